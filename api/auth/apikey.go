@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// apiKeySecretBytes is the number of random bytes in an API key's secret
+const apiKeySecretBytes = 32
+
+// NewAPIKeySecret generates the secret of an API key
+func NewAPIKeySecret() (string, error) {
+	b := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// FormatAPIKey joins the ID and secret of an API key into the key callers
+// present. Secrets never contain a dot, so the key splits at its last one.
+func FormatAPIKey(id, secret string) string {
+	return id + "." + secret
+}
+
+// ParseAPIKey splits a key callers present into its ID and secret
+func ParseAPIKey(key string) (id, secret string, ok bool) {
+	i := strings.LastIndex(key, ".")
+	if i <= 0 || i == len(key)-1 {
+		return "", "", false
+	}
+	return key[:i], key[i+1:], true
+}
+
+// HashSecret returns the hex SHA-256 of a secret, as it is stored
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// SecretMatches reports, in constant time, whether a secret has the stored
+// hash
+func SecretMatches(secret, hash string) bool {
+	if hash == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(HashSecret(secret)), []byte(hash)) == 1
+}
@@ -0,0 +1,29 @@
+package auth
+
+import "testing"
+
+func TestAPIKeyRoundTrip(t *testing.T) {
+	secret, err := NewAPIKeySecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// IDs may contain dots, secrets can't
+	id, got, ok := ParseAPIKey(FormatAPIKey("ci.deploy", secret))
+	if !ok || id != "ci.deploy" || got != secret {
+		t.Fatalf("key parsed to %q and %q, want ci.deploy and the secret", id, got)
+	}
+
+	hash := HashSecret(secret)
+	if !SecretMatches(secret, hash) {
+		t.Error("secret doesn't match its hash")
+	}
+	if SecretMatches(secret+"x", hash) || SecretMatches(secret, "") {
+		t.Error("secret matches another hash, or no hash at all")
+	}
+
+	for _, key := range []string{"", "nodot", ".secret", "id."} {
+		if _, _, ok := ParseAPIKey(key); ok {
+			t.Errorf("malformed key %q was parsed", key)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Package auth checks the credentials callers of the API present: API keys
+// and JWTs signed with HMAC or RSA
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken is returned for a JWT that is malformed, badly signed or
+// not valid at the time it is checked
+var ErrInvalidToken = errors.New("invalid token")
+
+// algorithms maps the JWT signing algorithms a JWTVerifier accepts to their
+// hashes
+var algorithms = map[string]crypto.Hash{
+	"HS256": crypto.SHA256,
+	"HS384": crypto.SHA384,
+	"HS512": crypto.SHA512,
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+}
+
+// JWTVerifier checks the signature and claims of JWTs. A token is only
+// accepted with an algorithm whose key is set, so an HMAC token can never be
+// checked against the RSA public key.
+type JWTVerifier struct {
+	// HMACSecret verifies HS256, HS384 and HS512 tokens
+	HMACSecret []byte
+	// RSAPublicKey verifies RS256, RS384 and RS512 tokens
+	RSAPublicKey *rsa.PublicKey
+
+	// Issuer and Audience, if set, must match the iss and aud claims
+	Issuer   string
+	Audience string
+
+	// Leeway is the clock skew allowed when checking exp and nbf
+	Leeway time.Duration
+}
+
+// Claims are the claims of a verified JWT
+type Claims struct {
+	Subject   string
+	Issuer    string
+	ExpiresAt time.Time
+}
+
+// jwtHeader is the JOSE header of a JWT
+type jwtHeader struct {
+	Alg string `json:"alg"`
+}
+
+// jwtClaims are the registered claims the verifier reads
+type jwtClaims struct {
+	Subject   string          `json:"sub"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *json.Number    `json:"exp"`
+	NotBefore *json.Number    `json:"nbf"`
+}
+
+// Verify checks a JWT at time now and returns its claims. Tokens need a
+// subject and an expiry.
+func (v *JWTVerifier) Verify(token string, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: expected three dot-separated parts", ErrInvalidToken)
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed header: %v", ErrInvalidToken, err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+	if err := v.verifySignature(header.Alg, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims: %v", ErrInvalidToken, err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: no sub claim", ErrInvalidToken)
+	}
+	if claims.ExpiresAt == nil {
+		return nil, fmt.Errorf("%w: no exp claim", ErrInvalidToken)
+	}
+	expiresAt, err := numericDate(*claims.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed exp claim", ErrInvalidToken)
+	}
+	if !now.Before(expiresAt.Add(v.Leeway)) {
+		return nil, fmt.Errorf("%w: expired", ErrInvalidToken)
+	}
+	if claims.NotBefore != nil {
+		notBefore, err := numericDate(*claims.NotBefore)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed nbf claim", ErrInvalidToken)
+		}
+		if now.Add(v.Leeway).Before(notBefore) {
+			return nil, fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+		}
+	}
+	if v.Issuer != "" && claims.Issuer != v.Issuer {
+		return nil, fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+	if v.Audience != "" && !hasAudience(claims.Audience, v.Audience) {
+		return nil, fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+
+	return &Claims{
+		Subject:   claims.Subject,
+		Issuer:    claims.Issuer,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// verifySignature checks the signature of the signed part of a token
+func (v *JWTVerifier) verifySignature(alg, signed string, signature []byte) error {
+	hash, ok := algorithms[alg]
+	if !ok {
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, alg)
+	}
+	h := hash.New()
+	h.Write([]byte(signed))
+
+	switch {
+	case strings.HasPrefix(alg, "HS") && len(v.HMACSecret) > 0:
+		mac := hmac.New(hash.New, v.HMACSecret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+		return nil
+	case strings.HasPrefix(alg, "RS") && v.RSAPublicKey != nil:
+		if err := rsa.VerifyPKCS1v15(v.RSAPublicKey, hash, h.Sum(nil), signature); err != nil {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: no key for algorithm %s", ErrInvalidToken, alg)
+}
+
+// decodeSegment decodes a base64url JSON segment of a token
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// numericDate reads a JWT NumericDate, seconds since the epoch
+func numericDate(n json.Number) (time.Time, error) {
+	f, err := n.Float64()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, int64(f*float64(time.Second))), nil
+}
+
+// hasAudience reports whether an aud claim, a string or a list of them,
+// names audience
+func hasAudience(aud json.RawMessage, audience string) bool {
+	var one string
+	if json.Unmarshal(aud, &one) == nil {
+		return one == audience
+	}
+	var many []string
+	if json.Unmarshal(aud, &many) == nil {
+		for _, a := range many {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseRSAPublicKey reads an RSA public key from PEM, as a PKIX public key,
+// a PKCS #1 public key or a certificate
+func ParseRSAPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("unexpected PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("not an RSA public key")
+	}
+	return rsaKey, nil
+}
@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+)
+
+// signToken builds a JWT with the claims, signed with alg by key: a byte
+// slice for HMAC, an RSA private key for RSA, or nothing for none
+func signToken(t *testing.T, alg string, key interface{}, claims map[string]interface{}) string {
+	t.Helper()
+
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := encode(map[string]string{"alg": alg, "typ": "JWT"}) + "." + encode(claims)
+
+	var signature []byte
+	switch key := key.(type) {
+	case []byte:
+		mac := hmac.New(algorithms[alg].New, key)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		h := algorithms[alg].New()
+		h.Write([]byte(signed))
+		var err error
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, key, algorithms[alg], h.Sum(nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestJWTVerify(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	secret := []byte("a shared secret of enough length")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)})
+
+	claims := func(extra map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{"sub": "alice", "exp": now.Add(time.Hour).Unix()}
+		for k, v := range extra {
+			c[k] = v
+		}
+		return c
+	}
+
+	hmacOnly := &JWTVerifier{HMACSecret: secret, Leeway: time.Minute}
+	rsaOnly := &JWTVerifier{RSAPublicKey: &rsaKey.PublicKey, Leeway: time.Minute}
+	strict := &JWTVerifier{HMACSecret: secret, Issuer: "https://issuer.example", Audience: "raft3d"}
+
+	cases := []struct {
+		name     string
+		verifier *JWTVerifier
+		token    string
+		valid    bool
+	}{
+		{"HS256", hmacOnly, signToken(t, "HS256", secret, claims(nil)), true},
+		{"HS512", hmacOnly, signToken(t, "HS512", secret, claims(nil)), true},
+		{"HS256 with another secret", hmacOnly, signToken(t, "HS256", []byte("not the secret"), claims(nil)), false},
+		{"RS256", rsaOnly, signToken(t, "RS256", rsaKey, claims(nil)), true},
+		{"RS384", rsaOnly, signToken(t, "RS384", rsaKey, claims(nil)), true},
+		{"RS256 with another key", rsaOnly, signToken(t, "RS256", otherKey, claims(nil)), false},
+
+		// An HMAC token keyed with the public key must not pass as RSA
+		{"HS256 keyed with the RSA public key", rsaOnly, signToken(t, "HS256", publicPEM, claims(nil)), false},
+		{"HS256 keyed with the DER public key", rsaOnly, signToken(t, "HS256", x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey), claims(nil)), false},
+		{"HS256 without an HMAC secret", rsaOnly, signToken(t, "HS256", secret, claims(nil)), false},
+		{"RS256 without an RSA key", hmacOnly, signToken(t, "RS256", rsaKey, claims(nil)), false},
+		{"none", hmacOnly, signToken(t, "none", nil, claims(nil)), false},
+		{"ES256", hmacOnly, signToken(t, "ES256", nil, claims(nil)), false},
+
+		{"expired within the leeway", hmacOnly, signToken(t, "HS256", secret, claims(map[string]interface{}{"exp": now.Add(-30 * time.Second).Unix()})), true},
+		{"expired past the leeway", hmacOnly, signToken(t, "HS256", secret, claims(map[string]interface{}{"exp": now.Add(-2 * time.Minute).Unix()})), false},
+		{"expiring now without leeway", strict, signToken(t, "HS256", secret, map[string]interface{}{
+			"sub": "alice", "exp": now.Unix(), "iss": "https://issuer.example", "aud": "raft3d",
+		}), false},
+		{"not yet valid within the leeway", hmacOnly, signToken(t, "HS256", secret, claims(map[string]interface{}{"nbf": now.Add(30 * time.Second).Unix()})), true},
+		{"not yet valid past the leeway", hmacOnly, signToken(t, "HS256", secret, claims(map[string]interface{}{"nbf": now.Add(2 * time.Minute).Unix()})), false},
+		{"without exp", hmacOnly, signToken(t, "HS256", secret, map[string]interface{}{"sub": "alice"}), false},
+		{"without sub", hmacOnly, signToken(t, "HS256", secret, map[string]interface{}{"exp": now.Add(time.Hour).Unix()}), false},
+
+		{"issuer and audience", strict, signToken(t, "HS256", secret, claims(map[string]interface{}{"iss": "https://issuer.example", "aud": "raft3d"})), true},
+		{"audience in a list", strict, signToken(t, "HS256", secret, claims(map[string]interface{}{"iss": "https://issuer.example", "aud": []string{"other", "raft3d"}})), true},
+		{"another issuer", strict, signToken(t, "HS256", secret, claims(map[string]interface{}{"iss": "https://evil.example", "aud": "raft3d"})), false},
+		{"without issuer", strict, signToken(t, "HS256", secret, claims(map[string]interface{}{"aud": "raft3d"})), false},
+		{"another audience", strict, signToken(t, "HS256", secret, claims(map[string]interface{}{"iss": "https://issuer.example", "aud": []string{"other"}})), false},
+		{"without audience", strict, signToken(t, "HS256", secret, claims(map[string]interface{}{"iss": "https://issuer.example"})), false},
+
+		{"malformed", hmacOnly, "not.a.token", false},
+		{"two parts", hmacOnly, "eyJhbGciOiJIUzI1NiJ9.e30", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.verifier.Verify(tc.token, now)
+			if !tc.valid {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Errorf("token was accepted as %+v, or failed with %v rather than %v", got, err, ErrInvalidToken)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("token was refused: %v", err)
+			}
+			if got.Subject != "alice" {
+				t.Errorf("token is for %q, want alice", got.Subject)
+			}
+		})
+	}
+}
+
+func TestParseRSAPublicKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkix, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, block := range []*pem.Block{
+		{Type: "PUBLIC KEY", Bytes: pkix},
+		{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey)},
+	} {
+		got, err := ParseRSAPublicKey(pem.EncodeToMemory(block))
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", block.Type, err)
+		}
+		if !got.Equal(&key.PublicKey) {
+			t.Errorf("%s parsed to another key", block.Type)
+		}
+	}
+
+	private := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if _, err := ParseRSAPublicKey(private); err == nil {
+		t.Error("a private key was taken for a public one")
+	}
+}
@@ -0,0 +1,306 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/auth"
+	"github.com/devadigapratham/raft3d/api/handlers"
+	raft3dv1 "github.com/devadigapratham/raft3d/api/proto/raft3d/v1"
+	"github.com/devadigapratham/raft3d/raft"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	testBootstrapKey = "bootstrap-key"
+	testJWTSecret    = "a shared secret of enough length"
+)
+
+// newTestAuthenticator starts a node whose API accepts the bootstrap key
+// and JWTs signed with testJWTSecret
+func newTestAuthenticator(t *testing.T) (*raft.Node, *handlers.Authenticator) {
+	t.Helper()
+
+	node := newTestNode(t)
+	return node, handlers.NewAuthenticator(node, testBootstrapKey, &auth.JWTVerifier{HMACSecret: []byte(testJWTSecret)})
+}
+
+// testJWT returns a bearer token for subject, signed with testJWTSecret,
+// that expires at exp
+func testJWT(t *testing.T, subject string, exp time.Time) string {
+	t.Helper()
+
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := encode(map[string]string{"alg": "HS256", "typ": "JWT"}) + "." +
+		encode(map[string]interface{}{"sub": subject, "exp": exp.Unix()})
+	mac := hmac.New(sha256.New, []byte(testJWTSecret))
+	mac.Write([]byte(signed))
+	return "Bearer " + signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// authRequest sends a request with an API key or, if it starts with
+// Bearer, an Authorization header
+func authRequest(router *gin.Engine, method, path, credential, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if strings.HasPrefix(credential, "Bearer ") {
+		req.Header.Set("Authorization", credential)
+	} else if credential != "" {
+		req.Header.Set("X-API-Key", credential)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// createTestKey creates an API key with a role through the API and returns
+// the key to present
+func createTestKey(t *testing.T, router *gin.Engine, body string) string {
+	t.Helper()
+
+	rec := authRequest(router, "POST", "/api/v1/auth/keys", testBootstrapKey, body)
+	if rec.Code != 201 {
+		t.Fatalf("failed to create key %s: %d %s", body, rec.Code, rec.Body)
+	}
+	var issued struct {
+		Key string `json:"api_key"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &issued); err != nil {
+		t.Fatal(err)
+	}
+	return issued.Key
+}
+
+func TestRoles(t *testing.T) {
+	node, authenticator := newTestAuthenticator(t)
+	router, err := SetupRouter(node, authenticator)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	viewer := createTestKey(t, router, `{"id": "viewer", "role": "viewer"}`)
+	operator := createTestKey(t, router, `{"id": "operator", "role": "operator"}`)
+	admin := createTestKey(t, router, `{"id": "admin", "role": "admin"}`)
+	id, _, _ := auth.ParseAPIKey(viewer)
+
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		credential string
+		body       string
+		status     int
+	}{
+		{"no credentials", "GET", "/api/v1/printers", "", "", 401},
+		{"unknown key", "GET", "/api/v1/printers", "nobody.secret", "", 401},
+		{"wrong secret", "GET", "/api/v1/printers", id + ".secret", "", 401},
+		{"malformed key", "GET", "/api/v1/printers", "nodot", "", 401},
+		{"not a bearer token", "GET", "/api/v1/printers", "Basic dXNlcjpwYXNz", "", 401},
+
+		{"viewer reads", "GET", "/api/v1/printers", viewer, "", 200},
+		{"viewer asks who it is", "GET", "/api/v1/auth/whoami", viewer, "", 200},
+		{"viewer writes", "POST", "/api/v1/printers", viewer, `{"id": "p1", "company": "Prusa", "model": "MK4"}`, 403},
+		{"viewer reads the digest", "GET", "/admin/digest", viewer, "", 403},
+		{"viewer lists keys", "GET", "/api/v1/auth/keys", viewer, "", 403},
+
+		{"operator writes", "POST", "/api/v1/printers", operator, `{"id": "p1", "company": "Prusa", "model": "MK4"}`, 201},
+		{"operator reads", "GET", "/api/v1/printers/p1", operator, "", 200},
+		{"operator reads the digest", "GET", "/admin/digest", operator, "", 403},
+		{"operator repairs invariants", "POST", "/admin/invariants/repair", operator, "", 403},
+		{"operator lists keys", "GET", "/api/v1/auth/keys", operator, "", 403},
+		{"operator creates a key", "POST", "/api/v1/auth/keys", operator, `{"id": "mine", "role": "admin"}`, 403},
+		{"operator binds a role", "PUT", "/api/v1/auth/roles/alice", operator, `{"role": "admin"}`, 403},
+
+		{"admin reads the digest", "GET", "/admin/digest", admin, "", 200},
+		{"admin checks invariants", "GET", "/admin/invariants", admin, "", 200},
+		{"admin lists keys", "GET", "/api/v1/auth/keys", admin, "", 200},
+		{"admin writes", "DELETE", "/api/v1/printers/p1", admin, "", 204},
+
+		{"unbound subject", "GET", "/api/v1/printers", testJWT(t, "alice", time.Now().Add(time.Hour)), "", 403},
+		{"bind alice", "PUT", "/api/v1/auth/roles/alice", admin, `{"role": "operator"}`, 200},
+		{"bound subject reads", "GET", "/api/v1/printers", testJWT(t, "alice", time.Now().Add(time.Hour)), "", 200},
+		{"bound subject writes", "POST", "/api/v1/printers", testJWT(t, "alice", time.Now().Add(time.Hour)), `{"id": "p2", "company": "Prusa", "model": "MK4"}`, 201},
+		{"bound subject lists keys", "GET", "/api/v1/auth/keys", testJWT(t, "alice", time.Now().Add(time.Hour)), "", 403},
+		{"expired token", "GET", "/api/v1/printers", testJWT(t, "alice", time.Now().Add(-time.Hour)), "", 401},
+		{"forged token", "GET", "/api/v1/printers", testJWT(t, "alice", time.Now().Add(time.Hour)) + "x", "", 401},
+	}
+	for _, tc := range cases {
+		rec := authRequest(router, tc.method, tc.path, tc.credential, tc.body)
+		if rec.Code != tc.status {
+			t.Errorf("%s: %s %s answered %d, want %d: %s", tc.name, tc.method, tc.path, rec.Code, tc.status, rec.Body)
+		}
+		// Only a caller that hasn't authenticated is asked to
+		challenged := rec.Header().Get("WWW-Authenticate") != ""
+		if challenged != (tc.status == 401) {
+			t.Errorf("%s: answered %d with WWW-Authenticate %q", tc.name, rec.Code, rec.Header().Get("WWW-Authenticate"))
+		}
+	}
+
+	rec := authRequest(router, "GET", "/api/v1/auth/whoami", viewer, "")
+	var principal handlers.Principal
+	if err := json.Unmarshal(rec.Body.Bytes(), &principal); err != nil {
+		t.Fatal(err)
+	}
+	if principal.Subject != "key:viewer" || principal.Role != "viewer" || principal.Method != "api_key" {
+		t.Errorf("viewer key is %+v", principal)
+	}
+}
+
+func TestAPIKeyRotationAndExpiry(t *testing.T) {
+	node, authenticator := newTestAuthenticator(t)
+	router, err := SetupRouter(node, authenticator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+
+	old := createTestKey(t, router, `{"id": "ci", "role": "operator"}`)
+	rec := authRequest(router, "POST", "/api/v1/auth/keys/ci/rotate", testBootstrapKey, `{"grace_period": "1h"}`)
+	if rec.Code != 200 {
+		t.Fatalf("failed to rotate key: %d %s", rec.Code, rec.Body)
+	}
+	var issued struct {
+		Key string `json:"api_key"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &issued); err != nil {
+		t.Fatal(err)
+	}
+
+	// The grace period is counted from when the rotation was applied, a
+	// little after now
+	for _, tc := range []struct {
+		name  string
+		key   string
+		at    time.Time
+		valid bool
+	}{
+		{"old secret during the grace period", old, now.Add(59 * time.Minute), true},
+		{"old secret after the grace period", old, now.Add(2 * time.Hour), false},
+		{"new secret during the grace period", issued.Key, now, true},
+		{"new secret after the grace period", issued.Key, now.Add(2 * time.Hour), true},
+	} {
+		if _, err := authenticator.Authenticate(tc.key, "", tc.at); (err == nil) != tc.valid {
+			t.Errorf("%s: valid %v, want %v (%v)", tc.name, err == nil, tc.valid, err)
+		}
+	}
+
+	// Rotating without a grace period ends the old secret at once
+	rec = authRequest(router, "POST", "/api/v1/auth/keys/ci/rotate", testBootstrapKey, "")
+	if rec.Code != 200 {
+		t.Fatalf("failed to rotate key: %d %s", rec.Code, rec.Body)
+	}
+	if _, err := authenticator.Authenticate(issued.Key, "", time.Now()); err == nil {
+		t.Error("secret replaced without a grace period is still valid")
+	}
+
+	expiresAt := now.Add(time.Hour).UTC().Format(time.RFC3339Nano)
+	expiring := createTestKey(t, router, `{"id": "temp", "role": "viewer", "expires_at": "`+expiresAt+`"}`)
+	if _, err := authenticator.Authenticate(expiring, "", now); err != nil {
+		t.Errorf("key is refused before it expires: %v", err)
+	}
+	if _, err := authenticator.Authenticate(expiring, "", now.Add(time.Hour)); err == nil {
+		t.Error("key is accepted when it expires")
+	}
+
+	past := now.Add(-time.Minute).UTC().Format(time.RFC3339Nano)
+	expired := createTestKey(t, router, `{"id": "stale", "role": "admin", "expires_at": "`+past+`"}`)
+	if rec := authRequest(router, "GET", "/api/v1/printers", expired, ""); rec.Code != 401 {
+		t.Errorf("expired key answered %d, want 401", rec.Code)
+	}
+}
+
+func TestGRPCAuth(t *testing.T) {
+	node, authenticator := newTestAuthenticator(t)
+	router, err := SetupRouter(node, authenticator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	viewer := createTestKey(t, router, `{"id": "viewer", "role": "viewer"}`)
+	operator := createTestKey(t, router, `{"id": "operator", "role": "operator"}`)
+
+	listener := bufconn.Listen(1 << 20)
+	server := SetupGRPCServer(node, authenticator)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	printers := raft3dv1.NewPrinterServiceClient(conn)
+
+	// withCredential returns a context carrying an API key or bearer token
+	withCredential := func(credential string) context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		t.Cleanup(cancel)
+		switch {
+		case strings.HasPrefix(credential, "Bearer "):
+			return metadata.AppendToOutgoingContext(ctx, "authorization", credential)
+		case credential != "":
+			return metadata.AppendToOutgoingContext(ctx, "x-api-key", credential)
+		}
+		return ctx
+	}
+	create := func(ctx context.Context, id string) error {
+		_, err := printers.CreatePrinter(ctx, &raft3dv1.CreatePrinterRequest{
+			Printer: &raft3dv1.Printer{Id: id, Company: "Prusa", Model: "MK4"},
+		})
+		return err
+	}
+	list := func(ctx context.Context) error {
+		_, err := printers.ListPrinters(ctx, &raft3dv1.ListPrintersRequest{})
+		return err
+	}
+
+	cases := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{"no credentials", func() error { return list(withCredential("")) }, codes.Unauthenticated},
+		{"unknown key", func() error { return list(withCredential("nobody.secret")) }, codes.Unauthenticated},
+		{"viewer reads", func() error { return list(withCredential(viewer)) }, codes.OK},
+		{"viewer writes", func() error { return create(withCredential(viewer), "p1") }, codes.PermissionDenied},
+		{"operator writes", func() error { return create(withCredential(operator), "p1") }, codes.OK},
+		{"unbound subject", func() error { return list(withCredential(testJWT(t, "alice", time.Now().Add(time.Hour)))) }, codes.PermissionDenied},
+		{"expired token", func() error { return list(withCredential(testJWT(t, "alice", time.Now().Add(-time.Hour)))) }, codes.Unauthenticated},
+		{"watch without credentials", func() error {
+			stream, err := raft3dv1.NewWatchServiceClient(conn).Watch(withCredential(""), &raft3dv1.WatchRequest{})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		}, codes.Unauthenticated},
+	}
+	for _, tc := range cases {
+		if code := status.Code(tc.call()); code != tc.code {
+			t.Errorf("%s: call failed with %s, want %s", tc.name, code, tc.code)
+		}
+	}
+}
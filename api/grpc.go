@@ -0,0 +1,24 @@
+package api
+
+import (
+	"github.com/devadigapratham/raft3d/api/handlers"
+	"github.com/devadigapratham/raft3d/raft"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// SetupGRPCServer sets up the gRPC API, described by the .proto files in
+// api/proto. Calls are authenticated by authenticator, or not at all if it
+// is nil. Server reflection is on, so tools like grpcurl need no copy of
+// them.
+func SetupGRPCServer(node *raft.Node, authenticator *handlers.Authenticator) *grpc.Server {
+	handler := handlers.NewGRPCServer(node, authenticator)
+
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(handler.UnaryInterceptor()),
+		grpc.StreamInterceptor(handler.StreamInterceptor()),
+	)
+	handler.Register(server)
+	reflection.Register(server)
+	return server
+}
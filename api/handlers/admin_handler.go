@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/devadigapratham/raft3d/raft"
+	"github.com/gin-gonic/gin"
+)
+
+// GetDigest returns the state digest at the last applied index, or at the
+// index given in the query string
+func (h *Handler) GetDigest(c *gin.Context) {
+	fsm := h.Node.GetFSM()
+
+	indexStr := c.Query("index")
+	if indexStr == "" {
+		appliedIndex, digest := fsm.Digest()
+		c.JSON(http.StatusOK, raft.DigestResponse{
+			AppliedIndex: appliedIndex,
+			Digest:       digest,
+		})
+		return
+	}
+
+	index, err := strconv.ParseUint(indexStr, 10, 64)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, "invalid index")
+		return
+	}
+
+	digest, ok := fsm.DigestAt(index)
+	if !ok {
+		respondProblem(c, codeNotFound, "digest not available at index")
+		return
+	}
+
+	c.JSON(http.StatusOK, raft.DigestResponse{
+		AppliedIndex: index,
+		Digest:       digest,
+	})
+}
+
+// CheckInvariants checks the FSM invariants on this node and reports the
+// violations along with the commands that would repair them
+func (h *Handler) CheckInvariants(c *gin.Context) {
+	report, err := h.Node.GetFSM().CheckInvariants()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// RepairInvariants checks the FSM invariants and proposes the corrective
+// commands through raft, unless dry_run is set. Each command is validated
+// by the FSM like any other, so one made stale by a concurrent change fails
+// on its own without affecting the rest.
+func (h *Handler) RepairInvariants(c *gin.Context) {
+	report, err := h.Node.GetFSM().CheckInvariants()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+	if dryRun {
+		c.JSON(http.StatusOK, gin.H{"report": report, "applied": 0, "errors": []string{}})
+		return
+	}
+
+	applied := 0
+	errs := make([]string, 0)
+	for _, cmd := range report.Repairs {
+		if err := h.apply(c, cmd); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		applied++
+	}
+	c.JSON(http.StatusOK, gin.H{"report": report, "applied": applied, "errors": errs})
+}
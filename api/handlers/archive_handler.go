@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/devadigapratham/raft3d/raft"
+	"github.com/gin-gonic/gin"
+)
+
+// GetArchivedPrintJobs returns a page of the print jobs this node archived
+// when they were purged, oldest first, filtered by printer_id, filament_id
+// and status. The next_cursor in the response continues the listing and is
+// absent on the last page.
+func (h *Handler) GetArchivedPrintJobs(c *gin.Context) {
+	filter := raft.ArchiveFilter{
+		PrinterID:  c.Query("printer_id"),
+		FilamentID: c.Query("filament_id"),
+		Status:     c.Query("status"),
+	}
+
+	var cursor int64
+	if s := c.Query("cursor"); s != "" {
+		var err error
+		cursor, err = strconv.ParseInt(s, 10, 64)
+		if err != nil || cursor < 0 {
+			respondProblem(c, codeInvalidRequest, "invalid cursor")
+			return
+		}
+	}
+	limit, err := parseLimit(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	jobs, next, err := h.Node.GetFSM().GetArchivedPrintJobs(filter, cursor, limit)
+	if errors.Is(err, raft.ErrInvalidCursor) {
+		respondProblem(c, codeInvalidRequest, "invalid cursor")
+		return
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := gin.H{"print_jobs": jobs}
+	if next != 0 {
+		response["next_cursor"] = strconv.FormatInt(next, 10)
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
+	"github.com/devadigapratham/raft3d/raft"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+)
+
+// parseLimit reads the page size of a paginated listing
+func parseLimit(c *gin.Context) (int, error) {
+	s := c.Query("limit")
+	if s == "" {
+		return defaultPageLimit, nil
+	}
+	limit, err := strconv.Atoi(s)
+	if err != nil || limit <= 0 || limit > maxPageLimit {
+		return 0, fmt.Errorf("invalid limit, expected 1 to %d", maxPageLimit)
+	}
+	return limit, nil
+}
+
+// parseAuditFilter reads the audit filter from the query string
+func parseAuditFilter(c *gin.Context) (raft.AuditFilter, error) {
+	filter := raft.AuditFilter{
+		Actor:        c.Query("actor"),
+		Command:      models.CommandType(c.Query("command")),
+		ResourceType: c.Query("resource_type"),
+		ResourceID:   c.Query("resource_id"),
+		Result:       c.Query("result"),
+	}
+	if filter.Result != "" && filter.Result != raft.AuditResultOK && filter.Result != raft.AuditResultError {
+		return filter, fmt.Errorf("invalid result, expected %s or %s", raft.AuditResultOK, raft.AuditResultError)
+	}
+
+	for _, p := range []struct {
+		name string
+		t    *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		s := c.Query(p.name)
+		if s == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s, expected RFC 3339", p.name)
+		}
+		*p.t = t
+	}
+	return filter, nil
+}
+
+// parseAuditCursor reads the index to continue listing audit records after
+func parseAuditCursor(c *gin.Context) (uint64, error) {
+	s := c.Query("cursor")
+	if s == "" {
+		return 0, nil
+	}
+	cursor, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	return cursor, nil
+}
+
+// GetAuditRecords returns a page of audit records matching the query
+// filters, oldest first. The next_cursor in the response continues the
+// listing and is absent on the last page.
+func (h *Handler) GetAuditRecords(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+	cursor, err := parseAuditCursor(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	limit, err := parseLimit(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	records, next, err := h.Node.GetFSM().GetAuditRecords(filter, cursor, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := gin.H{"records": records}
+	if next != 0 {
+		response["next_cursor"] = strconv.FormatUint(next, 10)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ExportAuditRecords streams every audit record matching the query filters
+// as JSON lines
+func (h *Handler) ExportAuditRecords(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+	cursor, err := parseAuditCursor(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", `attachment; filename="audit.jsonl"`)
+	c.Status(http.StatusOK)
+
+	enc := json.NewEncoder(c.Writer)
+	err = h.Node.GetFSM().ForEachAuditRecord(filter, cursor, func(record *raft.AuditRecord) bool {
+		return enc.Encode(record) == nil
+	})
+	if err != nil {
+		// The status is already sent, so all that is left is to log it
+		c.Error(err)
+	}
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/auth"
+	"github.com/devadigapratham/raft3d/api/models"
+	"github.com/devadigapratham/raft3d/api/openapi"
+	"github.com/devadigapratham/raft3d/raft"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// apiKeyHeader carries the API key a caller authenticates with
+	apiKeyHeader = "X-API-Key"
+	// authorizationHeader carries the JWT a caller authenticates with, as
+	// a bearer token
+	authorizationHeader = "Authorization"
+
+	// Context keys
+	contextPrincipal = "principal"
+)
+
+// How a principal authenticated
+const (
+	authMethodAPIKey    = "api_key"
+	authMethodJWT       = "jwt"
+	authMethodBootstrap = "bootstrap"
+	authMethodNone      = "none"
+)
+
+// bootstrapSubject names callers using the bootstrap key
+const bootstrapSubject = "bootstrap"
+
+var (
+	// errUnauthenticated is returned when a caller presents no credentials
+	// or ones that aren't valid
+	errUnauthenticated = errors.New("unauthenticated")
+	// errForbidden is returned when a caller's role doesn't allow a request
+	errForbidden = errors.New("forbidden")
+)
+
+// Principal is an authenticated caller
+type Principal struct {
+	Subject string      `json:"subject"`
+	Role    models.Role `json:"role"`
+	Method  string      `json:"method"`
+}
+
+// Authenticator checks the credentials of callers against the API keys and
+// role bindings in the FSM, so every node can authenticate on its own
+type Authenticator struct {
+	Node *raft.Node
+
+	// BootstrapKey, if set, is an admin key kept out of the FSM, for
+	// creating the first keys and for nodes calling each other
+	BootstrapKey string
+
+	// JWT verifies bearer tokens, which are refused if it is nil
+	JWT *auth.JWTVerifier
+}
+
+// NewAuthenticator creates a new Authenticator
+func NewAuthenticator(node *raft.Node, bootstrapKey string, jwt *auth.JWTVerifier) *Authenticator {
+	return &Authenticator{
+		Node:         node,
+		BootstrapKey: bootstrapKey,
+		JWT:          jwt,
+	}
+}
+
+// Authenticate returns who presents an API key or an Authorization header
+// at time now. An API key takes precedence.
+func (a *Authenticator) Authenticate(apiKey, authorization string, now time.Time) (*Principal, error) {
+	if apiKey != "" {
+		return a.authenticateAPIKey(apiKey, now)
+	}
+	if authorization == "" {
+		return nil, fmt.Errorf("%w: an API key or bearer token is required", errUnauthenticated)
+	}
+
+	scheme, token, _ := strings.Cut(authorization, " ")
+	if !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, fmt.Errorf("%w: expected a bearer token", errUnauthenticated)
+	}
+	return a.authenticateJWT(strings.TrimSpace(token), now)
+}
+
+// authenticateAPIKey checks an API key. After a rotation the previous
+// secret stays valid until its grace period ends.
+func (a *Authenticator) authenticateAPIKey(apiKey string, now time.Time) (*Principal, error) {
+	if a.BootstrapKey != "" && subtle.ConstantTimeCompare([]byte(apiKey), []byte(a.BootstrapKey)) == 1 {
+		return &Principal{Subject: bootstrapSubject, Role: models.RoleAdmin, Method: authMethodBootstrap}, nil
+	}
+
+	id, secret, ok := auth.ParseAPIKey(apiKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed API key", errUnauthenticated)
+	}
+	key, ok := a.Node.GetFSM().GetAPIKey(id)
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid API key", errUnauthenticated)
+	}
+	if key.ExpiresAt != nil && !now.Before(*key.ExpiresAt) {
+		return nil, fmt.Errorf("%w: API key %s has expired", errUnauthenticated, id)
+	}
+
+	valid := auth.SecretMatches(secret, key.SecretHash)
+	if !valid && key.PreviousExpiresAt != nil && now.Before(*key.PreviousExpiresAt) {
+		valid = auth.SecretMatches(secret, key.PreviousSecretHash)
+	}
+	if !valid {
+		return nil, fmt.Errorf("%w: invalid API key", errUnauthenticated)
+	}
+	return &Principal{Subject: "key:" + key.ID, Role: key.Role, Method: authMethodAPIKey}, nil
+}
+
+// authenticateJWT checks a JWT. Its subject gets the role bound to it.
+func (a *Authenticator) authenticateJWT(token string, now time.Time) (*Principal, error) {
+	if a.JWT == nil {
+		return nil, fmt.Errorf("%w: bearer tokens are not accepted", errUnauthenticated)
+	}
+	claims, err := a.JWT.Verify(token, now)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errUnauthenticated, err)
+	}
+	binding, ok := a.Node.GetFSM().GetRoleBinding(claims.Subject)
+	if !ok {
+		return nil, fmt.Errorf("%w: no role is bound to %s", errForbidden, claims.Subject)
+	}
+	return &Principal{Subject: claims.Subject, Role: binding.Role, Method: authMethodJWT}, nil
+}
+
+// authorize checks that a principal's role allows what needs the required
+// role
+func authorize(p *Principal, required models.Role) error {
+	if !p.Role.Allows(required) {
+		return fmt.Errorf("%w: the %s role is required, %s has %s", errForbidden, required, p.Subject, p.Role)
+	}
+	return nil
+}
+
+// requiredRole returns the least role an operation needs. Routes without an
+// operation, which answer 404, need any role.
+func requiredRole(op *openapi.Operation) models.Role {
+	if op == nil || op.RequiredRole == "" {
+		return models.RoleViewer
+	}
+	return models.Role(op.RequiredRole)
+}
+
+// Middleware authenticates every request and checks the caller's role
+// against the role the API description requires for its route. The
+// caller's subject becomes the actor of the commands it applies.
+func (a *Authenticator) Middleware(doc *openapi.Document) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := a.Authenticate(c.GetHeader(apiKeyHeader), c.GetHeader(authorizationHeader), time.Now())
+		if err == nil {
+			err = authorize(principal, requiredRole(doc.Operation(c.Request.Method, c.FullPath())))
+		}
+		if err != nil {
+			if errors.Is(err, errUnauthenticated) {
+				c.Header("WWW-Authenticate", `Bearer realm="raft3d"`)
+			}
+			respondError(c, err)
+			return
+		}
+
+		c.Set(contextPrincipal, principal)
+		c.Set(contextActor, principal.Subject)
+		c.Next()
+	}
+}
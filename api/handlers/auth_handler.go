@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/auth"
+	"github.com/devadigapratham/raft3d/api/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// apiKeyRequest is the body of a request creating an API key
+type apiKeyRequest struct {
+	ID          string      `json:"id"`
+	Description string      `json:"description"`
+	Role        models.Role `json:"role"`
+	ExpiresAt   *time.Time  `json:"expires_at"`
+}
+
+// rotateRequest is the body of a request rotating an API key
+type rotateRequest struct {
+	// GracePeriod is how long the old secret keeps working, such as 1h
+	GracePeriod string `json:"grace_period"`
+}
+
+// roleBindingRequest is the body of a request binding a role to a subject
+type roleBindingRequest struct {
+	Role models.Role `json:"role"`
+}
+
+// issuedAPIKey is an API key together with the key callers present, which
+// is only ever shown when it is created or rotated
+type issuedAPIKey struct {
+	*models.APIKey
+	Key string `json:"api_key"`
+}
+
+// newAPIKeySecret generates a secret and returns it with its hash
+func newAPIKeySecret() (secret, hash string, err error) {
+	secret, err = auth.NewAPIKeySecret()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate secret: %v", err)
+	}
+	return secret, auth.HashSecret(secret), nil
+}
+
+// CreateAPIKey creates an API key and responds with the key to present,
+// which can't be read again
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	var req apiKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+	if req.ID == "" {
+		req.ID = uuid.New().String()
+	}
+	if err := models.ValidateID(req.ID); err != nil {
+		respondProblem(c, codeValidationFailed, err.Error())
+		return
+	}
+	if !models.IsValidRole(req.Role) {
+		respondProblem(c, codeValidationFailed, "invalid role, expected viewer, operator or admin")
+		return
+	}
+
+	secret, hash, err := newAPIKeySecret()
+	if err != nil {
+		respondProblem(c, codeInternal, err.Error())
+		return
+	}
+
+	// Create the command
+	cmd := &models.Command{
+		Type: models.CreateAPIKey,
+		APIKey: &models.APIKey{
+			ID:          req.ID,
+			Description: req.Description,
+			Role:        req.Role,
+			SecretHash:  hash,
+			ExpiresAt:   req.ExpiresAt,
+		},
+	}
+
+	// Apply the command
+	if err := h.apply(c, cmd); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	key, ok := h.Node.GetFSM().GetAPIKey(req.ID)
+	if !ok {
+		respondProblem(c, codeInternal, "api key vanished after creation")
+		return
+	}
+	setETag(c, key.Version)
+	c.JSON(http.StatusCreated, issuedAPIKey{APIKey: key.Redacted(), Key: auth.FormatAPIKey(key.ID, secret)})
+}
+
+// GetAPIKeys returns all API keys, without their secrets
+func (h *Handler) GetAPIKeys(c *gin.Context) {
+	keys := h.Node.GetFSM().GetAPIKeys()
+	redacted := make([]*models.APIKey, 0, len(keys))
+	for _, key := range keys {
+		redacted = append(redacted, key.Redacted())
+	}
+	c.JSON(http.StatusOK, redacted)
+}
+
+// GetAPIKey returns an API key by ID, without its secret
+func (h *Handler) GetAPIKey(c *gin.Context) {
+	key, ok := h.Node.GetFSM().GetAPIKey(c.Param("id"))
+	if !ok {
+		respondProblem(c, codeNotFound, "api key not found")
+		return
+	}
+
+	setETag(c, key.Version)
+	c.JSON(http.StatusOK, key.Redacted())
+}
+
+// RotateAPIKey gives an API key a new secret, honouring If-Match, and
+// responds with the new key to present. The old one keeps working for the
+// grace period.
+func (h *Handler) RotateAPIKey(c *gin.Context) {
+	keyID := c.Param("id")
+	var req rotateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondProblem(c, codeInvalidRequest, err.Error())
+			return
+		}
+	}
+	var grace time.Duration
+	if req.GracePeriod != "" {
+		var err error
+		if grace, err = time.ParseDuration(req.GracePeriod); err != nil || grace < 0 {
+			respondProblem(c, codeValidationFailed, "invalid grace_period, expected a duration such as 1h")
+			return
+		}
+	}
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	// Check if the key exists
+	if _, ok := h.Node.GetFSM().GetAPIKey(keyID); !ok {
+		respondProblem(c, codeNotFound, "api key not found")
+		return
+	}
+
+	secret, hash, err := newAPIKeySecret()
+	if err != nil {
+		respondProblem(c, codeInternal, err.Error())
+		return
+	}
+
+	// Create the command
+	cmd := &models.Command{
+		Type:            models.RotateAPIKey,
+		APIKey:          &models.APIKey{ID: keyID, SecretHash: hash},
+		GraceSeconds:    int64(grace / time.Second),
+		ExpectedVersion: expectedVersion,
+	}
+
+	// Apply the command
+	if err := h.apply(c, cmd); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	key, ok := h.Node.GetFSM().GetAPIKey(keyID)
+	if !ok {
+		respondProblem(c, codeNotFound, "api key not found")
+		return
+	}
+	setETag(c, key.Version)
+	c.JSON(http.StatusOK, issuedAPIKey{APIKey: key.Redacted(), Key: auth.FormatAPIKey(key.ID, secret)})
+}
+
+// DeleteAPIKey deletes an API key, honouring If-Match. It stops working at
+// once.
+func (h *Handler) DeleteAPIKey(c *gin.Context) {
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	// Create the command
+	cmd := &models.Command{
+		Type:            models.DeleteAPIKey,
+		KeyID:           c.Param("id"),
+		ExpectedVersion: expectedVersion,
+	}
+
+	// Apply the command
+	if err := h.apply(c, cmd); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetRoleBindings returns the roles bound to JWT subjects
+func (h *Handler) GetRoleBindings(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Node.GetFSM().GetRoleBindings())
+}
+
+// SetRoleBinding binds a role to the JWT subject in the path, honouring
+// If-Match
+func (h *Handler) SetRoleBinding(c *gin.Context) {
+	var req roleBindingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+	if !models.IsValidRole(req.Role) {
+		respondProblem(c, codeValidationFailed, "invalid role, expected viewer, operator or admin")
+		return
+	}
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	// Create the command
+	subject := c.Param("subject")
+	cmd := &models.Command{
+		Type:            models.SetRoleBinding,
+		RoleBinding:     &models.RoleBinding{Subject: subject, Role: req.Role},
+		ExpectedVersion: expectedVersion,
+	}
+
+	// Apply the command
+	if err := h.apply(c, cmd); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	binding, ok := h.Node.GetFSM().GetRoleBinding(subject)
+	if !ok {
+		respondProblem(c, codeNotFound, "role binding not found")
+		return
+	}
+	setETag(c, binding.Version)
+	c.JSON(http.StatusOK, binding)
+}
+
+// DeleteRoleBinding removes the role bound to the JWT subject in the path,
+// honouring If-Match
+func (h *Handler) DeleteRoleBinding(c *gin.Context) {
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	// Create the command
+	cmd := &models.Command{
+		Type:            models.DeleteRoleBinding,
+		Subject:         c.Param("subject"),
+		ExpectedVersion: expectedVersion,
+	}
+
+	// Apply the command
+	if err := h.apply(c, cmd); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// WhoAmI returns the caller and its role. With authentication off every
+// caller is an admin.
+func (h *Handler) WhoAmI(c *gin.Context) {
+	if principal, ok := c.Get(contextPrincipal); ok {
+		c.JSON(http.StatusOK, principal)
+		return
+	}
+	c.JSON(http.StatusOK, Principal{Subject: actor(c), Role: models.RoleAdmin, Method: authMethodNone})
+}
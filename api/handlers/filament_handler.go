@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/devadigapratham/raft3d/api/models"
+	"github.com/devadigapratham/raft3d/raft"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -12,43 +15,351 @@ import (
 func (h *Handler) CreateFilament(c *gin.Context) {
 	var filament models.Filament
 	if err := c.ShouldBindJSON(&filament); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondProblem(c, codeInvalidRequest, err.Error())
 		return
 	}
 
-	// Validate filament type
-	if !models.IsValidFilamentType(filament.Type) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filament type"})
+	if err := prepareFilament(&filament); err != nil {
+		respondProblem(c, codeValidationFailed, err.Error())
 		return
 	}
 
-	// Generate an ID if not provided
-	if filament.ID == "" {
-		filament.ID = uuid.New().String()
+	// Create the command
+	cmd := &models.Command{
+		Type:     models.AddFilament,
+		Filament: &filament,
 	}
 
-	// If remaining weight not specified, set it to total weight
-	if filament.RemainingWeightInGrams == 0 {
-		filament.RemainingWeightInGrams = filament.TotalWeightInGrams
+	// Apply the command
+	if err := h.apply(c, cmd); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Respond with the filament as stored, which carries its version
+	if stored, ok := h.Node.GetFSM().GetFilament(filament.ID); ok {
+		filament = *stored
+	}
+	setETag(c, filament.Version)
+	c.JSON(http.StatusCreated, filament)
+}
+
+// ReplaceFilament creates or replaces the filament with the ID in the path,
+// honouring If-Match. A replaced filament keeps its remaining weight.
+func (h *Handler) ReplaceFilament(c *gin.Context) {
+	var filament models.Filament
+	if err := c.ShouldBindJSON(&filament); err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	if err := pathID(c, &filament.ID); err != nil {
+		respondProblem(c, codeValidationFailed, err.Error())
+		return
+	}
+	if err := prepareFilament(&filament); err != nil {
+		respondProblem(c, codeValidationFailed, err.Error())
+		return
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
 	}
 
 	// Create the command
 	cmd := &models.Command{
-		Type:     models.AddFilament,
-		Filament: &filament,
+		Type:            models.UpsertFilament,
+		Filament:        &filament,
+		ExpectedVersion: expectedVersion,
 	}
 
 	// Apply the command
-	if err := h.Node.Apply(cmd); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.apply(c, cmd); err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, filament)
+	// Respond with the filament as stored, which carries its version
+	if stored, ok := h.Node.GetFSM().GetFilament(filament.ID); ok {
+		filament = *stored
+	}
+	setETag(c, filament.Version)
+	c.JSON(http.StatusOK, filament)
 }
 
-// GetFilaments returns all filaments
+// GetFilaments returns a page of filaments that pass the filter,
+// optionally as they were at an earlier point given by as_of_index or
+// as_of_time
 func (h *Handler) GetFilaments(c *gin.Context) {
-	filaments := h.Node.GetFSM().GetFilaments()
-	c.JSON(http.StatusOK, filaments)
+	filter, err := parseFilter(c, raft.ResourceFilaments)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	index, asOf, err := h.asOfIndex(c)
+	if err != nil {
+		respondAsOfError(c, err)
+		return
+	}
+	if asOf {
+		filaments, err := h.Node.GetFSM().GetFilamentsAsOf(index)
+		if err != nil {
+			respondAsOfError(c, err)
+			return
+		}
+		respondList(c, raft.FilterResources(filaments, filter), filamentSortFields)
+		return
+	}
+
+	filaments := h.Node.GetFSM().QueryFilaments(filter)
+	respondList(c, filaments, filamentSortFields)
+}
+
+// GetFilament returns a filament by ID, optionally as it was at an earlier
+// point given by as_of_index or as_of_time
+func (h *Handler) GetFilament(c *gin.Context) {
+	index, asOf, err := h.asOfIndex(c)
+	if err != nil {
+		respondAsOfError(c, err)
+		return
+	}
+	var filament *models.Filament
+	exists := false
+	if asOf {
+		filament, err = h.Node.GetFSM().GetFilamentAsOf(c.Param("id"), index)
+		if err != nil {
+			respondAsOfError(c, err)
+			return
+		}
+		exists = filament != nil
+	} else {
+		filament, exists = h.Node.GetFSM().GetFilament(c.Param("id"))
+	}
+	if !exists {
+		respondProblem(c, codeNotFound, "filament not found")
+		return
+	}
+
+	setETag(c, filament.Version)
+	c.JSON(http.StatusOK, filament)
+}
+
+// UpdateFilament changes the fields of a filament given in the request body,
+// honouring If-Match. The remaining weight is changed through adjustments.
+func (h *Handler) UpdateFilament(c *gin.Context) {
+	filamentID := c.Param("id")
+	patch, err := readPatch(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	// Check if the filament exists
+	if _, exists := h.Node.GetFSM().GetFilament(filamentID); !exists {
+		respondProblem(c, codeNotFound, "filament not found")
+		return
+	}
+
+	// Create the command
+	cmd := &models.Command{
+		Type:            models.UpdateFilament,
+		FilamentID:      filamentID,
+		Patch:           patch,
+		ExpectedVersion: expectedVersion,
+	}
+
+	// Apply the command
+	if err := h.apply(c, cmd); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Get the updated filament
+	filament, exists := h.Node.GetFSM().GetFilament(filamentID)
+	if exists {
+		setETag(c, filament.Version)
+	}
+	c.JSON(http.StatusOK, filament)
+}
+
+// DeleteFilament deletes a filament, honouring If-Match. It fails while
+// Queued or Running jobs reserve the filament, unless cascade=cancel cancels
+// them in the same log entry.
+func (h *Handler) DeleteFilament(c *gin.Context) {
+	filamentID := c.Param("id")
+	cascade := c.Query("cascade")
+	if cascade != "" && cascade != raft.CascadeCancel {
+		respondProblem(c, codeInvalidRequest, "invalid cascade, expected cancel")
+		return
+	}
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	// Check if the filament exists
+	if _, exists := h.Node.GetFSM().GetFilament(filamentID); !exists {
+		respondProblem(c, codeNotFound, "filament not found")
+		return
+	}
+
+	// Create the command
+	cmd := &models.Command{
+		Type:            models.DeleteFilament,
+		FilamentID:      filamentID,
+		Cascade:         cascade,
+		ExpectedVersion: expectedVersion,
+	}
+
+	// Apply the command
+	if err := h.apply(c, cmd); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AdjustFilamentWeight adds delta_grams, which may be negative, to the
+// remaining weight of a filament, honouring If-Match. A reason is required
+// and kept in the audit log.
+func (h *Handler) AdjustFilamentWeight(c *gin.Context) {
+	filamentID := c.Param("id")
+	var req struct {
+		DeltaGrams int    `json:"delta_grams"`
+		Reason     string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+	if req.DeltaGrams == 0 {
+		respondProblem(c, codeValidationFailed, "delta_grams must not be 0")
+		return
+	}
+	if req.Reason == "" {
+		respondProblem(c, codeValidationFailed, "reason is required")
+		return
+	}
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	// Check if the filament exists
+	if _, exists := h.Node.GetFSM().GetFilament(filamentID); !exists {
+		respondProblem(c, codeNotFound, "filament not found")
+		return
+	}
+
+	// Create the command
+	cmd := &models.Command{
+		Type:            models.AdjustFilamentWeight,
+		FilamentID:      filamentID,
+		DeltaGrams:      req.DeltaGrams,
+		Reason:          req.Reason,
+		ExpectedVersion: expectedVersion,
+	}
+
+	// Apply the command
+	if err := h.apply(c, cmd); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Get the updated filament
+	filament, exists := h.Node.GetFSM().GetFilament(filamentID)
+	if exists {
+		setETag(c, filament.Version)
+	}
+	c.JSON(http.StatusOK, filament)
+}
+
+// GetFilamentAdjustments returns the weight adjustments of a filament,
+// oldest first, a page at a time
+func (h *Handler) GetFilamentAdjustments(c *gin.Context) {
+	limit, err := parseLimit(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+	cursor, err := parseAuditCursor(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	adjustments, next, err := h.Node.GetFSM().GetFilamentAdjustments(c.Param("id"), cursor, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := gin.H{"adjustments": adjustments}
+	if next != 0 {
+		response["next_cursor"] = strconv.FormatUint(next, 10)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetFilamentPrintJobs returns a page of the print jobs using a filament,
+// filtered and sorted like the list of all print jobs
+func (h *Handler) GetFilamentPrintJobs(c *gin.Context) {
+	filamentID := c.Param("id")
+	filter, err := parseFilter(c, raft.ResourcePrintJobs)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+	if err := filter.Equal("filament_id", filamentID); err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	if _, exists := h.Node.GetFSM().GetFilament(filamentID); !exists {
+		respondProblem(c, codeNotFound, "filament not found")
+		return
+	}
+	h.listPrintJobs(c, filter)
+}
+
+// GetFilamentReservations returns how much of a filament Queued and Running
+// jobs have reserved and how much is free for new jobs
+func (h *Handler) GetFilamentReservations(c *gin.Context) {
+	reservations, exists := h.Node.GetFSM().GetFilamentReservations(c.Param("id"))
+	if !exists {
+		respondProblem(c, codeNotFound, "filament not found")
+		return
+	}
+	c.JSON(http.StatusOK, reservations)
+}
+
+// prepareFilament validates a new filament and fills in what a client may
+// leave out
+func prepareFilament(filament *models.Filament) error {
+	// Validate filament type
+	if !models.IsValidFilamentType(filament.Type) {
+		return errors.New("invalid filament type")
+	}
+
+	// Generate an ID if not provided
+	if filament.ID == "" {
+		filament.ID = uuid.New().String()
+	}
+
+	// If remaining weight not specified, set it to total weight
+	if filament.RemainingWeightInGrams == 0 {
+		filament.RemainingWeightInGrams = filament.TotalWeightInGrams
+	}
+	return models.ValidateID(filament.ID)
 }
@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
+	raft3dv1 "github.com/devadigapratham/raft3d/api/proto/raft3d/v1"
+	"github.com/devadigapratham/raft3d/raft"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata of the gRPC API
+const (
+	// grpcLeaderKey and grpcLeaderIDKey name the leader a follower refused
+	// a write for, in the response metadata and the error details
+	grpcLeaderKey   = "raft3d-leader"
+	grpcLeaderIDKey = "raft3d-leader-id"
+
+	// grpcErrorDomain is the domain of the ErrorInfo details of errors,
+	// whose reason is the problem code the REST API would report
+	grpcErrorDomain = "raft3d"
+)
+
+// grpcCodes maps the codes of problems to the gRPC status codes they are
+// reported as
+var grpcCodes = map[string]codes.Code{
+	codeInvalidRequest:       codes.InvalidArgument,
+	codeUnauthenticated:      codes.Unauthenticated,
+	codeForbidden:            codes.PermissionDenied,
+	codeValidationFailed:     codes.InvalidArgument,
+	codeNotFound:             codes.NotFound,
+	codeAlreadyExists:        codes.AlreadyExists,
+	codeInUse:                codes.FailedPrecondition,
+	codeJobNotFinished:       codes.FailedPrecondition,
+	codeInvalidTransition:    codes.FailedPrecondition,
+	codeInsufficientFilament: codes.FailedPrecondition,
+	codeVersionConflict:      codes.Aborted,
+	codePreconditionFailed:   codes.FailedPrecondition,
+	codeHistoryUnavailable:   codes.OutOfRange,
+	codeResyncRequired:       codes.OutOfRange,
+	codeNotLeader:            codes.Unavailable,
+	codeUnavailable:          codes.Unavailable,
+	codeInternal:             codes.Internal,
+}
+
+// grpcWriteMethods are the RPCs that apply commands, which only the leader
+// serves
+var grpcWriteMethods = map[string]bool{
+	raft3dv1.PrinterService_CreatePrinter_FullMethodName:         true,
+	raft3dv1.PrinterService_UpdatePrinter_FullMethodName:         true,
+	raft3dv1.PrinterService_DeletePrinter_FullMethodName:         true,
+	raft3dv1.FilamentService_CreateFilament_FullMethodName:       true,
+	raft3dv1.FilamentService_UpdateFilament_FullMethodName:       true,
+	raft3dv1.FilamentService_DeleteFilament_FullMethodName:       true,
+	raft3dv1.FilamentService_AdjustFilamentWeight_FullMethodName: true,
+	raft3dv1.PrintJobService_CreatePrintJob_FullMethodName:       true,
+	raft3dv1.PrintJobService_UpdatePrintJobStatus_FullMethodName: true,
+	raft3dv1.PrintJobService_CancelPrintJob_FullMethodName:       true,
+	raft3dv1.PrintJobService_DeletePrintJob_FullMethodName:       true,
+}
+
+// GRPCServer serves the gRPC API. It reads from the same FSM and applies
+// the same commands, with the same checks, as the REST handlers.
+type GRPCServer struct {
+	raft3dv1.UnimplementedPrinterServiceServer
+	raft3dv1.UnimplementedFilamentServiceServer
+	raft3dv1.UnimplementedPrintJobServiceServer
+	raft3dv1.UnimplementedWatchServiceServer
+	raft3dv1.UnimplementedClusterServiceServer
+
+	Node *raft.Node
+
+	// Auth authenticates calls, which aren't authenticated if it is nil
+	Auth *Authenticator
+}
+
+// NewGRPCServer creates a new GRPCServer
+func NewGRPCServer(node *raft.Node, authenticator *Authenticator) *GRPCServer {
+	return &GRPCServer{
+		Node: node,
+		Auth: authenticator,
+	}
+}
+
+// Register registers the services of the gRPC API with a server
+func (s *GRPCServer) Register(server *grpc.Server) {
+	raft3dv1.RegisterPrinterServiceServer(server, s)
+	raft3dv1.RegisterFilamentServiceServer(server, s)
+	raft3dv1.RegisterPrintJobServiceServer(server, s)
+	raft3dv1.RegisterWatchServiceServer(server, s)
+	raft3dv1.RegisterClusterServiceServer(server, s)
+}
+
+// grpcCall is the identity of a call, stamped on the commands it applies
+type grpcCall struct {
+	requestID string
+	actor     string
+	clientIP  string
+}
+
+type grpcCallKey struct{}
+
+// UnaryInterceptor tags every call with a request ID, taken from the
+// x-request-id metadata or generated, and echoes it in the response
+// metadata. Calls are authenticated like the REST API's requests: writes
+// need the operator role and reads the viewer role. Writes on a follower
+// are refused with the leader's address, like RaftLeaderMiddleware does
+// for REST.
+func (s *GRPCServer) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		call := &grpcCall{
+			requestID: firstMetadata(md, requestIDHeader),
+			actor:     claimedActor(firstMetadata(md, actorHeader)),
+		}
+		if call.requestID == "" || len(call.requestID) > 128 {
+			call.requestID = uuid.New().String()
+		}
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+				call.clientIP = host
+			}
+		}
+		grpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(requestIDHeader), call.requestID))
+
+		required := models.RoleViewer
+		if grpcWriteMethods[info.FullMethod] {
+			required = models.RoleOperator
+		}
+		principal, err := s.authenticate(md, required)
+		if err != nil {
+			return nil, err
+		}
+		if principal != nil {
+			call.actor = principal.Subject
+		}
+
+		if grpcWriteMethods[info.FullMethod] && !s.Node.Leader() {
+			leader := map[string]string{
+				grpcLeaderKey:   s.Node.LeaderAddress(),
+				grpcLeaderIDKey: s.Node.LeaderID(),
+			}
+			grpc.SetHeader(ctx, metadata.New(leader))
+			return nil, grpcProblem(codeNotLeader, "writes go to the leader", leader)
+		}
+		return handler(context.WithValue(ctx, grpcCallKey{}, call), req)
+	}
+}
+
+// StreamInterceptor authenticates streaming calls, which only read and need
+// the viewer role
+func (s *GRPCServer) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		md, _ := metadata.FromIncomingContext(stream.Context())
+		if _, err := s.authenticate(md, models.RoleViewer); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+// authenticate checks the credentials in a call's metadata, x-api-key or
+// authorization, against the required role. It returns nil without an
+// Authenticator.
+func (s *GRPCServer) authenticate(md metadata.MD, required models.Role) (*Principal, error) {
+	if s.Auth == nil {
+		return nil, nil
+	}
+	principal, err := s.Auth.Authenticate(firstMetadata(md, apiKeyHeader), firstMetadata(md, authorizationHeader), time.Now())
+	if err == nil {
+		err = authorize(principal, required)
+	}
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return principal, nil
+}
+
+// firstMetadata returns the first value of a metadata key, empty if there
+// is none
+func firstMetadata(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// apply stamps a command with the identity of the call and applies it
+// through raft
+func (s *GRPCServer) apply(ctx context.Context, cmd *models.Command) error {
+	if call, ok := ctx.Value(grpcCallKey{}).(*grpcCall); ok {
+		cmd.Actor = call.actor
+		cmd.ClientIP = call.clientIP
+		cmd.RequestID = call.requestID
+	}
+	return s.Node.Apply(cmd)
+}
+
+// grpcProblem returns the gRPC status of the problem of a code, described
+// by detail. The code and any metadata go in its ErrorInfo details.
+func grpcProblem(code, detail string, md map[string]string) error {
+	grpcCode, ok := grpcCodes[code]
+	if !ok {
+		code, grpcCode = codeInternal, codes.Internal
+	}
+
+	st := status.New(grpcCode, detail)
+	if withDetails, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   code,
+		Domain:   grpcErrorDomain,
+		Metadata: md,
+	}); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// grpcError returns the gRPC status an error is reported as
+func grpcError(err error) error {
+	return grpcProblem(errorCode(err), err.Error(), nil)
+}
+
+// grpcListPage sorts and pages through a resource list by the options of a
+// list call
+func grpcListPage[T any](resources []*T, fields sortFields[T], opts *raft3dv1.ListOptions) (*listPage[T], error) {
+	keys, err := parseSort(opts.GetSort(), fields.names())
+	if err != nil {
+		return nil, grpcProblem(codeInvalidRequest, err.Error(), nil)
+	}
+	limit := int(opts.GetPageSize())
+	switch {
+	case limit == 0:
+		limit = defaultPageLimit
+	case limit < 0 || limit > maxPageLimit:
+		return nil, grpcProblem(codeInvalidRequest, fmt.Sprintf("invalid page_size, expected 1 to %d", maxPageLimit), nil)
+	}
+
+	page, err := pageList(resources, fields, keys, opts.GetSort(), opts.GetPageToken(), limit)
+	if errors.Is(err, errInvalidCursor) {
+		return nil, grpcProblem(codeInvalidRequest, "invalid page_token", nil)
+	}
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return page, nil
+}
+
+// grpcFilter parses the filter of a list call
+func grpcFilter(resourceType string, opts *raft3dv1.ListOptions) (*raft.Filter, error) {
+	filter, err := raft.ParseFilter(resourceType, opts.GetFilter())
+	if err != nil {
+		return nil, grpcProblem(codeInvalidRequest, err.Error(), nil)
+	}
+	return filter, nil
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/devadigapratham/raft3d/api/models"
+	raft3dv1 "github.com/devadigapratham/raft3d/api/proto/raft3d/v1"
+	"github.com/devadigapratham/raft3d/raft"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// printerToProto converts a printer to its gRPC message
+func printerToProto(p *models.Printer) *raft3dv1.Printer {
+	return &raft3dv1.Printer{
+		Id:           p.ID,
+		Company:      p.Company,
+		Model:        p.Model,
+		Version:      p.Version,
+		CreatedIndex: p.CreatedIndex,
+	}
+}
+
+// printerFromProto converts the printer of a request. Server-set fields are
+// left out.
+func printerFromProto(p *raft3dv1.Printer) *models.Printer {
+	return &models.Printer{
+		ID:      p.GetId(),
+		Company: p.GetCompany(),
+		Model:   p.GetModel(),
+	}
+}
+
+// filamentToProto converts a filament to its gRPC message
+func filamentToProto(f *models.Filament) *raft3dv1.Filament {
+	return &raft3dv1.Filament{
+		Id:                     f.ID,
+		Type:                   f.Type,
+		Color:                  f.Color,
+		TotalWeightInGrams:     int64(f.TotalWeightInGrams),
+		RemainingWeightInGrams: int64(f.RemainingWeightInGrams),
+		Version:                f.Version,
+		CreatedIndex:           f.CreatedIndex,
+	}
+}
+
+// filamentFromProto converts the filament of a request. Server-set fields
+// are left out.
+func filamentFromProto(f *raft3dv1.Filament) *models.Filament {
+	return &models.Filament{
+		ID:                     f.GetId(),
+		Type:                   f.GetType(),
+		Color:                  f.GetColor(),
+		TotalWeightInGrams:     int(f.GetTotalWeightInGrams()),
+		RemainingWeightInGrams: int(f.GetRemainingWeightInGrams()),
+	}
+}
+
+// printJobToProto converts a print job, with its status history, to its
+// gRPC message
+func printJobToProto(j *models.PrintJob) *raft3dv1.PrintJob {
+	job := &raft3dv1.PrintJob{
+		Id:                 j.ID,
+		PrinterId:          j.PrinterID,
+		FilamentId:         j.FilamentID,
+		Filepath:           j.Filepath,
+		PrintWeightInGrams: int64(j.PrintWeightInGrams),
+		Status:             j.Status,
+		Version:            j.Version,
+		CreatedIndex:       j.CreatedIndex,
+	}
+	if j.FinishedAt != nil {
+		job.FinishedAt = timestamppb.New(*j.FinishedAt)
+	}
+	for _, t := range j.Transitions {
+		job.Transitions = append(job.Transitions, &raft3dv1.StatusTransition{
+			From:          t.From,
+			To:            t.To,
+			At:            timestamppb.New(t.At),
+			Actor:         t.Actor,
+			Reason:        t.Reason,
+			ConsumedGrams: int64(t.ConsumedGrams),
+		})
+	}
+	return job
+}
+
+// printJobFromProto converts the print job of a request. Server-set fields,
+// the status and its history among them, are left out.
+func printJobFromProto(j *raft3dv1.PrintJob) *models.PrintJob {
+	return &models.PrintJob{
+		ID:                 j.GetId(),
+		PrinterID:          j.GetPrinterId(),
+		FilamentID:         j.GetFilamentId(),
+		Filepath:           j.GetFilepath(),
+		PrintWeightInGrams: int(j.GetPrintWeightInGrams()),
+	}
+}
+
+// reservationsToProto converts the reservations of a filament to their
+// gRPC message
+func reservationsToProto(r *raft.FilamentReservations) *raft3dv1.FilamentReservations {
+	reservations := &raft3dv1.FilamentReservations{
+		FilamentId:             r.FilamentID,
+		RemainingWeightInGrams: int64(r.RemainingWeightInGrams),
+		ReservedGrams:          int64(r.ReservedGrams),
+		FreeGrams:              int64(r.FreeGrams),
+	}
+	for _, res := range r.Reservations {
+		reservations.Reservations = append(reservations.Reservations, &raft3dv1.Reservation{
+			JobId:     res.JobID,
+			PrinterId: res.PrinterID,
+			Status:    res.Status,
+			Grams:     int64(res.Grams),
+		})
+	}
+	return reservations
+}
+
+// changeToProto converts a change to its gRPC message, decoding the
+// resource versions it holds by the change's resource type
+func changeToProto(c *raft.Change) (*raft3dv1.Change, error) {
+	change := &raft3dv1.Change{
+		Index:   c.Index,
+		Time:    timestamppb.New(c.Time),
+		Type:    c.Type,
+		Id:      c.ID,
+		Command: string(c.Command),
+	}
+	var err error
+	if change.Before, err = resourceToProto(c.Type, c.Before); err != nil {
+		return nil, err
+	}
+	if change.After, err = resourceToProto(c.Type, c.After); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// resourceToProto decodes a resource of a type from JSON into its gRPC
+// message, nil if there is no resource
+func resourceToProto(resourceType string, data json.RawMessage) (*raft3dv1.Resource, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	switch resourceType {
+	case raft.ResourcePrinters:
+		var p models.Printer
+		if err := json.Unmarshal(data, &p); err != nil {
+			return nil, fmt.Errorf("failed to decode printer: %v", err)
+		}
+		return &raft3dv1.Resource{Resource: &raft3dv1.Resource_Printer{Printer: printerToProto(&p)}}, nil
+	case raft.ResourceFilaments:
+		var f models.Filament
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("failed to decode filament: %v", err)
+		}
+		return &raft3dv1.Resource{Resource: &raft3dv1.Resource_Filament{Filament: filamentToProto(&f)}}, nil
+	case raft.ResourcePrintJobs:
+		var j models.PrintJob
+		if err := json.Unmarshal(data, &j); err != nil {
+			return nil, fmt.Errorf("failed to decode print job: %v", err)
+		}
+		return &raft3dv1.Resource{Resource: &raft3dv1.Resource_PrintJob{PrintJob: printJobToProto(&j)}}, nil
+	}
+	return nil, fmt.Errorf("unknown resource type: %s", resourceType)
+}
+
+// clusterStatusToProto converts the status of the cluster to its gRPC
+// message
+func clusterStatusToProto(s *raft.ClusterStatus) *raft3dv1.ClusterStatus {
+	status := &raft3dv1.ClusterStatus{
+		NodeId:        s.NodeID,
+		State:         s.State,
+		IsLeader:      s.IsLeader,
+		LeaderAddress: s.LeaderAddress,
+		LeaderId:      s.LeaderID,
+		AppliedIndex:  s.AppliedIndex,
+		LastLogIndex:  s.LastLogIndex,
+	}
+	for _, m := range s.Members {
+		status.Members = append(status.Members, &raft3dv1.ClusterMember{
+			Id:       m.ID,
+			Address:  m.Address,
+			Suffrage: m.Suffrage,
+			IsLeader: m.IsLeader,
+		})
+	}
+	return status
+}
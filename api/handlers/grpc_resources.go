@@ -0,0 +1,411 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/devadigapratham/raft3d/api/models"
+	raft3dv1 "github.com/devadigapratham/raft3d/api/proto/raft3d/v1"
+	"github.com/devadigapratham/raft3d/raft"
+)
+
+// CreatePrinter creates a new printer
+func (s *GRPCServer) CreatePrinter(ctx context.Context, req *raft3dv1.CreatePrinterRequest) (*raft3dv1.Printer, error) {
+	if req.GetPrinter() == nil {
+		return nil, grpcProblem(codeInvalidRequest, "printer is required", nil)
+	}
+	printer := printerFromProto(req.GetPrinter())
+	if err := preparePrinter(printer); err != nil {
+		return nil, grpcProblem(codeValidationFailed, err.Error(), nil)
+	}
+
+	cmd := &models.Command{
+		Type:    models.AddPrinter,
+		Printer: printer,
+	}
+	if err := s.apply(ctx, cmd); err != nil {
+		return nil, grpcError(err)
+	}
+
+	// Respond with the printer as stored, which carries its version
+	if stored, ok := s.Node.GetFSM().GetPrinter(printer.ID); ok {
+		printer = stored
+	}
+	return printerToProto(printer), nil
+}
+
+// GetPrinter returns a printer by ID
+func (s *GRPCServer) GetPrinter(ctx context.Context, req *raft3dv1.GetPrinterRequest) (*raft3dv1.Printer, error) {
+	printer, exists := s.Node.GetFSM().GetPrinter(req.GetId())
+	if !exists {
+		return nil, grpcProblem(codeNotFound, "printer not found", nil)
+	}
+	return printerToProto(printer), nil
+}
+
+// ListPrinters returns a page of printers that pass the filter
+func (s *GRPCServer) ListPrinters(ctx context.Context, req *raft3dv1.ListPrintersRequest) (*raft3dv1.ListPrintersResponse, error) {
+	filter, err := grpcFilter(raft.ResourcePrinters, req.GetOptions())
+	if err != nil {
+		return nil, err
+	}
+	page, err := grpcListPage(s.Node.GetFSM().QueryPrinters(filter), printerSortFields, req.GetOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &raft3dv1.ListPrintersResponse{
+		NextPageToken: page.next,
+		TotalSize:     int32(page.total),
+	}
+	for _, printer := range page.items {
+		resp.Printers = append(resp.Printers, printerToProto(printer))
+	}
+	return resp, nil
+}
+
+// UpdatePrinter changes the fields of a printer that are set in the request
+func (s *GRPCServer) UpdatePrinter(ctx context.Context, req *raft3dv1.UpdatePrinterRequest) (*raft3dv1.Printer, error) {
+	fields := make(map[string]string)
+	if req.Company != nil {
+		fields["company"] = req.GetCompany()
+	}
+	if req.Model != nil {
+		fields["model"] = req.GetModel()
+	}
+	if len(fields) == 0 {
+		return nil, grpcProblem(codeInvalidRequest, "patch changes nothing", nil)
+	}
+	patch, err := json.Marshal(fields)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	// Check if the printer exists
+	if _, exists := s.Node.GetFSM().GetPrinter(req.GetId()); !exists {
+		return nil, grpcProblem(codeNotFound, "printer not found", nil)
+	}
+
+	cmd := &models.Command{
+		Type:            models.UpdatePrinter,
+		PrinterID:       req.GetId(),
+		Patch:           patch,
+		ExpectedVersion: req.ExpectedVersion,
+	}
+	if err := s.apply(ctx, cmd); err != nil {
+		return nil, grpcError(err)
+	}
+	return s.GetPrinter(ctx, &raft3dv1.GetPrinterRequest{Id: req.GetId()})
+}
+
+// DeletePrinter deletes a printer. It fails while Queued or Running jobs are
+// scheduled on the printer, unless cascade is cancel.
+func (s *GRPCServer) DeletePrinter(ctx context.Context, req *raft3dv1.DeletePrinterRequest) (*raft3dv1.DeletePrinterResponse, error) {
+	if req.GetCascade() != "" && req.GetCascade() != raft.CascadeCancel {
+		return nil, grpcProblem(codeInvalidRequest, "invalid cascade, expected cancel", nil)
+	}
+
+	// Check if the printer exists
+	if _, exists := s.Node.GetFSM().GetPrinter(req.GetId()); !exists {
+		return nil, grpcProblem(codeNotFound, "printer not found", nil)
+	}
+
+	cmd := &models.Command{
+		Type:            models.DeletePrinter,
+		PrinterID:       req.GetId(),
+		Cascade:         req.GetCascade(),
+		ExpectedVersion: req.ExpectedVersion,
+	}
+	if err := s.apply(ctx, cmd); err != nil {
+		return nil, grpcError(err)
+	}
+	return &raft3dv1.DeletePrinterResponse{}, nil
+}
+
+// GetCurrentPrintJob returns the print job Running on a printer
+func (s *GRPCServer) GetCurrentPrintJob(ctx context.Context, req *raft3dv1.GetCurrentPrintJobRequest) (*raft3dv1.PrintJob, error) {
+	if _, exists := s.Node.GetFSM().GetPrinter(req.GetPrinterId()); !exists {
+		return nil, grpcProblem(codeNotFound, "printer not found", nil)
+	}
+
+	job, running := s.Node.GetFSM().CurrentPrintJob(req.GetPrinterId())
+	if !running {
+		return nil, grpcProblem(codeNotFound, "no print job is running on the printer", nil)
+	}
+	return printJobToProto(job), nil
+}
+
+// CreateFilament creates a new filament
+func (s *GRPCServer) CreateFilament(ctx context.Context, req *raft3dv1.CreateFilamentRequest) (*raft3dv1.Filament, error) {
+	if req.GetFilament() == nil {
+		return nil, grpcProblem(codeInvalidRequest, "filament is required", nil)
+	}
+	filament := filamentFromProto(req.GetFilament())
+	if err := prepareFilament(filament); err != nil {
+		return nil, grpcProblem(codeValidationFailed, err.Error(), nil)
+	}
+
+	cmd := &models.Command{
+		Type:     models.AddFilament,
+		Filament: filament,
+	}
+	if err := s.apply(ctx, cmd); err != nil {
+		return nil, grpcError(err)
+	}
+
+	// Respond with the filament as stored, which carries its version
+	if stored, ok := s.Node.GetFSM().GetFilament(filament.ID); ok {
+		filament = stored
+	}
+	return filamentToProto(filament), nil
+}
+
+// GetFilament returns a filament by ID
+func (s *GRPCServer) GetFilament(ctx context.Context, req *raft3dv1.GetFilamentRequest) (*raft3dv1.Filament, error) {
+	filament, exists := s.Node.GetFSM().GetFilament(req.GetId())
+	if !exists {
+		return nil, grpcProblem(codeNotFound, "filament not found", nil)
+	}
+	return filamentToProto(filament), nil
+}
+
+// ListFilaments returns a page of filaments that pass the filter
+func (s *GRPCServer) ListFilaments(ctx context.Context, req *raft3dv1.ListFilamentsRequest) (*raft3dv1.ListFilamentsResponse, error) {
+	filter, err := grpcFilter(raft.ResourceFilaments, req.GetOptions())
+	if err != nil {
+		return nil, err
+	}
+	page, err := grpcListPage(s.Node.GetFSM().QueryFilaments(filter), filamentSortFields, req.GetOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &raft3dv1.ListFilamentsResponse{
+		NextPageToken: page.next,
+		TotalSize:     int32(page.total),
+	}
+	for _, filament := range page.items {
+		resp.Filaments = append(resp.Filaments, filamentToProto(filament))
+	}
+	return resp, nil
+}
+
+// UpdateFilament changes the fields of a filament that are set in the
+// request. The remaining weight only changes through AdjustFilamentWeight.
+func (s *GRPCServer) UpdateFilament(ctx context.Context, req *raft3dv1.UpdateFilamentRequest) (*raft3dv1.Filament, error) {
+	fields := make(map[string]interface{})
+	if req.Type != nil {
+		fields["type"] = req.GetType()
+	}
+	if req.Color != nil {
+		fields["color"] = req.GetColor()
+	}
+	if req.TotalWeightInGrams != nil {
+		fields["total_weight_in_grams"] = req.GetTotalWeightInGrams()
+	}
+	if len(fields) == 0 {
+		return nil, grpcProblem(codeInvalidRequest, "patch changes nothing", nil)
+	}
+	patch, err := json.Marshal(fields)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	// Check if the filament exists
+	if _, exists := s.Node.GetFSM().GetFilament(req.GetId()); !exists {
+		return nil, grpcProblem(codeNotFound, "filament not found", nil)
+	}
+
+	cmd := &models.Command{
+		Type:            models.UpdateFilament,
+		FilamentID:      req.GetId(),
+		Patch:           patch,
+		ExpectedVersion: req.ExpectedVersion,
+	}
+	if err := s.apply(ctx, cmd); err != nil {
+		return nil, grpcError(err)
+	}
+	return s.GetFilament(ctx, &raft3dv1.GetFilamentRequest{Id: req.GetId()})
+}
+
+// DeleteFilament deletes a filament. It fails while Queued or Running jobs
+// reserve the filament, unless cascade is cancel.
+func (s *GRPCServer) DeleteFilament(ctx context.Context, req *raft3dv1.DeleteFilamentRequest) (*raft3dv1.DeleteFilamentResponse, error) {
+	if req.GetCascade() != "" && req.GetCascade() != raft.CascadeCancel {
+		return nil, grpcProblem(codeInvalidRequest, "invalid cascade, expected cancel", nil)
+	}
+
+	// Check if the filament exists
+	if _, exists := s.Node.GetFSM().GetFilament(req.GetId()); !exists {
+		return nil, grpcProblem(codeNotFound, "filament not found", nil)
+	}
+
+	cmd := &models.Command{
+		Type:            models.DeleteFilament,
+		FilamentID:      req.GetId(),
+		Cascade:         req.GetCascade(),
+		ExpectedVersion: req.ExpectedVersion,
+	}
+	if err := s.apply(ctx, cmd); err != nil {
+		return nil, grpcError(err)
+	}
+	return &raft3dv1.DeleteFilamentResponse{}, nil
+}
+
+// AdjustFilamentWeight adds delta_grams, which may be negative, to the
+// remaining weight of a filament
+func (s *GRPCServer) AdjustFilamentWeight(ctx context.Context, req *raft3dv1.AdjustFilamentWeightRequest) (*raft3dv1.Filament, error) {
+	if req.GetDeltaGrams() == 0 {
+		return nil, grpcProblem(codeValidationFailed, "delta_grams must not be 0", nil)
+	}
+	if req.GetReason() == "" {
+		return nil, grpcProblem(codeValidationFailed, "reason is required", nil)
+	}
+
+	// Check if the filament exists
+	if _, exists := s.Node.GetFSM().GetFilament(req.GetId()); !exists {
+		return nil, grpcProblem(codeNotFound, "filament not found", nil)
+	}
+
+	cmd := &models.Command{
+		Type:            models.AdjustFilamentWeight,
+		FilamentID:      req.GetId(),
+		DeltaGrams:      int(req.GetDeltaGrams()),
+		Reason:          req.GetReason(),
+		ExpectedVersion: req.ExpectedVersion,
+	}
+	if err := s.apply(ctx, cmd); err != nil {
+		return nil, grpcError(err)
+	}
+	return s.GetFilament(ctx, &raft3dv1.GetFilamentRequest{Id: req.GetId()})
+}
+
+// GetFilamentReservations returns how much of a filament Queued and Running
+// jobs have reserved and how much is free for new jobs
+func (s *GRPCServer) GetFilamentReservations(ctx context.Context, req *raft3dv1.GetFilamentReservationsRequest) (*raft3dv1.FilamentReservations, error) {
+	reservations, exists := s.Node.GetFSM().GetFilamentReservations(req.GetId())
+	if !exists {
+		return nil, grpcProblem(codeNotFound, "filament not found", nil)
+	}
+	return reservationsToProto(reservations), nil
+}
+
+// CreatePrintJob creates a new print job
+func (s *GRPCServer) CreatePrintJob(ctx context.Context, req *raft3dv1.CreatePrintJobRequest) (*raft3dv1.PrintJob, error) {
+	if req.GetPrintJob() == nil {
+		return nil, grpcProblem(codeInvalidRequest, "print_job is required", nil)
+	}
+	printJob := printJobFromProto(req.GetPrintJob())
+	if err := preparePrintJob(printJob); err != nil {
+		return nil, grpcProblem(codeValidationFailed, err.Error(), nil)
+	}
+
+	cmd := &models.Command{
+		Type:     models.AddPrintJob,
+		PrintJob: printJob,
+	}
+	if err := s.apply(ctx, cmd); err != nil {
+		return nil, grpcError(err)
+	}
+
+	// Respond with the job as stored, which carries its version
+	if stored, ok := s.Node.GetFSM().GetPrintJob(printJob.ID); ok {
+		printJob = stored
+	}
+	return printJobToProto(printJob), nil
+}
+
+// GetPrintJob returns a print job by ID, with its status history
+func (s *GRPCServer) GetPrintJob(ctx context.Context, req *raft3dv1.GetPrintJobRequest) (*raft3dv1.PrintJob, error) {
+	job, exists := s.Node.GetFSM().GetPrintJob(req.GetId())
+	if !exists {
+		return nil, grpcProblem(codeNotFound, "print job not found", nil)
+	}
+	return printJobToProto(job), nil
+}
+
+// ListPrintJobs returns a page of print jobs that pass the filter
+func (s *GRPCServer) ListPrintJobs(ctx context.Context, req *raft3dv1.ListPrintJobsRequest) (*raft3dv1.ListPrintJobsResponse, error) {
+	filter, err := grpcFilter(raft.ResourcePrintJobs, req.GetOptions())
+	if err != nil {
+		return nil, err
+	}
+	page, err := grpcListPage(s.Node.GetFSM().QueryPrintJobs(filter), printJobSortFields, req.GetOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &raft3dv1.ListPrintJobsResponse{
+		NextPageToken: page.next,
+		TotalSize:     int32(page.total),
+	}
+	for _, job := range page.items {
+		resp.PrintJobs = append(resp.PrintJobs, printJobToProto(job))
+	}
+	return resp, nil
+}
+
+// UpdatePrintJobStatus moves a print job to another status and returns the
+// job with its status history
+func (s *GRPCServer) UpdatePrintJobStatus(ctx context.Context, req *raft3dv1.UpdatePrintJobStatusRequest) (*raft3dv1.PrintJob, error) {
+	if !models.IsValidPrintJobStatus(req.GetStatus()) {
+		return nil, grpcProblem(codeValidationFailed, "invalid status", nil)
+	}
+
+	return s.changePrintJobStatus(ctx, &models.Command{
+		Type:            models.UpdatePrintJob,
+		JobID:           req.GetId(),
+		NewStatus:       req.GetStatus(),
+		Reason:          req.GetReason(),
+		ExpectedVersion: req.ExpectedVersion,
+	})
+}
+
+// CancelPrintJob cancels a Queued or Running print job. For a Running job
+// the filament it used up so far can be given, which is taken off the spool.
+func (s *GRPCServer) CancelPrintJob(ctx context.Context, req *raft3dv1.CancelPrintJobRequest) (*raft3dv1.PrintJob, error) {
+	if req.GetConsumedGrams() < 0 {
+		return nil, grpcProblem(codeValidationFailed, "consumed_grams can't be negative", nil)
+	}
+
+	return s.changePrintJobStatus(ctx, &models.Command{
+		Type:            models.UpdatePrintJob,
+		JobID:           req.GetId(),
+		NewStatus:       "Canceled",
+		Reason:          req.GetReason(),
+		ConsumedGrams:   int(req.GetConsumedGrams()),
+		ExpectedVersion: req.ExpectedVersion,
+	})
+}
+
+// changePrintJobStatus applies a status change and returns the job
+func (s *GRPCServer) changePrintJobStatus(ctx context.Context, cmd *models.Command) (*raft3dv1.PrintJob, error) {
+	// Check if the job exists
+	if _, exists := s.Node.GetFSM().GetPrintJob(cmd.JobID); !exists {
+		return nil, grpcProblem(codeNotFound, "print job not found", nil)
+	}
+
+	if err := s.apply(ctx, cmd); err != nil {
+		return nil, grpcError(err)
+	}
+	return s.GetPrintJob(ctx, &raft3dv1.GetPrintJobRequest{Id: cmd.JobID})
+}
+
+// DeletePrintJob deletes a Done or Canceled print job. The job is archived
+// like a purged one.
+func (s *GRPCServer) DeletePrintJob(ctx context.Context, req *raft3dv1.DeletePrintJobRequest) (*raft3dv1.DeletePrintJobResponse, error) {
+	// Check if the job exists
+	if _, exists := s.Node.GetFSM().GetPrintJob(req.GetId()); !exists {
+		return nil, grpcProblem(codeNotFound, "print job not found", nil)
+	}
+
+	cmd := &models.Command{
+		Type:            models.DeletePrintJob,
+		JobID:           req.GetId(),
+		ExpectedVersion: req.ExpectedVersion,
+	}
+	if err := s.apply(ctx, cmd); err != nil {
+		return nil, grpcError(err)
+	}
+	return &raft3dv1.DeletePrintJobResponse{}, nil
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	raft3dv1 "github.com/devadigapratham/raft3d/api/proto/raft3d/v1"
+)
+
+func TestGRPCWriteMethods(t *testing.T) {
+	services := raft3dv1.File_raft3d_proto.Services()
+	for i := 0; i < services.Len(); i++ {
+		methods := services.Get(i).Methods()
+		for j := 0; j < methods.Len(); j++ {
+			method := methods.Get(j)
+			fullMethod := fmt.Sprintf("/%s/%s", services.Get(i).FullName(), method.Name())
+			write := false
+			for _, verb := range []string{"Create", "Update", "Delete", "Adjust", "Cancel"} {
+				write = write || strings.HasPrefix(string(method.Name()), verb)
+			}
+			if write != grpcWriteMethods[fullMethod] {
+				t.Errorf("%s applies commands: %v, but is a write method: %v", fullMethod, write, grpcWriteMethods[fullMethod])
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	raft3dv1 "github.com/devadigapratham/raft3d/api/proto/raft3d/v1"
+	"github.com/devadigapratham/raft3d/raft"
+	"google.golang.org/grpc"
+)
+
+// Watch streams the changes made to resources after since_index until the
+// client goes away. While nothing changes, an empty message carrying the
+// last index is sent every watchHeartbeat. If the changes since the index
+// are no longer retained the stream ends with OUT_OF_RANGE.
+func (s *GRPCServer) Watch(req *raft3dv1.WatchRequest, stream grpc.ServerStreamingServer[raft3dv1.WatchResponse]) error {
+	types, err := parseWatchResources(strings.Join(req.GetResources(), ","))
+	if err != nil {
+		return grpcProblem(codeInvalidRequest, err.Error(), nil)
+	}
+
+	// Without since_index, only changes from now on are reported
+	since := s.Node.GetFSM().AppliedIndex()
+	if req.SinceIndex != nil {
+		since = req.GetSinceIndex()
+	}
+
+	heartbeat := time.NewTicker(watchHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		feed, err := s.Node.GetFSM().ChangesSince(since, types, watchBatchSize)
+		if errors.Is(err, raft.ErrResyncRequired) {
+			return grpcProblem(codeResyncRequired, err.Error(), nil)
+		}
+		if err != nil {
+			return grpcError(err)
+		}
+
+		if len(feed.Changes) > 0 {
+			resp := &raft3dv1.WatchResponse{LastIndex: feed.LastIndex}
+			for _, change := range feed.Changes {
+				c, err := changeToProto(change)
+				if err != nil {
+					return grpcError(err)
+				}
+				resp.Changes = append(resp.Changes, c)
+			}
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		}
+		since = feed.LastIndex
+
+		// More may be waiting if the batch was full
+		if len(feed.Changes) >= watchBatchSize {
+			continue
+		}
+
+		select {
+		case <-feed.Updated:
+		case <-heartbeat.C:
+			if err := stream.Send(&raft3dv1.WatchResponse{LastIndex: since}); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// GetClusterStatus returns the state of this node and the members of the
+// cluster
+func (s *GRPCServer) GetClusterStatus(ctx context.Context, req *raft3dv1.GetClusterStatusRequest) (*raft3dv1.ClusterStatus, error) {
+	status, err := s.Node.ClusterStatus()
+	if err != nil {
+		return nil, grpcProblem(codeUnavailable, err.Error(), nil)
+	}
+	return clusterStatusToProto(status), nil
+}
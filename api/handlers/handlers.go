@@ -1,6 +1,14 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
 	"github.com/devadigapratham/raft3d/raft"
 	"github.com/gin-gonic/gin"
 )
@@ -17,6 +25,42 @@ func NewHandler(node *raft.Node) *Handler {
 	}
 }
 
+// asOfIndex reads the as_of_index or as_of_time query parameter of a
+// historical read and returns the log index it refers to. ok is false when
+// the request asks for the current state.
+func (h *Handler) asOfIndex(c *gin.Context) (index uint64, ok bool, err error) {
+	if s := c.Query("as_of_index"); s != "" {
+		index, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid as_of_index")
+		}
+		return index, true, nil
+	}
+
+	if s := c.Query("as_of_time"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid as_of_time, expected RFC 3339")
+		}
+		index, err := h.Node.GetFSM().IndexAsOf(t)
+		if err != nil {
+			return 0, false, err
+		}
+		return index, true, nil
+	}
+
+	return 0, false, nil
+}
+
+// respondAsOfError responds to a historical read that failed
+func respondAsOfError(c *gin.Context, err error) {
+	if errors.Is(err, raft.ErrHistoryUnavailable) {
+		respondError(c, err)
+		return
+	}
+	respondProblem(c, codeInvalidRequest, err.Error())
+}
+
 // RaftLeaderMiddleware ensures a request is forwarded to the leader
 func (h *Handler) RaftLeaderMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -25,14 +69,63 @@ func (h *Handler) RaftLeaderMiddleware() gin.HandlerFunc {
 			// Check if this node is the leader
 			if !h.Node.Leader() {
 				// Respond with the leader's address
-				c.JSON(409, gin.H{
-					"error":  "not the leader",
-					"leader": h.Node.LeaderAddress(),
-				})
-				c.Abort()
+				respondProblem(c, codeNotLeader, "writes go to the leader",
+					gin.H{"leader": h.Node.LeaderAddress()})
 				return
 			}
 		}
 		c.Next()
 	}
 }
+
+// setETag reports the version of a resource as its ETag
+func setETag(c *gin.Context, version uint64) {
+	c.Header("ETag", strconv.Quote(strconv.FormatUint(version, 10)))
+}
+
+// ifMatchVersion reads the version a request's If-Match header requires the
+// resource to be at, or nil if it doesn't require one. "*" matches any
+// version, but like any If-Match needs the resource to exist.
+func ifMatchVersion(c *gin.Context) (*uint64, error) {
+	ifMatch := strings.TrimSpace(c.GetHeader("If-Match"))
+	if ifMatch == "" {
+		return nil, nil
+	}
+	if ifMatch == "*" {
+		version := models.AnyVersion
+		return &version, nil
+	}
+
+	// If-Match compares strongly, so a weak ETag could never match
+	tag, err := strconv.Unquote(ifMatch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid If-Match, expected a single ETag")
+	}
+	version, err := strconv.ParseUint(tag, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid If-Match, expected a single ETag")
+	}
+	return &version, nil
+}
+
+// readPatch reads a JSON object of fields to change from the request body
+func readPatch(c *gin.Context) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := c.ShouldBindJSON(&fields); err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("patch changes nothing")
+	}
+	return json.Marshal(fields)
+}
+
+// pathID sets the ID of a resource from the request path. An ID in the body
+// has to agree with it.
+func pathID(c *gin.Context, id *string) error {
+	if *id != "" && *id != c.Param("id") {
+		return fmt.Errorf("id in body does not match the path")
+	}
+	*id = c.Param("id")
+	return nil
+}
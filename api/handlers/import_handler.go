@@ -0,0 +1,587 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
+	"github.com/devadigapratham/raft3d/raft"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxImportBytes bounds the size of an import body
+	maxImportBytes = 32 << 20
+
+	// appliedIndexHeader reports the index an export reflects
+	appliedIndexHeader = "X-Applied-Index"
+
+	csvContentType    = "text/csv"
+	ndjsonContentType = "application/x-ndjson"
+)
+
+// Columns of the CSV form of each resource type. version and created_index
+// are exported but ignored on import.
+var (
+	printerColumns  = []string{"id", "company", "model", "version", "created_index"}
+	filamentColumns = []string{"id", "type", "color", "total_weight_in_grams", "remaining_weight_in_grams", "version", "created_index"}
+	printJobColumns = []string{"id", "printer_id", "filament_id", "filepath", "print_weight_in_grams", "status", "finished_at", "version", "created_index"}
+
+	readOnlyColumns = []string{"version", "created_index"}
+)
+
+// csvColumns returns the CSV columns of a resource type
+func csvColumns(resourceType string) ([]string, error) {
+	switch resourceType {
+	case raft.ResourcePrinters:
+		return printerColumns, nil
+	case raft.ResourceFilaments:
+		return filamentColumns, nil
+	case raft.ResourcePrintJobs:
+		return printJobColumns, nil
+	}
+	return nil, fmt.Errorf("invalid resource, expected one of %s, %s or %s",
+		raft.ResourcePrinters, raft.ResourceFilaments, raft.ResourcePrintJobs)
+}
+
+// importedFilament tells a remaining weight of 0 from one left out, which
+// defaults to the total weight
+type importedFilament struct {
+	models.Filament
+	RemainingWeightInGrams *int `json:"remaining_weight_in_grams"`
+}
+
+// importDocument is the JSON form of an import, as GET /export writes it
+type importDocument struct {
+	Index     uint64              `json:"index"`
+	Printers  []*models.Printer   `json:"printers"`
+	Filaments []*importedFilament `json:"filaments"`
+	PrintJobs []*models.PrintJob  `json:"print_jobs"`
+}
+
+// ndjsonLine is a resource in the NDJSON form of an import or export
+type ndjsonLine struct {
+	Type     string          `json:"type"`
+	Resource json.RawMessage `json:"resource"`
+}
+
+// importItem is a resource to import, along with where it came from
+type importItem struct {
+	resourceType string
+	// row is the line of a CSV or NDJSON body, or the position in its list
+	// of a JSON body, counting from 1
+	row int
+	cmd *models.Command
+}
+
+// importError is a resource that can't be imported
+type importError struct {
+	Resource string `json:"resource"`
+	Row      int    `json:"row"`
+	ID       string `json:"id,omitempty"`
+	Error    string `json:"error"`
+}
+
+// importReport is the outcome of an import
+type importReport struct {
+	DryRun bool           `json:"dry_run"`
+	Valid  bool           `json:"valid"`
+	Counts map[string]int `json:"counts"`
+	Errors []importError  `json:"errors"`
+	// Imported is the number of resources applied
+	Imported int `json:"imported"`
+}
+
+// newImportItem makes a resource into the command importing it, filling in
+// what an import may leave out
+func newImportItem(resourceType string, row int, resource interface{}) (*importItem, error) {
+	item := &importItem{resourceType: resourceType, row: row}
+	switch r := resource.(type) {
+	case *models.Printer:
+		item.cmd = &models.Command{Type: models.AddPrinter, Printer: r}
+		return item, preparePrinter(r)
+
+	case *importedFilament:
+		item.cmd = &models.Command{Type: models.AddFilament, Filament: &r.Filament}
+		if err := prepareFilament(&r.Filament); err != nil {
+			return item, err
+		}
+		if r.RemainingWeightInGrams != nil {
+			r.Filament.RemainingWeightInGrams = *r.RemainingWeightInGrams
+		}
+		return item, nil
+
+	case *models.PrintJob:
+		item.cmd = &models.Command{Type: models.ImportPrintJob, PrintJob: r}
+		if r.ID == "" {
+			r.ID = uuid.New().String()
+		}
+		if r.Status == "" {
+			r.Status = "Queued"
+		}
+		if !models.IsValidPrintJobStatus(r.Status) {
+			return item, errors.New("invalid status")
+		}
+		return item, models.ValidateID(r.ID)
+	}
+	return nil, fmt.Errorf("unsupported resource %T", resource)
+}
+
+// id returns the ID of the resource an item imports
+func (item *importItem) id() string {
+	switch {
+	case item == nil || item.cmd == nil:
+		return ""
+	case item.cmd.Printer != nil:
+		return item.cmd.Printer.ID
+	case item.cmd.Filament != nil:
+		return item.cmd.Filament.ID
+	case item.cmd.PrintJob != nil:
+		return item.cmd.PrintJob.ID
+	}
+	return ""
+}
+
+// parseImportJSON reads an import in the form GET /export writes as JSON
+func parseImportJSON(body []byte) ([]*importItem, []importError, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	var doc importDocument
+	if err := dec.Decode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+
+	var items []*importItem
+	var errs []importError
+	add := func(resourceType string, row int, resource interface{}) {
+		item, err := newImportItem(resourceType, row, resource)
+		if err != nil {
+			errs = append(errs, importError{Resource: resourceType, Row: row, ID: item.id(), Error: err.Error()})
+			return
+		}
+		items = append(items, item)
+	}
+	for i, p := range doc.Printers {
+		if p == nil {
+			p = &models.Printer{}
+		}
+		add(raft.ResourcePrinters, i+1, p)
+	}
+	for i, f := range doc.Filaments {
+		if f == nil {
+			f = &importedFilament{}
+		}
+		add(raft.ResourceFilaments, i+1, f)
+	}
+	for i, j := range doc.PrintJobs {
+		if j == nil {
+			j = &models.PrintJob{}
+		}
+		add(raft.ResourcePrintJobs, i+1, j)
+	}
+	return items, errs, nil
+}
+
+// newResource returns an empty resource of a type to decode into
+func newResource(resourceType string) (interface{}, error) {
+	switch resourceType {
+	case raft.ResourcePrinters:
+		return &models.Printer{}, nil
+	case raft.ResourceFilaments:
+		return &importedFilament{}, nil
+	case raft.ResourcePrintJobs:
+		return &models.PrintJob{}, nil
+	}
+	return nil, fmt.Errorf("unknown resource type %q", resourceType)
+}
+
+// parseImportNDJSON reads an import in the form GET /export writes as
+// NDJSON
+func parseImportNDJSON(body []byte) ([]*importItem, []importError, error) {
+	var items []*importItem
+	var errs []importError
+	for i, line := range bytes.Split(body, []byte("\n")) {
+		row := i + 1
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var l ndjsonLine
+		if err := json.Unmarshal(line, &l); err != nil {
+			return nil, nil, fmt.Errorf("invalid JSON on line %d: %v", row, err)
+		}
+		resource, err := newResource(l.Type)
+		if err == nil {
+			dec := json.NewDecoder(bytes.NewReader(l.Resource))
+			dec.DisallowUnknownFields()
+			err = dec.Decode(resource)
+		}
+		if err != nil {
+			errs = append(errs, importError{Resource: l.Type, Row: row, Error: err.Error()})
+			continue
+		}
+		item, err := newImportItem(l.Type, row, resource)
+		if err != nil {
+			errs = append(errs, importError{Resource: l.Type, Row: row, ID: item.id(), Error: err.Error()})
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, errs, nil
+}
+
+// parseImportCSV reads an import of one resource type as CSV with a header
+// row naming the columns
+func parseImportCSV(body []byte, resourceType string) ([]*importItem, []importError, error) {
+	columns, err := csvColumns(resourceType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r := csv.NewReader(bytes.NewReader(body))
+	r.TrimLeadingSpace = true
+	header, err := r.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CSV header: %v", err)
+	}
+	for _, name := range header {
+		if !containsString(columns, name) {
+			return nil, nil, fmt.Errorf("unknown column %q, expected some of %s", name, strings.Join(columns, ", "))
+		}
+	}
+
+	var items []*importItem
+	var errs []importError
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+		line, _ := r.FieldPos(0)
+
+		fields := make(map[string]string, len(header))
+		for i, name := range header {
+			if !containsString(readOnlyColumns, name) {
+				fields[name] = record[i]
+			}
+		}
+		resource, err := csvResource(resourceType, fields)
+		if err != nil {
+			errs = append(errs, importError{Resource: resourceType, Row: line, ID: fields["id"], Error: err.Error()})
+			continue
+		}
+		item, err := newImportItem(resourceType, line, resource)
+		if err != nil {
+			errs = append(errs, importError{Resource: resourceType, Row: line, ID: item.id(), Error: err.Error()})
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, errs, nil
+}
+
+// csvResource builds a resource from the fields of a CSV row. Empty fields
+// are left out.
+func csvResource(resourceType string, fields map[string]string) (interface{}, error) {
+	var err error
+	number := func(name string) int {
+		s := fields[name]
+		if s == "" || err != nil {
+			return 0
+		}
+		n, convErr := strconv.Atoi(s)
+		if convErr != nil {
+			err = fmt.Errorf("invalid %s %q, expected a whole number", name, s)
+		}
+		return n
+	}
+
+	switch resourceType {
+	case raft.ResourcePrinters:
+		return &models.Printer{ID: fields["id"], Company: fields["company"], Model: fields["model"]}, nil
+
+	case raft.ResourceFilaments:
+		f := &importedFilament{Filament: models.Filament{
+			ID:                 fields["id"],
+			Type:               fields["type"],
+			Color:              fields["color"],
+			TotalWeightInGrams: number("total_weight_in_grams"),
+		}}
+		if fields["remaining_weight_in_grams"] != "" {
+			remaining := number("remaining_weight_in_grams")
+			f.RemainingWeightInGrams = &remaining
+		}
+		return f, err
+
+	case raft.ResourcePrintJobs:
+		job := &models.PrintJob{
+			ID:                 fields["id"],
+			PrinterID:          fields["printer_id"],
+			FilamentID:         fields["filament_id"],
+			Filepath:           fields["filepath"],
+			PrintWeightInGrams: number("print_weight_in_grams"),
+			Status:             fields["status"],
+		}
+		if s := fields["finished_at"]; s != "" && err == nil {
+			t, parseErr := time.Parse(time.RFC3339, s)
+			if parseErr != nil {
+				return nil, fmt.Errorf("invalid finished_at %q, expected RFC 3339", s)
+			}
+			job.FinishedAt = &t
+		}
+		return job, err
+	}
+	return nil, fmt.Errorf("unknown resource type %q", resourceType)
+}
+
+// Import creates the printers, filaments and print jobs in the request
+// body, given as JSON or NDJSON in the form GET /export writes, or as CSV
+// of the resource type in the resource parameter. Everything is checked
+// against the current state first; with dry_run only the report of that
+// check is returned. Printers are imported before filaments and filaments
+// before print jobs, all in one log entry, so either everything is imported
+// or nothing is. Imported print jobs keep their status and history.
+func (h *Handler) Import(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes))
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, fmt.Sprintf("failed to read body of at most %d bytes: %v", maxImportBytes, err))
+		return
+	}
+
+	var items []*importItem
+	var parseErrs []importError
+	switch c.ContentType() {
+	case csvContentType:
+		items, parseErrs, err = parseImportCSV(body, c.Query("resource"))
+	case ndjsonContentType:
+		items, parseErrs, err = parseImportNDJSON(body)
+	default:
+		items, parseErrs, err = parseImportJSON(body)
+	}
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	// Dependencies first, keeping the order of the body within a type
+	ordered := make([]*importItem, 0, len(items))
+	report := &importReport{DryRun: dryRun, Counts: make(map[string]int), Errors: parseErrs}
+	for _, resourceType := range []string{raft.ResourcePrinters, raft.ResourceFilaments, raft.ResourcePrintJobs} {
+		for _, item := range items {
+			if item.resourceType == resourceType {
+				ordered = append(ordered, item)
+				report.Counts[resourceType]++
+			}
+		}
+	}
+	if len(ordered) == 0 && len(parseErrs) == 0 {
+		respondProblem(c, codeValidationFailed, "nothing to import")
+		return
+	}
+
+	cmds := make([]*models.Command, len(ordered))
+	for i, item := range ordered {
+		item.cmd.Actor = actor(c)
+		cmds[i] = item.cmd
+	}
+	errs, err := h.Node.GetFSM().DryRun(cmds)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	for i, err := range errs {
+		if err != nil {
+			item := ordered[i]
+			report.Errors = append(report.Errors, importError{Resource: item.resourceType, Row: item.row, ID: item.id(), Error: err.Error()})
+		}
+	}
+	if report.Errors == nil {
+		report.Errors = []importError{}
+	}
+	report.Valid = len(report.Errors) == 0
+
+	if dryRun {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+	if !report.Valid {
+		respondProblem(c, codeValidationFailed, "the import has invalid resources, nothing was imported",
+			gin.H{"errors": report.Errors})
+		return
+	}
+
+	// Should the state have changed since the check so that a resource
+	// fails after all, the transaction takes the rest down with it
+	cmd := &models.Command{
+		Type:        models.CommitTransaction,
+		Transaction: &models.Transaction{Operations: cmds},
+	}
+	_, err = h.applyWithResult(c, cmd)
+	var txErr *raft.TransactionError
+	if errors.As(err, &txErr) && txErr.Stage == raft.TransactionStageOperation {
+		item := ordered[txErr.Index]
+		respondError(c, txErr.Err, gin.H{
+			"errors": []importError{{Resource: item.resourceType, Row: item.row, ID: item.id(), Error: txErr.Err.Error()}},
+		})
+		return
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	report.Imported = len(ordered)
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// exportResourceTypes returns the resource types to export, all of them
+// unless the resource parameter names one
+func exportResourceTypes(c *gin.Context) ([]string, error) {
+	resourceType := c.Query("resource")
+	if resourceType == "" {
+		return []string{raft.ResourcePrinters, raft.ResourceFilaments, raft.ResourcePrintJobs}, nil
+	}
+	if _, err := csvColumns(resourceType); err != nil {
+		return nil, err
+	}
+	return []string{resourceType}, nil
+}
+
+// Export streams every printer, filament and print job as of one applied
+// index, as JSON in the form POST /import reads, as NDJSON with one
+// resource per line, or as CSV of the resource type in the resource
+// parameter
+func (h *Handler) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "ndjson" && format != "csv" {
+		respondProblem(c, codeInvalidRequest, "invalid format, expected json, ndjson or csv")
+		return
+	}
+	types, err := exportResourceTypes(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+	if format == "csv" && c.Query("resource") == "" {
+		respondProblem(c, codeInvalidRequest, "format=csv needs the resource to export")
+		return
+	}
+
+	export, err := h.Node.GetFSM().Export()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer export.Close()
+
+	c.Header(appliedIndexHeader, strconv.FormatUint(export.Index, 10))
+	switch format {
+	case "json":
+		c.Header("Content-Type", "application/json")
+		c.Header("Content-Disposition", `attachment; filename="raft3d.json"`)
+		c.Status(http.StatusOK)
+		err = exportJSON(c.Writer, export, types)
+	case "ndjson":
+		c.Header("Content-Type", ndjsonContentType)
+		c.Header("Content-Disposition", `attachment; filename="raft3d.jsonl"`)
+		c.Status(http.StatusOK)
+		err = exportNDJSON(c.Writer, export, types)
+	case "csv":
+		c.Header("Content-Type", csvContentType)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="raft3d-%s.csv"`, types[0]))
+		c.Status(http.StatusOK)
+		err = exportCSV(c.Writer, export, types[0])
+	}
+
+	// The status is out; all that's left is to cut the stream short
+	if err != nil {
+		c.Error(err)
+	}
+}
+
+func exportJSON(w io.Writer, export *raft.StateExport, types []string) error {
+	if _, err := fmt.Fprintf(w, `{"index":%d`, export.Index); err != nil {
+		return err
+	}
+	for _, resourceType := range types {
+		if _, err := fmt.Fprintf(w, `,%q:[`, resourceType); err != nil {
+			return err
+		}
+		first := true
+		err := export.ForEach(resourceType, func(data json.RawMessage) error {
+			if !first {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			first = false
+			_, err := w.Write(data)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "]"); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "}\n")
+	return err
+}
+
+func exportNDJSON(w io.Writer, export *raft.StateExport, types []string) error {
+	enc := json.NewEncoder(w)
+	for _, resourceType := range types {
+		err := export.ForEach(resourceType, func(data json.RawMessage) error {
+			return enc.Encode(ndjsonLine{Type: resourceType, Resource: data})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exportCSV(w io.Writer, export *raft.StateExport, resourceType string) error {
+	columns, err := csvColumns(resourceType)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	err = export.ForEach(resourceType, func(data json.RawMessage) error {
+		var fields map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&fields); err != nil {
+			return err
+		}
+		record := make([]string, len(columns))
+		for i, name := range columns {
+			if v, ok := fields[name]; ok && v != nil {
+				record[i] = fmt.Sprint(v)
+			}
+		}
+		return cw.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
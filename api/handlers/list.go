@@ -0,0 +1,338 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/devadigapratham/raft3d/api/models"
+	"github.com/devadigapratham/raft3d/raft"
+	"github.com/gin-gonic/gin"
+)
+
+// Headers of a paginated resource list
+const (
+	totalCountHeader = "X-Total-Count"
+	nextCursorHeader = "X-Next-Cursor"
+)
+
+// sortField is a field a resource list can be sorted on. Its value is read
+// straight off the resource, as a value that comes back the same from the
+// JSON of a cursor and orders like the field.
+type sortField[T any] struct {
+	name  string
+	value func(*T) interface{}
+}
+
+// sortFields are the fields a resource list can be sorted on
+type sortFields[T any] []sortField[T]
+
+// names returns the names of the fields
+func (fields sortFields[T]) names() []string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.name
+	}
+	return names
+}
+
+// get returns the field with a name, or nil if there is none
+func (fields sortFields[T]) get(name string) func(*T) interface{} {
+	for _, field := range fields {
+		if field.name == name {
+			return field.value
+		}
+	}
+	return nil
+}
+
+// sortTimeLayout formats times to sort on. In UTC and with every digit of
+// the fraction, the strings sort in the order of the times.
+const sortTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// Fields each resource list can be sorted on
+var (
+	printerSortFields = sortFields[models.Printer]{
+		{"id", func(p *models.Printer) interface{} { return p.ID }},
+		{"company", func(p *models.Printer) interface{} { return p.Company }},
+		{"model", func(p *models.Printer) interface{} { return p.Model }},
+		{"version", func(p *models.Printer) interface{} { return float64(p.Version) }},
+		{"created_index", func(p *models.Printer) interface{} { return float64(p.CreatedIndex) }},
+	}
+	filamentSortFields = sortFields[models.Filament]{
+		{"id", func(f *models.Filament) interface{} { return f.ID }},
+		{"type", func(f *models.Filament) interface{} { return f.Type }},
+		{"color", func(f *models.Filament) interface{} { return f.Color }},
+		{"total_weight_in_grams", func(f *models.Filament) interface{} { return float64(f.TotalWeightInGrams) }},
+		{"remaining_weight_in_grams", func(f *models.Filament) interface{} { return float64(f.RemainingWeightInGrams) }},
+		{"version", func(f *models.Filament) interface{} { return float64(f.Version) }},
+		{"created_index", func(f *models.Filament) interface{} { return float64(f.CreatedIndex) }},
+	}
+	printJobSortFields = sortFields[models.PrintJob]{
+		{"id", func(j *models.PrintJob) interface{} { return j.ID }},
+		{"printer_id", func(j *models.PrintJob) interface{} { return j.PrinterID }},
+		{"filament_id", func(j *models.PrintJob) interface{} { return j.FilamentID }},
+		{"status", func(j *models.PrintJob) interface{} { return j.Status }},
+		{"print_weight_in_grams", func(j *models.PrintJob) interface{} { return float64(j.PrintWeightInGrams) }},
+		{"finished_at", func(j *models.PrintJob) interface{} {
+			if j.FinishedAt == nil {
+				return nil
+			}
+			return j.FinishedAt.UTC().Format(sortTimeLayout)
+		}},
+		{"version", func(j *models.PrintJob) interface{} { return float64(j.Version) }},
+		{"created_index", func(j *models.PrintJob) interface{} { return float64(j.CreatedIndex) }},
+	}
+)
+
+// sortKey is a field to sort on, descending if desc
+type sortKey struct {
+	field string
+	desc  bool
+}
+
+// listCursor is where a page of a resource list ends. It holds the sort
+// values of the last item, so the next page starts after it even if items
+// were added or removed in between.
+type listCursor struct {
+	Sort   string        `json:"s"`
+	Values []interface{} `json:"v"`
+}
+
+// listItem is a resource along with its sort values
+type listItem[T any] struct {
+	resource *T
+	values   []interface{}
+}
+
+// parseSort parses a sort parameter, field,-field. Ties, and lists without
+// a sort, are ordered by creation index and then ID.
+func parseSort(s string, fields []string) ([]sortKey, error) {
+	var keys []sortKey
+	if s != "" {
+		for _, name := range strings.Split(s, ",") {
+			key := sortKey{field: name}
+			if strings.HasPrefix(name, "-") {
+				key = sortKey{field: name[1:], desc: true}
+			}
+			if !containsString(fields, key.field) {
+				return nil, fmt.Errorf("invalid sort field %q, expected one of %s", key.field, strings.Join(fields, ", "))
+			}
+			keys = append(keys, key)
+		}
+	}
+	return append(keys, sortKey{field: "created_index"}, sortKey{field: "id"}), nil
+}
+
+// parseFilter reads the filter of a resource list: the filter parameter,
+// narrowed down by any field=value parameters naming a filterable field
+func parseFilter(c *gin.Context, resourceType string) (*raft.Filter, error) {
+	filter, err := raft.ParseFilter(resourceType, c.Query("filter"))
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range raft.FilterFields(resourceType) {
+		if value, ok := c.GetQuery(field); ok {
+			if err := filter.Equal(field, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return filter, nil
+}
+
+// errInvalidCursor is returned for a cursor that wasn't made for the list
+// it is passed to
+var errInvalidCursor = errors.New("invalid cursor")
+
+// listPage is one page of a sorted resource list
+type listPage[T any] struct {
+	items []*T
+	// total is the number of items on all pages
+	total int
+	// next is the cursor of the next page, empty on the last one
+	next string
+}
+
+// respondList sorts and pages through a resource list, answering with one
+// page of it. The total number of items and the cursor of the next page, if
+// there is one, go in headers.
+func respondList[T any](c *gin.Context, resources []*T, fields sortFields[T]) {
+	keys, err := parseSort(c.Query("sort"), fields.names())
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+	limit, err := parseLimit(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	page, err := pageList(resources, fields, keys, c.Query("sort"), c.Query("cursor"), limit)
+	if errors.Is(err, errInvalidCursor) {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header(totalCountHeader, strconv.Itoa(page.total))
+	if page.next != "" {
+		c.Header(nextCursorHeader, page.next)
+	}
+	c.JSON(http.StatusOK, page.items)
+}
+
+// pageList sorts a resource list by keys on its fields, parsed from
+// sortSpec, and returns the page of up to limit items after cursor
+func pageList[T any](resources []*T, fields sortFields[T], keys []sortKey, sortSpec, cursor string, limit int) (*listPage[T], error) {
+	accessors := make([]func(*T) interface{}, len(keys))
+	for i, key := range keys {
+		if accessors[i] = fields.get(key.field); accessors[i] == nil {
+			return nil, fmt.Errorf("invalid sort field %q", key.field)
+		}
+	}
+
+	items := make([]listItem[T], len(resources))
+	for i, r := range resources {
+		values := make([]interface{}, len(keys))
+		for j, value := range accessors {
+			values[j] = value(r)
+		}
+		items[i] = listItem[T]{resource: r, values: values}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return compareSortValues(keys, items[i].values, items[j].values) < 0
+	})
+
+	start := 0
+	if cursor != "" {
+		after, err := decodeListCursor(cursor)
+		if err != nil || after.Sort != sortSpec || len(after.Values) != len(keys) {
+			return nil, errInvalidCursor
+		}
+		start = sort.Search(len(items), func(i int) bool {
+			return compareSortValues(keys, items[i].values, after.Values) > 0
+		})
+	}
+	end := start + limit
+	if end > len(items) {
+		end = len(items)
+	}
+
+	page := &listPage[T]{
+		items: make([]*T, 0, end-start),
+		total: len(items),
+	}
+	if end < len(items) {
+		next, err := encodeListCursor(&listCursor{Sort: sortSpec, Values: items[end-1].values})
+		if err != nil {
+			return nil, err
+		}
+		page.next = next
+	}
+	for _, item := range items[start:end] {
+		page.items = append(page.items, item.resource)
+	}
+	return page, nil
+}
+
+// compareSortValues orders two sets of sort values
+func compareSortValues(keys []sortKey, a, b []interface{}) int {
+	for i, key := range keys {
+		cmp := compareValues(a[i], b[i])
+		if key.desc {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp
+		}
+	}
+	return 0
+}
+
+// compareValues orders two decoded JSON values. Missing values come first,
+// and values of different types are ordered by type.
+func compareValues(a, b interface{}) int {
+	rank := func(v interface{}) int {
+		switch v.(type) {
+		case nil:
+			return 0
+		case bool:
+			return 1
+		case float64:
+			return 2
+		case string:
+			return 3
+		default:
+			return 4
+		}
+	}
+	if ra, rb := rank(a), rank(b); ra != rb {
+		return ra - rb
+	}
+
+	switch va := a.(type) {
+	case bool:
+		vb := b.(bool)
+		switch {
+		case va == vb:
+			return 0
+		case !va:
+			return -1
+		}
+		return 1
+	case float64:
+		vb := b.(float64)
+		switch {
+		case va < vb:
+			return -1
+		case va > vb:
+			return 1
+		}
+		return 0
+	case string:
+		return strings.Compare(va, b.(string))
+	}
+	return 0
+}
+
+// encodeListCursor turns a cursor into the opaque string clients pass back
+func encodeListCursor(cursor *listCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeListCursor reads a cursor made by encodeListCursor
+func decodeListCursor(s string) (*listCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var cursor listCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// containsString reports whether list holds s
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+// checkSortFields fails the test unless every sort value of a resource
+// comes back the same from the JSON of a cursor
+func checkSortFields[T any](t *testing.T, resource *T, fields sortFields[T]) {
+	t.Helper()
+
+	for _, field := range fields {
+		value := field.value(resource)
+		data, err := json.Marshal(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(value, decoded) {
+			t.Errorf("sort field %s of %T is %#v, %#v in a cursor", field.name, resource, value, decoded)
+		}
+	}
+}
+
+func TestSortFieldsSurviveCursors(t *testing.T) {
+	finished := time.Date(2024, 3, 1, 12, 30, 0, 123400000, time.FixedZone("CET", 3600))
+	checkSortFields(t, &models.Printer{ID: "p1", Company: "Prusa", Model: "MK4", Version: 7, CreatedIndex: 3}, printerSortFields)
+	checkSortFields(t, &models.Filament{
+		ID: "f1", Type: "PLA", Color: "red", TotalWeightInGrams: 1000, RemainingWeightInGrams: 750, Version: 9, CreatedIndex: 4,
+	}, filamentSortFields)
+	checkSortFields(t, &models.PrintJob{
+		ID: "j1", PrinterID: "p1", FilamentID: "f1", PrintWeightInGrams: 40, Status: "Done", FinishedAt: &finished, Version: 12, CreatedIndex: 5,
+	}, printJobSortFields)
+	checkSortFields(t, &models.PrintJob{ID: "j2", PrinterID: "p1", FilamentID: "f1", Status: "Queued"}, printJobSortFields)
+}
+
+func TestSortByFinishedAtIsChronological(t *testing.T) {
+	// Listed in the order they finished, with whole and fractional seconds
+	// and in different zones
+	base := time.Date(2024, 3, 1, 12, 0, 1, 0, time.UTC)
+	times := []time.Time{
+		base.Add(-time.Hour).In(time.FixedZone("EST", -5*3600)),
+		base,
+		base.Add(time.Nanosecond),
+		base.Add(500 * time.Millisecond),
+		base.Add(time.Second).In(time.FixedZone("CEST", 2*3600)),
+		base.Add(2 * time.Hour).In(time.FixedZone("PST", -8*3600)),
+	}
+	var jobs []*models.PrintJob
+	for i := len(times) - 1; i >= 0; i-- {
+		finished := times[i]
+		jobs = append(jobs, &models.PrintJob{ID: fmt.Sprintf("j%d", i), Status: "Done", FinishedAt: &finished, CreatedIndex: uint64(len(times) - i)})
+	}
+	jobs = append(jobs, &models.PrintJob{ID: "queued", Status: "Queued", CreatedIndex: 99})
+
+	keys, err := parseSort("finished_at", printJobSortFields.names())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	cursor := ""
+	for {
+		page, err := pageList(jobs, printJobSortFields, keys, "finished_at", cursor, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, job := range page.items {
+			got = append(got, job.ID)
+		}
+		if page.next == "" {
+			break
+		}
+		cursor = page.next
+	}
+	want := []string{"queued", "j0", "j1", "j2", "j3", "j4", "j5"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sorting by finished_at lists %v, want %v", got, want)
+	}
+}
+
+func TestPageListFollowsCursor(t *testing.T) {
+	var jobs []*models.PrintJob
+	for i := 0; i < 10; i++ {
+		jobs = append(jobs, &models.PrintJob{
+			ID: fmt.Sprintf("j%d", i), PrinterID: fmt.Sprintf("p%d", i%3), PrintWeightInGrams: 10 * (i % 4), CreatedIndex: uint64(i + 1),
+		})
+	}
+	const spec = "printer_id,-print_weight_in_grams"
+	keys, err := parseSort(spec, printJobSortFields.names())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	cursor := ""
+	for {
+		page, err := pageList(jobs, printJobSortFields, keys, spec, cursor, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, job := range page.items {
+			got = append(got, job.ID)
+		}
+		if page.next == "" {
+			break
+		}
+		cursor = page.next
+	}
+	want := []string{"j3", "j6", "j9", "j0", "j7", "j1", "j4", "j2", "j5", "j8"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pages list %v, want %v", got, want)
+	}
+}
+
+func BenchmarkPageList(b *testing.B) {
+	var jobs []*models.PrintJob
+	for i := 0; i < 10000; i++ {
+		jobs = append(jobs, &models.PrintJob{
+			ID: fmt.Sprintf("job%05d", i), PrinterID: fmt.Sprintf("p%d", i%100), FilamentID: "f1",
+			Filepath: "/prints/part.gcode", PrintWeightInGrams: i % 50, Status: "Queued", CreatedIndex: uint64(i + 1),
+		})
+	}
+	keys, err := parseSort("printer_id", printJobSortFields.names())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := pageList(jobs, printJobSortFields, keys, "printer_id", "", 50); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
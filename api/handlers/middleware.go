@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"github.com/devadigapratham/raft3d/api/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// requestIDHeader carries the ID a request is traced by
+	requestIDHeader = "X-Request-ID"
+	// actorHeader names the caller when authentication is off
+	actorHeader = "X-Actor"
+
+	// Context keys
+	contextRequestID = "request_id"
+	contextActor     = "actor"
+
+	anonymousActor = "anonymous"
+	// unverifiedActorPrefix tags actors named by the caller rather than
+	// established by authentication
+	unverifiedActorPrefix = "unverified:"
+)
+
+// RequestIDMiddleware tags every request with an ID, taken from the
+// X-Request-ID header or generated, and echoes it in the response
+func (h *Handler) RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = uuid.New().String()
+		}
+		c.Set(contextRequestID, requestID)
+		c.Header(requestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// actor returns who is making the request
+func actor(c *gin.Context) string {
+	if actor := c.GetString(contextActor); actor != "" {
+		return actor
+	}
+	return claimedActor(c.GetHeader(actorHeader))
+}
+
+// claimedActor returns the actor of an unauthenticated request naming
+// itself name. Nothing vouches for the name, so it is recorded as
+// unverified, and without one the request is anonymous.
+func claimedActor(name string) string {
+	if name == "" {
+		return anonymousActor
+	}
+	return unverifiedActorPrefix + name
+}
+
+// apply stamps a command with the identity of the request and applies it
+// through raft
+func (h *Handler) apply(c *gin.Context, cmd *models.Command) error {
+	_, err := h.applyWithResult(c, cmd)
+	return err
+}
+
+// applyWithResult is apply for commands whose result the caller needs
+func (h *Handler) applyWithResult(c *gin.Context, cmd *models.Command) (interface{}, error) {
+	cmd.Actor = actor(c)
+	cmd.ClientIP = c.ClientIP()
+	cmd.RequestID = c.GetString(contextRequestID)
+	return h.Node.ApplyWithResult(cmd)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/devadigapratham/raft3d/api/models"
+	"github.com/devadigapratham/raft3d/raft"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -12,13 +13,13 @@ import (
 func (h *Handler) CreatePrinter(c *gin.Context) {
 	var printer models.Printer
 	if err := c.ShouldBindJSON(&printer); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondProblem(c, codeInvalidRequest, err.Error())
 		return
 	}
 
-	// Generate an ID if not provided
-	if printer.ID == "" {
-		printer.ID = uuid.New().String()
+	if err := preparePrinter(&printer); err != nil {
+		respondProblem(c, codeValidationFailed, err.Error())
+		return
 	}
 
 	// Create the command
@@ -28,16 +29,249 @@ func (h *Handler) CreatePrinter(c *gin.Context) {
 	}
 
 	// Apply the command
-	if err := h.Node.Apply(cmd); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.apply(c, cmd); err != nil {
+		respondError(c, err)
 		return
 	}
 
+	// Respond with the printer as stored, which carries its version
+	if stored, ok := h.Node.GetFSM().GetPrinter(printer.ID); ok {
+		printer = *stored
+	}
+	setETag(c, printer.Version)
 	c.JSON(http.StatusCreated, printer)
 }
 
-// GetPrinters returns all printers
+// ReplacePrinter creates or replaces the printer with the ID in the path,
+// honouring If-Match
+func (h *Handler) ReplacePrinter(c *gin.Context) {
+	var printer models.Printer
+	if err := c.ShouldBindJSON(&printer); err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	if err := pathID(c, &printer.ID); err != nil {
+		respondProblem(c, codeValidationFailed, err.Error())
+		return
+	}
+	if err := preparePrinter(&printer); err != nil {
+		respondProblem(c, codeValidationFailed, err.Error())
+		return
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	// Create the command
+	cmd := &models.Command{
+		Type:            models.UpsertPrinter,
+		Printer:         &printer,
+		ExpectedVersion: expectedVersion,
+	}
+
+	// Apply the command
+	if err := h.apply(c, cmd); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Respond with the printer as stored, which carries its version
+	if stored, ok := h.Node.GetFSM().GetPrinter(printer.ID); ok {
+		printer = *stored
+	}
+	setETag(c, printer.Version)
+	c.JSON(http.StatusOK, printer)
+}
+
+// GetPrinters returns a page of printers that pass the filter,
+// optionally as they were at an earlier point given by as_of_index or
+// as_of_time
 func (h *Handler) GetPrinters(c *gin.Context) {
-	printers := h.Node.GetFSM().GetPrinters()
-	c.JSON(http.StatusOK, printers)
+	filter, err := parseFilter(c, raft.ResourcePrinters)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	index, asOf, err := h.asOfIndex(c)
+	if err != nil {
+		respondAsOfError(c, err)
+		return
+	}
+	if asOf {
+		printers, err := h.Node.GetFSM().GetPrintersAsOf(index)
+		if err != nil {
+			respondAsOfError(c, err)
+			return
+		}
+		respondList(c, raft.FilterResources(printers, filter), printerSortFields)
+		return
+	}
+
+	printers := h.Node.GetFSM().QueryPrinters(filter)
+	respondList(c, printers, printerSortFields)
+}
+
+// GetPrinter returns a printer by ID, optionally as it was at an earlier
+// point given by as_of_index or as_of_time
+func (h *Handler) GetPrinter(c *gin.Context) {
+	index, asOf, err := h.asOfIndex(c)
+	if err != nil {
+		respondAsOfError(c, err)
+		return
+	}
+	var printer *models.Printer
+	exists := false
+	if asOf {
+		printer, err = h.Node.GetFSM().GetPrinterAsOf(c.Param("id"), index)
+		if err != nil {
+			respondAsOfError(c, err)
+			return
+		}
+		exists = printer != nil
+	} else {
+		printer, exists = h.Node.GetFSM().GetPrinter(c.Param("id"))
+	}
+	if !exists {
+		respondProblem(c, codeNotFound, "printer not found")
+		return
+	}
+
+	setETag(c, printer.Version)
+	c.JSON(http.StatusOK, printer)
+}
+
+// UpdatePrinter changes the fields of a printer given in the request body,
+// honouring If-Match
+func (h *Handler) UpdatePrinter(c *gin.Context) {
+	printerID := c.Param("id")
+	patch, err := readPatch(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	// Check if the printer exists
+	if _, exists := h.Node.GetFSM().GetPrinter(printerID); !exists {
+		respondProblem(c, codeNotFound, "printer not found")
+		return
+	}
+
+	// Create the command
+	cmd := &models.Command{
+		Type:            models.UpdatePrinter,
+		PrinterID:       printerID,
+		Patch:           patch,
+		ExpectedVersion: expectedVersion,
+	}
+
+	// Apply the command
+	if err := h.apply(c, cmd); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Get the updated printer
+	printer, exists := h.Node.GetFSM().GetPrinter(printerID)
+	if exists {
+		setETag(c, printer.Version)
+	}
+	c.JSON(http.StatusOK, printer)
+}
+
+// DeletePrinter deletes a printer, honouring If-Match. It fails while
+// Queued or Running jobs are scheduled on the printer, unless cascade=cancel
+// cancels them in the same log entry.
+func (h *Handler) DeletePrinter(c *gin.Context) {
+	printerID := c.Param("id")
+	cascade := c.Query("cascade")
+	if cascade != "" && cascade != raft.CascadeCancel {
+		respondProblem(c, codeInvalidRequest, "invalid cascade, expected cancel")
+		return
+	}
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	// Check if the printer exists
+	if _, exists := h.Node.GetFSM().GetPrinter(printerID); !exists {
+		respondProblem(c, codeNotFound, "printer not found")
+		return
+	}
+
+	// Create the command
+	cmd := &models.Command{
+		Type:            models.DeletePrinter,
+		PrinterID:       printerID,
+		Cascade:         cascade,
+		ExpectedVersion: expectedVersion,
+	}
+
+	// Apply the command
+	if err := h.apply(c, cmd); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetPrinterPrintJobs returns a page of the print jobs scheduled on a
+// printer, filtered and sorted like the list of all print jobs
+func (h *Handler) GetPrinterPrintJobs(c *gin.Context) {
+	printerID := c.Param("id")
+	filter, err := parseFilter(c, raft.ResourcePrintJobs)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+	if err := filter.Equal("printer_id", printerID); err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	if _, exists := h.Node.GetFSM().GetPrinter(printerID); !exists {
+		respondProblem(c, codeNotFound, "printer not found")
+		return
+	}
+	h.listPrintJobs(c, filter)
+}
+
+// GetCurrentPrintJob returns the print job Running on a printer
+func (h *Handler) GetCurrentPrintJob(c *gin.Context) {
+	printerID := c.Param("id")
+	if _, exists := h.Node.GetFSM().GetPrinter(printerID); !exists {
+		respondProblem(c, codeNotFound, "printer not found")
+		return
+	}
+
+	job, running := h.Node.GetFSM().CurrentPrintJob(printerID)
+	if !running {
+		respondProblem(c, codeNotFound, "no print job is running on the printer")
+		return
+	}
+
+	setETag(c, job.Version)
+	c.JSON(http.StatusOK, job)
+}
+
+// preparePrinter validates a new printer and fills in what a client may
+// leave out
+func preparePrinter(printer *models.Printer) error {
+	// Generate an ID if not provided
+	if printer.ID == "" {
+		printer.ID = uuid.New().String()
+	}
+	return models.ValidateID(printer.ID)
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/devadigapratham/raft3d/api/models"
+	"github.com/devadigapratham/raft3d/raft"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -12,18 +13,15 @@ import (
 func (h *Handler) CreatePrintJob(c *gin.Context) {
 	var printJob models.PrintJob
 	if err := c.ShouldBindJSON(&printJob); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondProblem(c, codeInvalidRequest, err.Error())
 		return
 	}
 
-	// Generate an ID if not provided
-	if printJob.ID == "" {
-		printJob.ID = uuid.New().String()
+	if err := preparePrintJob(&printJob); err != nil {
+		respondProblem(c, codeValidationFailed, err.Error())
+		return
 	}
 
-	// Force status to be "Queued"
-	printJob.Status = "Queued"
-
 	// Create the command
 	cmd := &models.Command{
 		Type:     models.AddPrintJob,
@@ -31,61 +29,259 @@ func (h *Handler) CreatePrintJob(c *gin.Context) {
 	}
 
 	// Apply the command
-	if err := h.Node.Apply(cmd); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.apply(c, cmd); err != nil {
+		respondError(c, err)
 		return
 	}
 
+	// Respond with the job as stored, which carries its version
+	if stored, ok := h.Node.GetFSM().GetPrintJob(printJob.ID); ok {
+		printJob = *stored
+	}
+	setETag(c, printJob.Version)
 	c.JSON(http.StatusCreated, printJob)
 }
 
-// GetPrintJobs returns all print jobs
+// ReplacePrintJob creates or replaces the print job with the ID in the path,
+// honouring If-Match. A replaced job keeps its status, and while Queued or
+// Running its filament and weight.
+func (h *Handler) ReplacePrintJob(c *gin.Context) {
+	var printJob models.PrintJob
+	if err := c.ShouldBindJSON(&printJob); err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	if err := pathID(c, &printJob.ID); err != nil {
+		respondProblem(c, codeValidationFailed, err.Error())
+		return
+	}
+	if err := preparePrintJob(&printJob); err != nil {
+		respondProblem(c, codeValidationFailed, err.Error())
+		return
+	}
+
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	// Create the command
+	cmd := &models.Command{
+		Type:            models.UpsertPrintJob,
+		PrintJob:        &printJob,
+		ExpectedVersion: expectedVersion,
+	}
+
+	// Apply the command
+	if err := h.apply(c, cmd); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Respond with the job as stored, which carries its version
+	if stored, ok := h.Node.GetFSM().GetPrintJob(printJob.ID); ok {
+		printJob = *stored
+	}
+	setETag(c, printJob.Version)
+	c.JSON(http.StatusOK, printJob)
+}
+
+// GetPrintJobs returns a page of print jobs that pass the filter,
+// optionally as they were at an earlier point given by as_of_index or
+// as_of_time
 func (h *Handler) GetPrintJobs(c *gin.Context) {
-	// Check if status filter is provided
-	status := c.Query("status")
+	filter, err := parseFilter(c, raft.ResourcePrintJobs)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+	h.listPrintJobs(c, filter)
+}
+
+// listPrintJobs responds with a page of the print jobs that pass the
+// filter, as of as_of_index or as_of_time if given
+func (h *Handler) listPrintJobs(c *gin.Context, filter *raft.Filter) {
+	index, asOf, err := h.asOfIndex(c)
+	if err != nil {
+		respondAsOfError(c, err)
+		return
+	}
+	if asOf {
+		printJobs, err := h.Node.GetFSM().GetPrintJobsAsOf(index)
+		if err != nil {
+			respondAsOfError(c, err)
+			return
+		}
+		respondList(c, raft.FilterResources(printJobs, filter), printJobSortFields)
+		return
+	}
+
+	printJobs := h.Node.GetFSM().QueryPrintJobs(filter)
+	respondList(c, printJobs, printJobSortFields)
+}
 
-	var printJobs []*models.PrintJob
-	if status != "" && models.IsValidPrintJobStatus(status) {
-		printJobs = h.Node.GetFSM().GetPrintJobsByStatus(status)
+// GetPrintJob returns a print job by ID, with its status history,
+// optionally as it was at an earlier point given by as_of_index or
+// as_of_time
+func (h *Handler) GetPrintJob(c *gin.Context) {
+	index, asOf, err := h.asOfIndex(c)
+	if err != nil {
+		respondAsOfError(c, err)
+		return
+	}
+	var job *models.PrintJob
+	exists := false
+	if asOf {
+		job, err = h.Node.GetFSM().GetPrintJobAsOf(c.Param("id"), index)
+		if err != nil {
+			respondAsOfError(c, err)
+			return
+		}
+		exists = job != nil
 	} else {
-		printJobs = h.Node.GetFSM().GetPrintJobs()
+		job, exists = h.Node.GetFSM().GetPrintJob(c.Param("id"))
+	}
+	if !exists {
+		respondProblem(c, codeNotFound, "print job not found")
+		return
 	}
 
-	c.JSON(http.StatusOK, printJobs)
+	setETag(c, job.Version)
+	c.JSON(http.StatusOK, job)
 }
 
-// UpdatePrintJobStatus updates the status of a print job
+// UpdatePrintJobStatus moves a print job to the status in the request body,
+// honouring If-Match, and returns the job with its status history. The
+// status may still be given as a query parameter instead, as it used to be.
 func (h *Handler) UpdatePrintJobStatus(c *gin.Context) {
-	jobID := c.Param("id")
-	newStatus := c.Query("status")
+	var req struct {
+		Status string `json:"status"`
+		Reason string `json:"reason"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondProblem(c, codeInvalidRequest, err.Error())
+			return
+		}
+	}
+	if req.Status == "" {
+		req.Status = c.Query("status")
+	}
 
 	// Validate status
-	if !models.IsValidPrintJobStatus(newStatus) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+	if !models.IsValidPrintJobStatus(req.Status) {
+		respondProblem(c, codeValidationFailed, "invalid status")
+		return
+	}
+
+	h.changePrintJobStatus(c, &models.Command{
+		Type:      models.UpdatePrintJob,
+		JobID:     c.Param("id"),
+		NewStatus: req.Status,
+		Reason:    req.Reason,
+	})
+}
+
+// CancelPrintJob cancels a Queued or Running print job, honouring If-Match.
+// For a Running job the filament it used up so far can be given, which is
+// taken off the spool.
+func (h *Handler) CancelPrintJob(c *gin.Context) {
+	var req struct {
+		Reason        string `json:"reason"`
+		ConsumedGrams int    `json:"consumed_grams"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondProblem(c, codeInvalidRequest, err.Error())
+			return
+		}
+	}
+	if req.ConsumedGrams < 0 {
+		respondProblem(c, codeValidationFailed, "consumed_grams can't be negative")
 		return
 	}
 
+	h.changePrintJobStatus(c, &models.Command{
+		Type:          models.UpdatePrintJob,
+		JobID:         c.Param("id"),
+		NewStatus:     "Canceled",
+		Reason:        req.Reason,
+		ConsumedGrams: req.ConsumedGrams,
+	})
+}
+
+// changePrintJobStatus applies a status change and responds with the job
+func (h *Handler) changePrintJobStatus(c *gin.Context, cmd *models.Command) {
 	// Check if the job exists
-	_, exists := h.Node.GetFSM().GetPrintJob(jobID)
-	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "print job not found"})
+	if _, exists := h.Node.GetFSM().GetPrintJob(cmd.JobID); !exists {
+		respondProblem(c, codeNotFound, "print job not found")
 		return
 	}
 
-	// Create the command
-	cmd := &models.Command{
-		Type:      models.UpdatePrintJob,
-		JobID:     jobID,
-		NewStatus: newStatus,
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
 	}
+	cmd.ExpectedVersion = expectedVersion
 
 	// Apply the command
-	if err := h.Node.Apply(cmd); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.apply(c, cmd); err != nil {
+		respondError(c, err)
 		return
 	}
 
 	// Get the updated job
-	job, _ := h.Node.GetFSM().GetPrintJob(jobID)
+	job, exists := h.Node.GetFSM().GetPrintJob(cmd.JobID)
+	if exists {
+		setETag(c, job.Version)
+	}
 	c.JSON(http.StatusOK, job)
 }
+
+// DeletePrintJob deletes a Done or Canceled print job, honouring If-Match.
+// The job is archived like a purged one.
+func (h *Handler) DeletePrintJob(c *gin.Context) {
+	jobID := c.Param("id")
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	// Check if the job exists
+	if _, exists := h.Node.GetFSM().GetPrintJob(jobID); !exists {
+		respondProblem(c, codeNotFound, "print job not found")
+		return
+	}
+
+	// Create the command
+	cmd := &models.Command{
+		Type:            models.DeletePrintJob,
+		JobID:           jobID,
+		ExpectedVersion: expectedVersion,
+	}
+
+	// Apply the command
+	if err := h.apply(c, cmd); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// preparePrintJob validates a new print job and fills in what a client may
+// leave out
+func preparePrintJob(printJob *models.PrintJob) error {
+	// Generate an ID if not provided
+	if printJob.ID == "" {
+		printJob.ID = uuid.New().String()
+	}
+
+	// Force status to be "Queued"
+	printJob.Status = "Queued"
+	return models.ValidateID(printJob.ID)
+}
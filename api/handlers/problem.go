@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/devadigapratham/raft3d/raft"
+	"github.com/gin-gonic/gin"
+)
+
+// problemContentType is the media type of RFC 7807 problem details
+const problemContentType = "application/problem+json"
+
+// problemTypePrefix makes a problem code into the URI naming the problem
+// type
+const problemTypePrefix = "urn:raft3d:problem:"
+
+// Codes of the problems the API responds with
+const (
+	codeInvalidRequest       = "invalid_request"
+	codeUnauthenticated      = "unauthenticated"
+	codeForbidden            = "forbidden"
+	codeValidationFailed     = "validation_failed"
+	codeNotFound             = "not_found"
+	codeAlreadyExists        = "already_exists"
+	codeInUse                = "in_use"
+	codeJobNotFinished       = "job_not_finished"
+	codeInvalidTransition    = "invalid_transition"
+	codeInsufficientFilament = "insufficient_filament"
+	codeVersionConflict      = "version_conflict"
+	codePreconditionFailed   = "precondition_failed"
+	codeHistoryUnavailable   = "history_unavailable"
+	codeResyncRequired       = "resync_required"
+	codeNotLeader            = "not_leader"
+	codeUnavailable          = "unavailable"
+	codeInternal             = "internal_error"
+)
+
+// problemType is the status and title every problem with a code shares
+type problemType struct {
+	status int
+	title  string
+}
+
+var problemTypes = map[string]problemType{
+	codeInvalidRequest:       {http.StatusBadRequest, "The request is malformed"},
+	codeUnauthenticated:      {http.StatusUnauthorized, "The request is not authenticated"},
+	codeForbidden:            {http.StatusForbidden, "The caller's role doesn't allow the request"},
+	codeValidationFailed:     {http.StatusUnprocessableEntity, "The request is invalid"},
+	codeNotFound:             {http.StatusNotFound, "The resource does not exist"},
+	codeAlreadyExists:        {http.StatusConflict, "The resource already exists"},
+	codeInUse:                {http.StatusConflict, "The resource is in use"},
+	codeJobNotFinished:       {http.StatusConflict, "The print job has not finished"},
+	codeInvalidTransition:    {http.StatusConflict, "The print job can't move to that status"},
+	codeInsufficientFilament: {http.StatusUnprocessableEntity, "Not enough filament"},
+	codeVersionConflict:      {http.StatusPreconditionFailed, "The resource has changed"},
+	codePreconditionFailed:   {http.StatusPreconditionFailed, "A precondition failed"},
+	codeHistoryUnavailable:   {http.StatusGone, "The state at that point is no longer kept"},
+	codeResyncRequired:       {http.StatusGone, "The changes since that index are no longer kept"},
+	codeNotLeader:            {http.StatusServiceUnavailable, "This node is not the leader"},
+	codeUnavailable:          {http.StatusServiceUnavailable, "The cluster can't commit commands"},
+	codeInternal:             {http.StatusInternalServerError, "Internal error"},
+}
+
+// errorCodes maps the kinds of errors from the FSM and node to the codes of
+// the problems they are reported as, most specific first
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{raft.ErrVersionConflict, codeVersionConflict},
+	{raft.ErrAlreadyExists, codeAlreadyExists},
+	{raft.ErrInUse, codeInUse},
+	{raft.ErrJobNotFinished, codeJobNotFinished},
+	{raft.ErrInvalidTransition, codeInvalidTransition},
+	{raft.ErrInsufficientFilament, codeInsufficientFilament},
+	{raft.ErrNotFound, codeNotFound},
+	{raft.ErrValidation, codeValidationFailed},
+	{raft.ErrHistoryUnavailable, codeHistoryUnavailable},
+	{raft.ErrUnavailable, codeUnavailable},
+	{errUnauthenticated, codeUnauthenticated},
+	{errForbidden, codeForbidden},
+}
+
+// errorCode returns the code of the problem an error is reported as
+func errorCode(err error) string {
+	for _, ec := range errorCodes {
+		if errors.Is(err, ec.err) {
+			return ec.code
+		}
+	}
+	return codeInternal
+}
+
+// respondProblem responds with the problem of a code, described by detail.
+// extensions add members to the problem.
+func respondProblem(c *gin.Context, code, detail string, extensions ...gin.H) {
+	pt, ok := problemTypes[code]
+	if !ok {
+		code, pt = codeInternal, problemTypes[codeInternal]
+	}
+
+	problem := gin.H{}
+	for _, ext := range extensions {
+		for k, v := range ext {
+			problem[k] = v
+		}
+	}
+	problem["type"] = problemTypePrefix + code
+	problem["title"] = pt.title
+	problem["status"] = pt.status
+	problem["code"] = code
+	if detail != "" {
+		problem["detail"] = detail
+	}
+	problem["instance"] = c.Request.URL.Path
+	if requestID := c.GetString(contextRequestID); requestID != "" {
+		problem["request_id"] = requestID
+	}
+
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(pt.status, problem)
+}
+
+// respondError responds with the problem an error is reported as
+func respondError(c *gin.Context, err error, extensions ...gin.H) {
+	respondProblem(c, errorCode(err), err.Error(), extensions...)
+}
+
+// NoRoute responds to requests for paths the API doesn't serve
+func (h *Handler) NoRoute(c *gin.Context) {
+	respondProblem(c, codeNotFound, "no such endpoint")
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/devadigapratham/raft3d/api/models"
+	"github.com/devadigapratham/raft3d/raft"
+	"github.com/gin-gonic/gin"
+)
+
+// maxTransactionOperations bounds the size of a single log entry
+const maxTransactionOperations = 100
+
+// prepareOperation validates an operation of a transaction the way the
+// endpoint for it would and fills in the same defaults
+func prepareOperation(op *models.Command) error {
+	// Who issued an operation is whoever sent the transaction
+	if op.Actor != "" || op.ClientIP != "" || op.RequestID != "" {
+		return errors.New("actor, client_ip and request_id come from the request, not from its operations")
+	}
+
+	switch op.Type {
+	case models.AddPrinter, models.UpsertPrinter:
+		if op.Printer == nil {
+			return errors.New("printer is required")
+		}
+		return preparePrinter(op.Printer)
+
+	case models.AddFilament, models.UpsertFilament:
+		if op.Filament == nil {
+			return errors.New("filament is required")
+		}
+		return prepareFilament(op.Filament)
+
+	case models.AddPrintJob, models.UpsertPrintJob:
+		if op.PrintJob == nil {
+			return errors.New("print_job is required")
+		}
+		return preparePrintJob(op.PrintJob)
+
+	case models.UpdatePrinter:
+		if op.PrinterID == "" || len(op.Patch) == 0 {
+			return errors.New("printer_id and patch are required")
+		}
+
+	case models.DeletePrinter:
+		if op.PrinterID == "" {
+			return errors.New("printer_id is required")
+		}
+		if op.Cascade != "" && op.Cascade != raft.CascadeCancel {
+			return errors.New("invalid cascade, expected cancel")
+		}
+
+	case models.UpdateFilament:
+		if op.FilamentID == "" || len(op.Patch) == 0 {
+			return errors.New("filament_id and patch are required")
+		}
+
+	case models.DeleteFilament:
+		if op.FilamentID == "" {
+			return errors.New("filament_id is required")
+		}
+		if op.Cascade != "" && op.Cascade != raft.CascadeCancel {
+			return errors.New("invalid cascade, expected cancel")
+		}
+
+	case models.AdjustFilamentWeight:
+		if op.FilamentID == "" || op.DeltaGrams == 0 || op.Reason == "" {
+			return errors.New("filament_id, a non-zero delta_grams and reason are required")
+		}
+
+	case models.UpdatePrintJob:
+		if op.JobID == "" {
+			return errors.New("job_id is required")
+		}
+		if !models.IsValidPrintJobStatus(op.NewStatus) {
+			return errors.New("invalid status")
+		}
+
+	case models.DeletePrintJob:
+		if op.JobID == "" {
+			return errors.New("job_id is required")
+		}
+
+	default:
+		return fmt.Errorf("unsupported operation type: %s", op.Type)
+	}
+	return nil
+}
+
+// CreateTransaction applies a list of operations all-or-nothing in a single
+// log entry, provided its preconditions hold. A failed precondition answers
+// 412 and a failed operation as the operation alone would, naming which one
+// failed.
+func (h *Handler) CreateTransaction(c *gin.Context) {
+	var transaction models.Transaction
+	if err := c.ShouldBindJSON(&transaction); err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	if len(transaction.Operations) == 0 {
+		respondProblem(c, codeInvalidRequest, "a transaction needs at least one operation")
+		return
+	}
+	if len(transaction.Operations) > maxTransactionOperations {
+		respondProblem(c, codeInvalidRequest, fmt.Sprintf("a transaction can have at most %d operations", maxTransactionOperations))
+		return
+	}
+	for i, op := range transaction.Operations {
+		if op == nil {
+			respondProblem(c, codeInvalidRequest, "operation is null", gin.H{"operation": i})
+			return
+		}
+		if err := prepareOperation(op); err != nil {
+			respondProblem(c, codeValidationFailed, err.Error(), gin.H{"operation": i})
+			return
+		}
+	}
+
+	cmd := &models.Command{
+		Type:        models.CommitTransaction,
+		Transaction: &transaction,
+	}
+
+	result, err := h.applyWithResult(c, cmd)
+	var txErr *raft.TransactionError
+	if errors.As(err, &txErr) {
+		failed := gin.H{txErr.Stage: txErr.Index}
+		if txErr.Stage == raft.TransactionStagePrecondition && !errors.Is(txErr, raft.ErrValidation) {
+			respondProblem(c, codePreconditionFailed, txErr.Err.Error(), failed)
+			return
+		}
+		respondError(c, txErr.Err, failed)
+		return
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
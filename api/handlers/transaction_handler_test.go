@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+func TestPrepareOperationRejectsIssuer(t *testing.T) {
+	for _, op := range []*models.Command{
+		{Type: models.DeletePrintJob, JobID: "j1", Actor: "mallory"},
+		{Type: models.DeletePrintJob, JobID: "j1", ClientIP: "10.0.0.1"},
+		{Type: models.DeletePrintJob, JobID: "j1", RequestID: "forged"},
+	} {
+		if err := prepareOperation(op); err == nil {
+			t.Errorf("operation %+v was accepted", op)
+		}
+	}
+	if err := prepareOperation(&models.Command{Type: models.DeletePrintJob, JobID: "j1"}); err != nil {
+		t.Errorf("operation without an issuer was refused: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/devadigapratham/raft3d/api/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+// ValidationMiddleware checks the query parameters and JSON body of a
+// request against the operation the API description has for its route.
+// Malformed parameters and bodies answer 400, bodies that don't match their
+// schema 422. Handlers still check what the description can't express.
+func (h *Handler) ValidationMiddleware(doc *openapi.Document) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		op := doc.Operation(c.Request.Method, c.FullPath())
+		if op == nil {
+			c.Next()
+			return
+		}
+
+		for _, p := range op.Parameters {
+			if p.In != "query" {
+				continue
+			}
+			value, ok := c.GetQuery(p.Name)
+			if !ok {
+				if p.Required {
+					respondProblem(c, codeInvalidRequest, p.Name+" is required")
+					return
+				}
+				continue
+			}
+			if err := doc.ValidateParameter(p, value); err != nil {
+				respondProblem(c, codeInvalidRequest, err.Error())
+				return
+			}
+		}
+
+		// Handlers read JSON whatever the content type says, so bodies of
+		// types the operation doesn't take are checked as JSON. Media types
+		// without a schema are left to the handler.
+		if op.RequestBody != nil {
+			media, ok := op.RequestBody.Content[c.ContentType()]
+			if !ok {
+				media, ok = op.RequestBody.Content["application/json"]
+			}
+			if ok && media.Schema != nil && !validateBody(c, doc, op.RequestBody.Required, media.Schema) {
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// validateBody checks a JSON request body against its schema, leaving the
+// body for the handler to read again. It responds and returns false if the
+// body is invalid.
+func validateBody(c *gin.Context, doc *openapi.Document, required bool, schema *openapi.Schema) bool {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, "failed to read request body")
+		return false
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if required {
+			respondProblem(c, codeInvalidRequest, "request body is required")
+			return false
+		}
+		return true
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		respondProblem(c, codeInvalidRequest, "invalid JSON: "+err.Error())
+		return false
+	}
+
+	if err := doc.Validate(schema, value, "body"); err != nil {
+		var verr *openapi.ValidationError
+		if errors.As(err, &verr) {
+			respondProblem(c, codeValidationFailed, verr.Error(), gin.H{"location": verr.Location})
+			return false
+		}
+		respondError(c, err)
+		return false
+	}
+	return true
+}
+
+// GetOpenAPI returns the OpenAPI description of the API
+func (h *Handler) GetOpenAPI(doc *openapi.Document) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, doc)
+	}
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devadigapratham/raft3d/raft"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// watchBatchSize is the number of changes read from the history at once
+	watchBatchSize = 100
+	// watchHeartbeat is how often an idle SSE stream reports it is alive
+	watchHeartbeat = 15 * time.Second
+
+	defaultLongPollTimeout = 30 * time.Second
+	maxLongPollTimeout     = 60 * time.Second
+)
+
+// parseWatchResources parses the comma-separated resources query parameter
+func parseWatchResources(resources string) ([]string, error) {
+	if resources == "" {
+		return nil, nil
+	}
+
+	valid := raft.ResourceTypes()
+	var types []string
+	for _, resource := range strings.Split(resources, ",") {
+		resource = strings.TrimSpace(resource)
+		found := false
+		for _, v := range valid {
+			if resource == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown resource %q, expected one of %s", resource, strings.Join(valid, ", "))
+		}
+		types = append(types, resource)
+	}
+	return types, nil
+}
+
+// Watch streams the changes made to resources after since_index, as
+// server-sent events when the client asks for text/event-stream (or passes
+// mode=sse) and as a long-poll otherwise. Clients resume from the last index
+// they saw; if its changes are no longer retained they have to resync.
+func (h *Handler) Watch(c *gin.Context) {
+	types, err := parseWatchResources(c.Query("resources"))
+	if err != nil {
+		respondProblem(c, codeInvalidRequest, err.Error())
+		return
+	}
+
+	// Resume from since_index, or from the ID of the last event an SSE
+	// client saw. Without either, only changes from now on are reported.
+	sinceStr := c.Query("since_index")
+	if sinceStr == "" {
+		sinceStr = c.GetHeader("Last-Event-ID")
+	}
+	since := h.Node.GetFSM().AppliedIndex()
+	if sinceStr != "" {
+		since, err = strconv.ParseUint(sinceStr, 10, 64)
+		if err != nil {
+			respondProblem(c, codeInvalidRequest, "invalid since_index")
+			return
+		}
+	}
+
+	if c.Query("mode") == "sse" || strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+		h.watchSSE(c, types, since)
+		return
+	}
+	h.watchLongPoll(c, types, since)
+}
+
+// watchLongPoll responds with the next batch of changes, waiting for one
+// until the timeout
+func (h *Handler) watchLongPoll(c *gin.Context, types []string, since uint64) {
+	timeout := defaultLongPollTimeout
+	if s := c.Query("timeout"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			respondProblem(c, codeInvalidRequest, "invalid timeout")
+			return
+		}
+		if d < maxLongPollTimeout {
+			timeout = d
+		} else {
+			timeout = maxLongPollTimeout
+		}
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		feed, err := h.Node.GetFSM().ChangesSince(since, types, watchBatchSize)
+		if errors.Is(err, raft.ErrResyncRequired) {
+			respondProblem(c, codeResyncRequired, err.Error(), gin.H{"resync_required": true})
+			return
+		}
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		if len(feed.Changes) > 0 {
+			c.JSON(http.StatusOK, gin.H{"changes": feed.Changes, "last_index": feed.LastIndex})
+			return
+		}
+		since = feed.LastIndex
+
+		select {
+		case <-feed.Updated:
+		case <-deadline.C:
+			c.JSON(http.StatusOK, gin.H{"changes": feed.Changes, "last_index": feed.LastIndex})
+			return
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
+
+// watchSSE streams changes as server-sent events until the client goes away.
+// Each entry's last change carries the entry's index as its event ID, so a
+// reconnecting client never resumes halfway through an entry.
+func (h *Handler) watchSSE(c *gin.Context, types []string, since uint64) {
+	heartbeat := time.NewTicker(watchHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		feed, err := h.Node.GetFSM().ChangesSince(since, types, watchBatchSize)
+		if errors.Is(err, raft.ErrResyncRequired) {
+			c.Render(-1, sse.Event{
+				Event: "resync",
+				Data:  gin.H{"error": err.Error(), "resync_required": true},
+			})
+			c.Writer.Flush()
+			return
+		}
+		if err != nil {
+			c.Render(-1, sse.Event{Event: "error", Data: gin.H{"error": err.Error()}})
+			c.Writer.Flush()
+			return
+		}
+
+		for i, change := range feed.Changes {
+			event := sse.Event{Event: "change", Data: change}
+			if i == len(feed.Changes)-1 || feed.Changes[i+1].Index != change.Index {
+				event.Id = strconv.FormatUint(change.Index, 10)
+			}
+			c.Render(-1, event)
+		}
+		c.Writer.Flush()
+		since = feed.LastIndex
+
+		// More may be waiting if the batch was full
+		if len(feed.Changes) >= watchBatchSize {
+			continue
+		}
+
+		select {
+		case <-feed.Updated:
+		case <-heartbeat.C:
+			c.Render(-1, sse.Event{
+				Event: "heartbeat",
+				Id:    strconv.FormatUint(since, 10),
+				Data:  gin.H{"last_index": since},
+			})
+			c.Writer.Flush()
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
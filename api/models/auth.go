@@ -0,0 +1,102 @@
+// api/models/auth.go
+package models
+
+import "time"
+
+// Role is what a caller of the API may do. Each role may do everything the
+// roles before it may.
+type Role string
+
+const (
+	// RoleViewer may read resources
+	RoleViewer Role = "viewer"
+	// RoleOperator may also change printers, filaments and print jobs
+	RoleOperator Role = "operator"
+	// RoleAdmin may also manage API keys and role bindings, and use the
+	// cluster operations
+	RoleAdmin Role = "admin"
+)
+
+// roleRanks orders the roles by what they may do
+var roleRanks = map[Role]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+// IsValidRole checks if a role is valid
+func IsValidRole(role Role) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// Allows reports whether a role may do what the required role may
+func (r Role) Allows(required Role) bool {
+	return IsValidRole(r) && roleRanks[r] >= roleRanks[required]
+}
+
+// APIKey is a key callers authenticate with. Only hashes of its secret are
+// stored; the secret itself is shown once, when the key is created or
+// rotated.
+type APIKey struct {
+	ID          string `json:"id"`
+	Description string `json:"description,omitempty"`
+	Role        Role   `json:"role"`
+
+	// SecretHash is the hex SHA-256 of the key's secret
+	SecretHash string `json:"secret_hash,omitempty"`
+	// PreviousSecretHash is the hash of the secret the last rotation
+	// replaced, which stays valid until PreviousExpiresAt
+	PreviousSecretHash string     `json:"previous_secret_hash,omitempty"`
+	PreviousExpiresAt  *time.Time `json:"previous_expires_at,omitempty"`
+
+	// ExpiresAt is when the key stops working, if ever
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+
+	// Version is the raft index of the last change to the key
+	Version uint64 `json:"version"`
+	// CreatedIndex is the raft index the key was created at
+	CreatedIndex uint64 `json:"created_index"`
+}
+
+// SetVersion sets the version of the key
+func (k *APIKey) SetVersion(version uint64) {
+	k.Version = version
+}
+
+// SetCreatedIndex sets the index the key was created at
+func (k *APIKey) SetCreatedIndex(index uint64) {
+	k.CreatedIndex = index
+}
+
+// Redacted returns a copy of the key without its secret hashes, as shown to
+// clients
+func (k *APIKey) Redacted() *APIKey {
+	clone := *k
+	clone.SecretHash = ""
+	clone.PreviousSecretHash = ""
+	return &clone
+}
+
+// RoleBinding gives the callers a JWT names as its subject a role
+type RoleBinding struct {
+	Subject string `json:"subject"`
+	Role    Role   `json:"role"`
+
+	// Version is the raft index of the last change to the binding
+	Version uint64 `json:"version"`
+	// CreatedIndex is the raft index the binding was created at
+	CreatedIndex uint64 `json:"created_index"`
+}
+
+// SetVersion sets the version of the binding
+func (b *RoleBinding) SetVersion(version uint64) {
+	b.Version = version
+}
+
+// SetCreatedIndex sets the index the binding was created at
+func (b *RoleBinding) SetCreatedIndex(index uint64) {
+	b.CreatedIndex = index
+}
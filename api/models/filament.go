@@ -8,4 +8,19 @@ type Filament struct {
 	Color                  string `json:"color"`
 	TotalWeightInGrams     int    `json:"total_weight_in_grams"`
 	RemainingWeightInGrams int    `json:"remaining_weight_in_grams"`
+
+	// Version is the raft index of the last change to the filament
+	Version uint64 `json:"version"`
+	// CreatedIndex is the raft index the filament was created at
+	CreatedIndex uint64 `json:"created_index"`
+}
+
+// SetVersion sets the version of the filament
+func (f *Filament) SetVersion(version uint64) {
+	f.Version = version
+}
+
+// SetCreatedIndex sets the index the filament was created at
+func (f *Filament) SetCreatedIndex(index uint64) {
+	f.CreatedIndex = index
 }
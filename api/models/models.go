@@ -4,6 +4,7 @@ package models
 import (
 	"encoding/json"
 	"errors"
+	"regexp"
 	"strings"
 )
 
@@ -11,20 +12,98 @@ import (
 type CommandType string
 
 const (
-	AddPrinter     CommandType = "ADD_PRINTER"
-	AddFilament    CommandType = "ADD_FILAMENT"
-	AddPrintJob    CommandType = "ADD_PRINT_JOB"
-	UpdatePrintJob CommandType = "UPDATE_PRINT_JOB"
+	AddPrinter           CommandType = "ADD_PRINTER"
+	AddFilament          CommandType = "ADD_FILAMENT"
+	AddPrintJob          CommandType = "ADD_PRINT_JOB"
+	UpdatePrintJob       CommandType = "UPDATE_PRINT_JOB"
+	UpsertPrinter        CommandType = "UPSERT_PRINTER"
+	UpsertFilament       CommandType = "UPSERT_FILAMENT"
+	UpsertPrintJob       CommandType = "UPSERT_PRINT_JOB"
+	PurgePrintJobs       CommandType = "PURGE_PRINT_JOBS"
+	UpdatePrinter        CommandType = "UPDATE_PRINTER"
+	DeletePrinter        CommandType = "DELETE_PRINTER"
+	UpdateFilament       CommandType = "UPDATE_FILAMENT"
+	DeleteFilament       CommandType = "DELETE_FILAMENT"
+	AdjustFilamentWeight CommandType = "ADJUST_FILAMENT_WEIGHT"
+	DeletePrintJob       CommandType = "DELETE_PRINT_JOB"
+	// ImportPrintJob creates a print job with the status and history it
+	// had where it was exported from
+	ImportPrintJob    CommandType = "IMPORT_PRINT_JOB"
+	CommitTransaction CommandType = "TRANSACTION"
+
+	// Commands managing who may use the API. They can't be part of a
+	// transaction.
+	CreateAPIKey      CommandType = "CREATE_API_KEY"
+	RotateAPIKey      CommandType = "ROTATE_API_KEY"
+	DeleteAPIKey      CommandType = "DELETE_API_KEY"
+	SetRoleBinding    CommandType = "SET_ROLE_BINDING"
+	DeleteRoleBinding CommandType = "DELETE_ROLE_BINDING"
+
+	// ReportDivergence records in the audit log that replicas' state
+	// digests differ from the leader's, described by the reason. It
+	// changes nothing and can't be part of a transaction.
+	ReportDivergence CommandType = "REPORT_DIVERGENCE"
 )
 
+// IsAuthCommand reports whether a command type manages API keys or role
+// bindings
+func IsAuthCommand(t CommandType) bool {
+	switch t {
+	case CreateAPIKey, RotateAPIKey, DeleteAPIKey, SetRoleBinding, DeleteRoleBinding:
+		return true
+	}
+	return false
+}
+
+// AnyVersion as a command's ExpectedVersion matches any version of the
+// resource, as long as it exists. Versions are raft indexes, which never
+// get this high.
+const AnyVersion = ^uint64(0)
+
 // Command represents a command to be applied to the FSM
 type Command struct {
-	Type      CommandType `json:"type"`
-	Printer   *Printer    `json:"printer,omitempty"`
-	Filament  *Filament   `json:"filament,omitempty"`
-	PrintJob  *PrintJob   `json:"print_job,omitempty"`
-	JobID     string      `json:"job_id,omitempty"`
-	NewStatus string      `json:"new_status,omitempty"`
+	Type       CommandType `json:"type"`
+	Printer    *Printer    `json:"printer,omitempty"`
+	Filament   *Filament   `json:"filament,omitempty"`
+	PrintJob   *PrintJob   `json:"print_job,omitempty"`
+	JobID      string      `json:"job_id,omitempty"`
+	NewStatus  string      `json:"new_status,omitempty"`
+	JobIDs     []string    `json:"job_ids,omitempty"`
+	PrinterID  string      `json:"printer_id,omitempty"`
+	FilamentID string      `json:"filament_id,omitempty"`
+	DeltaGrams int         `json:"delta_grams,omitempty"`
+
+	// ConsumedGrams is the filament a Running job used up before it was
+	// canceled
+	ConsumedGrams int `json:"consumed_grams,omitempty"`
+
+	// Reason says why the command was issued, for the audit log
+	Reason string `json:"reason,omitempty"`
+
+	// Patch holds the fields an update command changes, as a JSON object
+	Patch json.RawMessage `json:"patch,omitempty"`
+	// Cascade says what deleting a resource does to the jobs still using
+	// it; empty refuses to delete it
+	Cascade string `json:"cascade,omitempty"`
+
+	Transaction *Transaction `json:"transaction,omitempty"`
+
+	APIKey      *APIKey      `json:"api_key,omitempty"`
+	KeyID       string       `json:"key_id,omitempty"`
+	RoleBinding *RoleBinding `json:"role_binding,omitempty"`
+	Subject     string       `json:"subject,omitempty"`
+	// GraceSeconds keeps the secret a rotation replaces valid for this
+	// long
+	GraceSeconds int64 `json:"grace_seconds,omitempty"`
+
+	// ExpectedVersion makes the command fail unless the resource it targets
+	// is at this version, or exists at all if it is AnyVersion
+	ExpectedVersion *uint64 `json:"expected_version,omitempty"`
+
+	// Who issued the command, for the audit log
+	Actor     string `json:"actor,omitempty"`
+	ClientIP  string `json:"client_ip,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
 }
 
 // Marshal serializes a command to JSON
@@ -39,6 +118,18 @@ func UnmarshalCommand(data []byte) (*Command, error) {
 	return &c, err
 }
 
+// idPattern is the format of client-supplied IDs. Generated IDs are UUIDs,
+// which fit it too.
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// ValidateID checks if an ID is well-formed
+func ValidateID(id string) error {
+	if !idPattern.MatchString(id) {
+		return errors.New("invalid id: use 1 to 64 letters, digits, '.', '_' or '-', starting with a letter or digit")
+	}
+	return nil
+}
+
 // ValidateStatus checks if a status transition is valid
 func ValidateStatusChange(currentStatus, newStatus string) error {
 	switch currentStatus {
@@ -69,6 +160,11 @@ func IsValidFilamentType(filamentType string) bool {
 	return false
 }
 
+// IsTerminalPrintJobStatus checks if a print job status is final
+func IsTerminalPrintJobStatus(status string) bool {
+	return status == "Done" || status == "Canceled"
+}
+
 // IsValidPrintJobStatus checks if a print job status is valid
 func IsValidPrintJobStatus(status string) bool {
 	validStatuses := []string{"Queued", "Running", "Done", "Canceled"}
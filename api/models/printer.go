@@ -6,4 +6,19 @@ type Printer struct {
 	ID      string `json:"id"`
 	Company string `json:"company"`
 	Model   string `json:"model"`
+
+	// Version is the raft index of the last change to the printer
+	Version uint64 `json:"version"`
+	// CreatedIndex is the raft index the printer was created at
+	CreatedIndex uint64 `json:"created_index"`
+}
+
+// SetVersion sets the version of the printer
+func (p *Printer) SetVersion(version uint64) {
+	p.Version = version
+}
+
+// SetCreatedIndex sets the index the printer was created at
+func (p *Printer) SetCreatedIndex(index uint64) {
+	p.CreatedIndex = index
 }
@@ -1,6 +1,8 @@
 // api/models/printjob.go
 package models
 
+import "time"
+
 // PrintJob represents a 3D printing job
 type PrintJob struct {
 	ID                 string `json:"id"`
@@ -9,4 +11,37 @@ type PrintJob struct {
 	Filepath           string `json:"filepath"`
 	PrintWeightInGrams int    `json:"print_weight_in_grams"`
 	Status             string `json:"status"` // Queued, Running, Done, Canceled
+
+	// FinishedAt is when the job became Done or Canceled
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+
+	// Transitions is the status history of the job, oldest first
+	Transitions []StatusTransition `json:"transitions,omitempty"`
+
+	// Version is the raft index of the last change to the print job
+	Version uint64 `json:"version"`
+	// CreatedIndex is the raft index the print job was created at
+	CreatedIndex uint64 `json:"created_index"`
+}
+
+// StatusTransition is a status change of a print job
+type StatusTransition struct {
+	// From is empty for the transition that created the job
+	From   string    `json:"from,omitempty"`
+	To     string    `json:"to"`
+	At     time.Time `json:"at"`
+	Actor  string    `json:"actor,omitempty"`
+	Reason string    `json:"reason,omitempty"`
+	// ConsumedGrams is the filament used up by a canceled job
+	ConsumedGrams int `json:"consumed_grams,omitempty"`
+}
+
+// SetVersion sets the version of the print job
+func (p *PrintJob) SetVersion(version uint64) {
+	p.Version = version
+}
+
+// SetCreatedIndex sets the index the print job was created at
+func (p *PrintJob) SetCreatedIndex(index uint64) {
+	p.CreatedIndex = index
 }
@@ -0,0 +1,36 @@
+// api/models/transaction.go
+package models
+
+import "encoding/json"
+
+// Precondition types
+const (
+	// PreconditionExists requires the resource to exist
+	PreconditionExists = "exists"
+	// PreconditionNotExists requires the resource not to exist
+	PreconditionNotExists = "not_exists"
+	// PreconditionFieldEquals requires a top-level field of the resource to
+	// hold Value
+	PreconditionFieldEquals = "field_equals"
+	// PreconditionVersionEquals requires the resource to be at Version
+	PreconditionVersionEquals = "version_equals"
+)
+
+// Precondition is a condition on the state a transaction only applies in.
+// Resource is one of printers, filaments or print_jobs.
+type Precondition struct {
+	Type     string          `json:"type"`
+	Resource string          `json:"resource"`
+	ID       string          `json:"id"`
+	Field    string          `json:"field,omitempty"`
+	Value    json.RawMessage `json:"value,omitempty"`
+	Version  uint64          `json:"version,omitempty"`
+}
+
+// Transaction is a list of operations applied all-or-nothing, provided its
+// preconditions hold. Operations are commands of any type but CommitTransaction,
+// applied in order, each seeing the effects of the ones before it.
+type Transaction struct {
+	Preconditions []Precondition `json:"preconditions,omitempty"`
+	Operations    []*Command     `json:"operations"`
+}
@@ -0,0 +1,221 @@
+// Package openapi describes the HTTP API as an OpenAPI 3 document and
+// validates requests against it
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Version is the OpenAPI version documents are written in
+const Version = "3.0.3"
+
+// Document is an OpenAPI document, limited to what the API uses
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+
+	// Security lists the ways of authenticating operations accept, any
+	// one of which will do
+	Security []SecurityRequirement `json:"security,omitempty"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Components holds the schemas and security schemes operations refer to
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme is a way of authenticating
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Description  string `json:"description,omitempty"`
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// SecurityRequirement names the security schemes that together
+// authenticate a request
+type SecurityRequirement map[string][]string
+
+// PathItem holds the operations on a path, by lower case HTTP method
+type PathItem map[string]*Operation
+
+// Operation describes one route
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+
+	// RequiredRole is the least role a caller needs for the operation,
+	// written as the x-required-role extension
+	RequiredRole string `json:"x-required-role,omitempty"`
+}
+
+// Parameter is a path, query or header parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is the body an operation takes
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response is a response an operation gives
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a body
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Schema is a JSON schema, as far as OpenAPI 3.0 and the API use it
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// New creates an empty document
+func New(title, version string) *Document {
+	return &Document{
+		OpenAPI:    Version,
+		Info:       Info{Title: title, Version: version},
+		Paths:      make(map[string]*PathItem),
+		Components: Components{Schemas: make(map[string]*Schema)},
+	}
+}
+
+// Add adds the operation on method and path. Paths may use gin's :param
+// syntax.
+func (d *Document) Add(method, path string, op *Operation) {
+	path = Path(path)
+	item, ok := d.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+	(*item)[strings.ToLower(method)] = op
+}
+
+// Operation returns the operation on method and path, or nil if there is
+// none. Paths may use gin's :param syntax.
+func (d *Document) Operation(method, path string) *Operation {
+	item, ok := d.Paths[Path(path)]
+	if !ok {
+		return nil
+	}
+	return (*item)[strings.ToLower(method)]
+}
+
+// Route is an HTTP method and path
+type Route struct {
+	Method string
+	Path   string
+}
+
+// Drift lists the routes that are served but not documented and the
+// operations that are documented but not served. Both are empty when the
+// document matches the routes.
+func (d *Document) Drift(routes []Route) (undocumented, unserved []string) {
+	served := make(map[string]bool)
+	for _, r := range routes {
+		key := strings.ToUpper(r.Method) + " " + Path(r.Path)
+		served[key] = true
+		if d.Operation(r.Method, r.Path) == nil {
+			undocumented = append(undocumented, key)
+		}
+	}
+	for path, item := range d.Paths {
+		for method := range *item {
+			key := strings.ToUpper(method) + " " + path
+			if !served[key] {
+				unserved = append(unserved, key)
+			}
+		}
+	}
+	sort.Strings(undocumented)
+	sort.Strings(unserved)
+	return undocumented, unserved
+}
+
+// CheckDrift fails if the document and the routes disagree
+func (d *Document) CheckDrift(routes []Route) error {
+	undocumented, unserved := d.Drift(routes)
+	if len(undocumented) == 0 && len(unserved) == 0 {
+		return nil
+	}
+	var parts []string
+	if len(undocumented) > 0 {
+		parts = append(parts, "undocumented routes: "+strings.Join(undocumented, ", "))
+	}
+	if len(unserved) > 0 {
+		parts = append(parts, "documented but not served: "+strings.Join(unserved, ", "))
+	}
+	return fmt.Errorf("API description out of date: %s", strings.Join(parts, "; "))
+}
+
+// Path turns gin's :param and *param path segments into OpenAPI's {param}
+func Path(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Ref returns a schema referring to a component schema
+func Ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// resolve follows a schema's $ref
+func (d *Document) resolve(s *Schema) (*Schema, error) {
+	for s != nil && s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+		next, ok := d.Components.Schemas[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown schema %s", s.Ref)
+		}
+		s = next
+	}
+	return s, nil
+}
@@ -0,0 +1,187 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError is a value that doesn't match its schema
+type ValidationError struct {
+	// Location names the value, such as "body.printer.print_weight_in_grams"
+	// or "query.limit"
+	Location string
+	Message  string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Location, e.Message)
+}
+
+func invalid(at, format string, args ...interface{}) error {
+	return &ValidationError{Location: at, Message: fmt.Sprintf(format, args...)}
+}
+
+// Validate checks a value decoded from JSON, with numbers as json.Number,
+// against a schema
+func (d *Document) Validate(s *Schema, v interface{}, at string) error {
+	s, err := d.resolve(s)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return nil
+	}
+	if v == nil {
+		if s.Nullable || s.Type == "" {
+			return nil
+		}
+		return invalid(at, "must not be null")
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return invalid(at, "must be an object")
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				return invalid(join(at, name), "is required")
+			}
+		}
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := s.Properties[name]
+			if !ok {
+				prop = s.AdditionalProperties
+			}
+			if err := d.Validate(prop, obj[name], join(at, name)); err != nil {
+				return err
+			}
+		}
+
+	case "array":
+		arr, ok := v.([]interface{})
+		if !ok {
+			return invalid(at, "must be an array")
+		}
+		if s.MinItems != nil && len(arr) < *s.MinItems {
+			return invalid(at, "must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(arr) > *s.MaxItems {
+			return invalid(at, "must have at most %d items", *s.MaxItems)
+		}
+		for i, item := range arr {
+			if err := d.Validate(s.Items, item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return invalid(at, "must be a string")
+		}
+		if s.MinLength != nil && len(str) < *s.MinLength {
+			return invalid(at, "must be at least %d characters", *s.MinLength)
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				return invalid(at, "must be an RFC 3339 date-time")
+			}
+		}
+
+	case "integer", "number":
+		n, ok := v.(json.Number)
+		if !ok {
+			return invalid(at, "must be a number")
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return invalid(at, "must be a number")
+		}
+		if s.Type == "integer" && f != math.Trunc(f) {
+			return invalid(at, "must be an integer")
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			return invalid(at, "must be at least %v", *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			return invalid(at, "must be at most %v", *s.Maximum)
+		}
+
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return invalid(at, "must be a boolean")
+		}
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		return invalid(at, "must be one of %s", enumList(s.Enum))
+	}
+	return nil
+}
+
+// ValidateParameter parses a path, query or header parameter's value by its
+// schema and checks it
+func (d *Document) ValidateParameter(p *Parameter, raw string) error {
+	at := p.In + "." + p.Name
+	s, err := d.resolve(p.Schema)
+	if err != nil {
+		return err
+	}
+
+	var v interface{} = raw
+	switch s.Type {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			return invalid(at, "must be a number")
+		}
+		v = json.Number(raw)
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return invalid(at, "must be a boolean")
+		}
+		v = b
+	}
+	return d.Validate(s, v, at)
+}
+
+func join(at, name string) string {
+	if at == "" {
+		return name
+	}
+	return at + "." + name
+}
+
+func inEnum(enum []interface{}, v interface{}) bool {
+	for _, e := range enum {
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+		// Numbers are decoded as json.Number but declared as Go numbers
+		if n, ok := v.(json.Number); ok && fmt.Sprint(e) == n.String() {
+			return true
+		}
+	}
+	return false
+}
+
+func enumList(enum []interface{}) string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		values[i] = fmt.Sprint(e)
+	}
+	return strings.Join(values, ", ")
+}
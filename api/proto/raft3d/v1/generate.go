@@ -0,0 +1,9 @@
+// Package raft3dv1 holds the gRPC API of raft3d, generated from raft3d.proto
+package raft3dv1
+
+// The code is generated with protoc 25.3 and the plugins at the versions
+// below, which the headers of the generated files record
+//go:generate sh -c "protoc --version | grep -qx 'libprotoc 25.3' || { echo \"raft3d.proto is generated with protoc 25.3, found $(protoc --version)\" >&2; exit 1; }"
+//go:generate go install google.golang.org/protobuf/cmd/protoc-gen-go@v1.34.1
+//go:generate go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@v1.5.1
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative raft3d.proto
@@ -0,0 +1,1222 @@
+// The gRPC API of raft3d. It serves the same resources as the REST API
+// under /api/v1, backed by the same Raft log, and reports failures with the
+// gRPC status codes matching the REST problem codes.
+//
+// Writes only succeed on the leader. A follower answers them with
+// UNAVAILABLE and the raft3d-leader and raft3d-leader-id response metadata,
+// naming the Raft address and ID of the leader to retry against.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v4.25.3
+// source: raft3d.proto
+
+package raft3dv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PrinterService_CreatePrinter_FullMethodName      = "/raft3d.v1.PrinterService/CreatePrinter"
+	PrinterService_GetPrinter_FullMethodName         = "/raft3d.v1.PrinterService/GetPrinter"
+	PrinterService_ListPrinters_FullMethodName       = "/raft3d.v1.PrinterService/ListPrinters"
+	PrinterService_UpdatePrinter_FullMethodName      = "/raft3d.v1.PrinterService/UpdatePrinter"
+	PrinterService_DeletePrinter_FullMethodName      = "/raft3d.v1.PrinterService/DeletePrinter"
+	PrinterService_GetCurrentPrintJob_FullMethodName = "/raft3d.v1.PrinterService/GetCurrentPrintJob"
+)
+
+// PrinterServiceClient is the client API for PrinterService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PrinterService manages printers
+type PrinterServiceClient interface {
+	// CreatePrinter creates a printer, generating its ID if it has none
+	CreatePrinter(ctx context.Context, in *CreatePrinterRequest, opts ...grpc.CallOption) (*Printer, error)
+	// GetPrinter returns a printer by ID
+	GetPrinter(ctx context.Context, in *GetPrinterRequest, opts ...grpc.CallOption) (*Printer, error)
+	// ListPrinters returns a page of the printers that pass a filter
+	ListPrinters(ctx context.Context, in *ListPrintersRequest, opts ...grpc.CallOption) (*ListPrintersResponse, error)
+	// UpdatePrinter changes the fields of a printer that are set
+	UpdatePrinter(ctx context.Context, in *UpdatePrinterRequest, opts ...grpc.CallOption) (*Printer, error)
+	// DeletePrinter deletes a printer
+	DeletePrinter(ctx context.Context, in *DeletePrinterRequest, opts ...grpc.CallOption) (*DeletePrinterResponse, error)
+	// GetCurrentPrintJob returns the print job running on a printer
+	GetCurrentPrintJob(ctx context.Context, in *GetCurrentPrintJobRequest, opts ...grpc.CallOption) (*PrintJob, error)
+}
+
+type printerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPrinterServiceClient(cc grpc.ClientConnInterface) PrinterServiceClient {
+	return &printerServiceClient{cc}
+}
+
+func (c *printerServiceClient) CreatePrinter(ctx context.Context, in *CreatePrinterRequest, opts ...grpc.CallOption) (*Printer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Printer)
+	err := c.cc.Invoke(ctx, PrinterService_CreatePrinter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *printerServiceClient) GetPrinter(ctx context.Context, in *GetPrinterRequest, opts ...grpc.CallOption) (*Printer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Printer)
+	err := c.cc.Invoke(ctx, PrinterService_GetPrinter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *printerServiceClient) ListPrinters(ctx context.Context, in *ListPrintersRequest, opts ...grpc.CallOption) (*ListPrintersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPrintersResponse)
+	err := c.cc.Invoke(ctx, PrinterService_ListPrinters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *printerServiceClient) UpdatePrinter(ctx context.Context, in *UpdatePrinterRequest, opts ...grpc.CallOption) (*Printer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Printer)
+	err := c.cc.Invoke(ctx, PrinterService_UpdatePrinter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *printerServiceClient) DeletePrinter(ctx context.Context, in *DeletePrinterRequest, opts ...grpc.CallOption) (*DeletePrinterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeletePrinterResponse)
+	err := c.cc.Invoke(ctx, PrinterService_DeletePrinter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *printerServiceClient) GetCurrentPrintJob(ctx context.Context, in *GetCurrentPrintJobRequest, opts ...grpc.CallOption) (*PrintJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PrintJob)
+	err := c.cc.Invoke(ctx, PrinterService_GetCurrentPrintJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PrinterServiceServer is the server API for PrinterService service.
+// All implementations must embed UnimplementedPrinterServiceServer
+// for forward compatibility.
+//
+// PrinterService manages printers
+type PrinterServiceServer interface {
+	// CreatePrinter creates a printer, generating its ID if it has none
+	CreatePrinter(context.Context, *CreatePrinterRequest) (*Printer, error)
+	// GetPrinter returns a printer by ID
+	GetPrinter(context.Context, *GetPrinterRequest) (*Printer, error)
+	// ListPrinters returns a page of the printers that pass a filter
+	ListPrinters(context.Context, *ListPrintersRequest) (*ListPrintersResponse, error)
+	// UpdatePrinter changes the fields of a printer that are set
+	UpdatePrinter(context.Context, *UpdatePrinterRequest) (*Printer, error)
+	// DeletePrinter deletes a printer
+	DeletePrinter(context.Context, *DeletePrinterRequest) (*DeletePrinterResponse, error)
+	// GetCurrentPrintJob returns the print job running on a printer
+	GetCurrentPrintJob(context.Context, *GetCurrentPrintJobRequest) (*PrintJob, error)
+	mustEmbedUnimplementedPrinterServiceServer()
+}
+
+// UnimplementedPrinterServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPrinterServiceServer struct{}
+
+func (UnimplementedPrinterServiceServer) CreatePrinter(context.Context, *CreatePrinterRequest) (*Printer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePrinter not implemented")
+}
+func (UnimplementedPrinterServiceServer) GetPrinter(context.Context, *GetPrinterRequest) (*Printer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPrinter not implemented")
+}
+func (UnimplementedPrinterServiceServer) ListPrinters(context.Context, *ListPrintersRequest) (*ListPrintersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPrinters not implemented")
+}
+func (UnimplementedPrinterServiceServer) UpdatePrinter(context.Context, *UpdatePrinterRequest) (*Printer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePrinter not implemented")
+}
+func (UnimplementedPrinterServiceServer) DeletePrinter(context.Context, *DeletePrinterRequest) (*DeletePrinterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePrinter not implemented")
+}
+func (UnimplementedPrinterServiceServer) GetCurrentPrintJob(context.Context, *GetCurrentPrintJobRequest) (*PrintJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCurrentPrintJob not implemented")
+}
+func (UnimplementedPrinterServiceServer) mustEmbedUnimplementedPrinterServiceServer() {}
+func (UnimplementedPrinterServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafePrinterServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PrinterServiceServer will
+// result in compilation errors.
+type UnsafePrinterServiceServer interface {
+	mustEmbedUnimplementedPrinterServiceServer()
+}
+
+func RegisterPrinterServiceServer(s grpc.ServiceRegistrar, srv PrinterServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPrinterServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PrinterService_ServiceDesc, srv)
+}
+
+func _PrinterService_CreatePrinter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePrinterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrinterServiceServer).CreatePrinter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrinterService_CreatePrinter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrinterServiceServer).CreatePrinter(ctx, req.(*CreatePrinterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrinterService_GetPrinter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPrinterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrinterServiceServer).GetPrinter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrinterService_GetPrinter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrinterServiceServer).GetPrinter(ctx, req.(*GetPrinterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrinterService_ListPrinters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPrintersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrinterServiceServer).ListPrinters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrinterService_ListPrinters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrinterServiceServer).ListPrinters(ctx, req.(*ListPrintersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrinterService_UpdatePrinter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePrinterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrinterServiceServer).UpdatePrinter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrinterService_UpdatePrinter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrinterServiceServer).UpdatePrinter(ctx, req.(*UpdatePrinterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrinterService_DeletePrinter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePrinterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrinterServiceServer).DeletePrinter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrinterService_DeletePrinter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrinterServiceServer).DeletePrinter(ctx, req.(*DeletePrinterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrinterService_GetCurrentPrintJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCurrentPrintJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrinterServiceServer).GetCurrentPrintJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrinterService_GetCurrentPrintJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrinterServiceServer).GetCurrentPrintJob(ctx, req.(*GetCurrentPrintJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PrinterService_ServiceDesc is the grpc.ServiceDesc for PrinterService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PrinterService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "raft3d.v1.PrinterService",
+	HandlerType: (*PrinterServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreatePrinter",
+			Handler:    _PrinterService_CreatePrinter_Handler,
+		},
+		{
+			MethodName: "GetPrinter",
+			Handler:    _PrinterService_GetPrinter_Handler,
+		},
+		{
+			MethodName: "ListPrinters",
+			Handler:    _PrinterService_ListPrinters_Handler,
+		},
+		{
+			MethodName: "UpdatePrinter",
+			Handler:    _PrinterService_UpdatePrinter_Handler,
+		},
+		{
+			MethodName: "DeletePrinter",
+			Handler:    _PrinterService_DeletePrinter_Handler,
+		},
+		{
+			MethodName: "GetCurrentPrintJob",
+			Handler:    _PrinterService_GetCurrentPrintJob_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "raft3d.proto",
+}
+
+const (
+	FilamentService_CreateFilament_FullMethodName          = "/raft3d.v1.FilamentService/CreateFilament"
+	FilamentService_GetFilament_FullMethodName             = "/raft3d.v1.FilamentService/GetFilament"
+	FilamentService_ListFilaments_FullMethodName           = "/raft3d.v1.FilamentService/ListFilaments"
+	FilamentService_UpdateFilament_FullMethodName          = "/raft3d.v1.FilamentService/UpdateFilament"
+	FilamentService_DeleteFilament_FullMethodName          = "/raft3d.v1.FilamentService/DeleteFilament"
+	FilamentService_AdjustFilamentWeight_FullMethodName    = "/raft3d.v1.FilamentService/AdjustFilamentWeight"
+	FilamentService_GetFilamentReservations_FullMethodName = "/raft3d.v1.FilamentService/GetFilamentReservations"
+)
+
+// FilamentServiceClient is the client API for FilamentService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FilamentService manages filaments
+type FilamentServiceClient interface {
+	// CreateFilament creates a filament, generating its ID if it has none
+	CreateFilament(ctx context.Context, in *CreateFilamentRequest, opts ...grpc.CallOption) (*Filament, error)
+	// GetFilament returns a filament by ID
+	GetFilament(ctx context.Context, in *GetFilamentRequest, opts ...grpc.CallOption) (*Filament, error)
+	// ListFilaments returns a page of the filaments that pass a filter
+	ListFilaments(ctx context.Context, in *ListFilamentsRequest, opts ...grpc.CallOption) (*ListFilamentsResponse, error)
+	// UpdateFilament changes the fields of a filament that are set
+	UpdateFilament(ctx context.Context, in *UpdateFilamentRequest, opts ...grpc.CallOption) (*Filament, error)
+	// DeleteFilament deletes a filament
+	DeleteFilament(ctx context.Context, in *DeleteFilamentRequest, opts ...grpc.CallOption) (*DeleteFilamentResponse, error)
+	// AdjustFilamentWeight adds to or takes from the remaining weight of a
+	// filament
+	AdjustFilamentWeight(ctx context.Context, in *AdjustFilamentWeightRequest, opts ...grpc.CallOption) (*Filament, error)
+	// GetFilamentReservations returns how much of a filament queued and
+	// running jobs have reserved
+	GetFilamentReservations(ctx context.Context, in *GetFilamentReservationsRequest, opts ...grpc.CallOption) (*FilamentReservations, error)
+}
+
+type filamentServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFilamentServiceClient(cc grpc.ClientConnInterface) FilamentServiceClient {
+	return &filamentServiceClient{cc}
+}
+
+func (c *filamentServiceClient) CreateFilament(ctx context.Context, in *CreateFilamentRequest, opts ...grpc.CallOption) (*Filament, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Filament)
+	err := c.cc.Invoke(ctx, FilamentService_CreateFilament_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *filamentServiceClient) GetFilament(ctx context.Context, in *GetFilamentRequest, opts ...grpc.CallOption) (*Filament, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Filament)
+	err := c.cc.Invoke(ctx, FilamentService_GetFilament_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *filamentServiceClient) ListFilaments(ctx context.Context, in *ListFilamentsRequest, opts ...grpc.CallOption) (*ListFilamentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFilamentsResponse)
+	err := c.cc.Invoke(ctx, FilamentService_ListFilaments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *filamentServiceClient) UpdateFilament(ctx context.Context, in *UpdateFilamentRequest, opts ...grpc.CallOption) (*Filament, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Filament)
+	err := c.cc.Invoke(ctx, FilamentService_UpdateFilament_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *filamentServiceClient) DeleteFilament(ctx context.Context, in *DeleteFilamentRequest, opts ...grpc.CallOption) (*DeleteFilamentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteFilamentResponse)
+	err := c.cc.Invoke(ctx, FilamentService_DeleteFilament_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *filamentServiceClient) AdjustFilamentWeight(ctx context.Context, in *AdjustFilamentWeightRequest, opts ...grpc.CallOption) (*Filament, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Filament)
+	err := c.cc.Invoke(ctx, FilamentService_AdjustFilamentWeight_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *filamentServiceClient) GetFilamentReservations(ctx context.Context, in *GetFilamentReservationsRequest, opts ...grpc.CallOption) (*FilamentReservations, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FilamentReservations)
+	err := c.cc.Invoke(ctx, FilamentService_GetFilamentReservations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FilamentServiceServer is the server API for FilamentService service.
+// All implementations must embed UnimplementedFilamentServiceServer
+// for forward compatibility.
+//
+// FilamentService manages filaments
+type FilamentServiceServer interface {
+	// CreateFilament creates a filament, generating its ID if it has none
+	CreateFilament(context.Context, *CreateFilamentRequest) (*Filament, error)
+	// GetFilament returns a filament by ID
+	GetFilament(context.Context, *GetFilamentRequest) (*Filament, error)
+	// ListFilaments returns a page of the filaments that pass a filter
+	ListFilaments(context.Context, *ListFilamentsRequest) (*ListFilamentsResponse, error)
+	// UpdateFilament changes the fields of a filament that are set
+	UpdateFilament(context.Context, *UpdateFilamentRequest) (*Filament, error)
+	// DeleteFilament deletes a filament
+	DeleteFilament(context.Context, *DeleteFilamentRequest) (*DeleteFilamentResponse, error)
+	// AdjustFilamentWeight adds to or takes from the remaining weight of a
+	// filament
+	AdjustFilamentWeight(context.Context, *AdjustFilamentWeightRequest) (*Filament, error)
+	// GetFilamentReservations returns how much of a filament queued and
+	// running jobs have reserved
+	GetFilamentReservations(context.Context, *GetFilamentReservationsRequest) (*FilamentReservations, error)
+	mustEmbedUnimplementedFilamentServiceServer()
+}
+
+// UnimplementedFilamentServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFilamentServiceServer struct{}
+
+func (UnimplementedFilamentServiceServer) CreateFilament(context.Context, *CreateFilamentRequest) (*Filament, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFilament not implemented")
+}
+func (UnimplementedFilamentServiceServer) GetFilament(context.Context, *GetFilamentRequest) (*Filament, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFilament not implemented")
+}
+func (UnimplementedFilamentServiceServer) ListFilaments(context.Context, *ListFilamentsRequest) (*ListFilamentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFilaments not implemented")
+}
+func (UnimplementedFilamentServiceServer) UpdateFilament(context.Context, *UpdateFilamentRequest) (*Filament, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFilament not implemented")
+}
+func (UnimplementedFilamentServiceServer) DeleteFilament(context.Context, *DeleteFilamentRequest) (*DeleteFilamentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFilament not implemented")
+}
+func (UnimplementedFilamentServiceServer) AdjustFilamentWeight(context.Context, *AdjustFilamentWeightRequest) (*Filament, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdjustFilamentWeight not implemented")
+}
+func (UnimplementedFilamentServiceServer) GetFilamentReservations(context.Context, *GetFilamentReservationsRequest) (*FilamentReservations, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFilamentReservations not implemented")
+}
+func (UnimplementedFilamentServiceServer) mustEmbedUnimplementedFilamentServiceServer() {}
+func (UnimplementedFilamentServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeFilamentServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FilamentServiceServer will
+// result in compilation errors.
+type UnsafeFilamentServiceServer interface {
+	mustEmbedUnimplementedFilamentServiceServer()
+}
+
+func RegisterFilamentServiceServer(s grpc.ServiceRegistrar, srv FilamentServiceServer) {
+	// If the following call pancis, it indicates UnimplementedFilamentServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FilamentService_ServiceDesc, srv)
+}
+
+func _FilamentService_CreateFilament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateFilamentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FilamentServiceServer).CreateFilament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FilamentService_CreateFilament_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FilamentServiceServer).CreateFilament(ctx, req.(*CreateFilamentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FilamentService_GetFilament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFilamentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FilamentServiceServer).GetFilament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FilamentService_GetFilament_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FilamentServiceServer).GetFilament(ctx, req.(*GetFilamentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FilamentService_ListFilaments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFilamentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FilamentServiceServer).ListFilaments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FilamentService_ListFilaments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FilamentServiceServer).ListFilaments(ctx, req.(*ListFilamentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FilamentService_UpdateFilament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFilamentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FilamentServiceServer).UpdateFilament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FilamentService_UpdateFilament_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FilamentServiceServer).UpdateFilament(ctx, req.(*UpdateFilamentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FilamentService_DeleteFilament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFilamentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FilamentServiceServer).DeleteFilament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FilamentService_DeleteFilament_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FilamentServiceServer).DeleteFilament(ctx, req.(*DeleteFilamentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FilamentService_AdjustFilamentWeight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdjustFilamentWeightRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FilamentServiceServer).AdjustFilamentWeight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FilamentService_AdjustFilamentWeight_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FilamentServiceServer).AdjustFilamentWeight(ctx, req.(*AdjustFilamentWeightRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FilamentService_GetFilamentReservations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFilamentReservationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FilamentServiceServer).GetFilamentReservations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FilamentService_GetFilamentReservations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FilamentServiceServer).GetFilamentReservations(ctx, req.(*GetFilamentReservationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FilamentService_ServiceDesc is the grpc.ServiceDesc for FilamentService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FilamentService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "raft3d.v1.FilamentService",
+	HandlerType: (*FilamentServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateFilament",
+			Handler:    _FilamentService_CreateFilament_Handler,
+		},
+		{
+			MethodName: "GetFilament",
+			Handler:    _FilamentService_GetFilament_Handler,
+		},
+		{
+			MethodName: "ListFilaments",
+			Handler:    _FilamentService_ListFilaments_Handler,
+		},
+		{
+			MethodName: "UpdateFilament",
+			Handler:    _FilamentService_UpdateFilament_Handler,
+		},
+		{
+			MethodName: "DeleteFilament",
+			Handler:    _FilamentService_DeleteFilament_Handler,
+		},
+		{
+			MethodName: "AdjustFilamentWeight",
+			Handler:    _FilamentService_AdjustFilamentWeight_Handler,
+		},
+		{
+			MethodName: "GetFilamentReservations",
+			Handler:    _FilamentService_GetFilamentReservations_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "raft3d.proto",
+}
+
+const (
+	PrintJobService_CreatePrintJob_FullMethodName       = "/raft3d.v1.PrintJobService/CreatePrintJob"
+	PrintJobService_GetPrintJob_FullMethodName          = "/raft3d.v1.PrintJobService/GetPrintJob"
+	PrintJobService_ListPrintJobs_FullMethodName        = "/raft3d.v1.PrintJobService/ListPrintJobs"
+	PrintJobService_UpdatePrintJobStatus_FullMethodName = "/raft3d.v1.PrintJobService/UpdatePrintJobStatus"
+	PrintJobService_CancelPrintJob_FullMethodName       = "/raft3d.v1.PrintJobService/CancelPrintJob"
+	PrintJobService_DeletePrintJob_FullMethodName       = "/raft3d.v1.PrintJobService/DeletePrintJob"
+)
+
+// PrintJobServiceClient is the client API for PrintJobService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PrintJobService manages print jobs
+type PrintJobServiceClient interface {
+	// CreatePrintJob queues a print job, generating its ID if it has none
+	CreatePrintJob(ctx context.Context, in *CreatePrintJobRequest, opts ...grpc.CallOption) (*PrintJob, error)
+	// GetPrintJob returns a print job by ID, with its status history
+	GetPrintJob(ctx context.Context, in *GetPrintJobRequest, opts ...grpc.CallOption) (*PrintJob, error)
+	// ListPrintJobs returns a page of the print jobs that pass a filter
+	ListPrintJobs(ctx context.Context, in *ListPrintJobsRequest, opts ...grpc.CallOption) (*ListPrintJobsResponse, error)
+	// UpdatePrintJobStatus moves a print job to another status
+	UpdatePrintJobStatus(ctx context.Context, in *UpdatePrintJobStatusRequest, opts ...grpc.CallOption) (*PrintJob, error)
+	// CancelPrintJob cancels a queued or running print job
+	CancelPrintJob(ctx context.Context, in *CancelPrintJobRequest, opts ...grpc.CallOption) (*PrintJob, error)
+	// DeletePrintJob deletes a done or canceled print job
+	DeletePrintJob(ctx context.Context, in *DeletePrintJobRequest, opts ...grpc.CallOption) (*DeletePrintJobResponse, error)
+}
+
+type printJobServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPrintJobServiceClient(cc grpc.ClientConnInterface) PrintJobServiceClient {
+	return &printJobServiceClient{cc}
+}
+
+func (c *printJobServiceClient) CreatePrintJob(ctx context.Context, in *CreatePrintJobRequest, opts ...grpc.CallOption) (*PrintJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PrintJob)
+	err := c.cc.Invoke(ctx, PrintJobService_CreatePrintJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *printJobServiceClient) GetPrintJob(ctx context.Context, in *GetPrintJobRequest, opts ...grpc.CallOption) (*PrintJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PrintJob)
+	err := c.cc.Invoke(ctx, PrintJobService_GetPrintJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *printJobServiceClient) ListPrintJobs(ctx context.Context, in *ListPrintJobsRequest, opts ...grpc.CallOption) (*ListPrintJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPrintJobsResponse)
+	err := c.cc.Invoke(ctx, PrintJobService_ListPrintJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *printJobServiceClient) UpdatePrintJobStatus(ctx context.Context, in *UpdatePrintJobStatusRequest, opts ...grpc.CallOption) (*PrintJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PrintJob)
+	err := c.cc.Invoke(ctx, PrintJobService_UpdatePrintJobStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *printJobServiceClient) CancelPrintJob(ctx context.Context, in *CancelPrintJobRequest, opts ...grpc.CallOption) (*PrintJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PrintJob)
+	err := c.cc.Invoke(ctx, PrintJobService_CancelPrintJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *printJobServiceClient) DeletePrintJob(ctx context.Context, in *DeletePrintJobRequest, opts ...grpc.CallOption) (*DeletePrintJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeletePrintJobResponse)
+	err := c.cc.Invoke(ctx, PrintJobService_DeletePrintJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PrintJobServiceServer is the server API for PrintJobService service.
+// All implementations must embed UnimplementedPrintJobServiceServer
+// for forward compatibility.
+//
+// PrintJobService manages print jobs
+type PrintJobServiceServer interface {
+	// CreatePrintJob queues a print job, generating its ID if it has none
+	CreatePrintJob(context.Context, *CreatePrintJobRequest) (*PrintJob, error)
+	// GetPrintJob returns a print job by ID, with its status history
+	GetPrintJob(context.Context, *GetPrintJobRequest) (*PrintJob, error)
+	// ListPrintJobs returns a page of the print jobs that pass a filter
+	ListPrintJobs(context.Context, *ListPrintJobsRequest) (*ListPrintJobsResponse, error)
+	// UpdatePrintJobStatus moves a print job to another status
+	UpdatePrintJobStatus(context.Context, *UpdatePrintJobStatusRequest) (*PrintJob, error)
+	// CancelPrintJob cancels a queued or running print job
+	CancelPrintJob(context.Context, *CancelPrintJobRequest) (*PrintJob, error)
+	// DeletePrintJob deletes a done or canceled print job
+	DeletePrintJob(context.Context, *DeletePrintJobRequest) (*DeletePrintJobResponse, error)
+	mustEmbedUnimplementedPrintJobServiceServer()
+}
+
+// UnimplementedPrintJobServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPrintJobServiceServer struct{}
+
+func (UnimplementedPrintJobServiceServer) CreatePrintJob(context.Context, *CreatePrintJobRequest) (*PrintJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePrintJob not implemented")
+}
+func (UnimplementedPrintJobServiceServer) GetPrintJob(context.Context, *GetPrintJobRequest) (*PrintJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPrintJob not implemented")
+}
+func (UnimplementedPrintJobServiceServer) ListPrintJobs(context.Context, *ListPrintJobsRequest) (*ListPrintJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPrintJobs not implemented")
+}
+func (UnimplementedPrintJobServiceServer) UpdatePrintJobStatus(context.Context, *UpdatePrintJobStatusRequest) (*PrintJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePrintJobStatus not implemented")
+}
+func (UnimplementedPrintJobServiceServer) CancelPrintJob(context.Context, *CancelPrintJobRequest) (*PrintJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelPrintJob not implemented")
+}
+func (UnimplementedPrintJobServiceServer) DeletePrintJob(context.Context, *DeletePrintJobRequest) (*DeletePrintJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePrintJob not implemented")
+}
+func (UnimplementedPrintJobServiceServer) mustEmbedUnimplementedPrintJobServiceServer() {}
+func (UnimplementedPrintJobServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafePrintJobServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PrintJobServiceServer will
+// result in compilation errors.
+type UnsafePrintJobServiceServer interface {
+	mustEmbedUnimplementedPrintJobServiceServer()
+}
+
+func RegisterPrintJobServiceServer(s grpc.ServiceRegistrar, srv PrintJobServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPrintJobServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PrintJobService_ServiceDesc, srv)
+}
+
+func _PrintJobService_CreatePrintJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePrintJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrintJobServiceServer).CreatePrintJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrintJobService_CreatePrintJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrintJobServiceServer).CreatePrintJob(ctx, req.(*CreatePrintJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrintJobService_GetPrintJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPrintJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrintJobServiceServer).GetPrintJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrintJobService_GetPrintJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrintJobServiceServer).GetPrintJob(ctx, req.(*GetPrintJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrintJobService_ListPrintJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPrintJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrintJobServiceServer).ListPrintJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrintJobService_ListPrintJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrintJobServiceServer).ListPrintJobs(ctx, req.(*ListPrintJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrintJobService_UpdatePrintJobStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePrintJobStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrintJobServiceServer).UpdatePrintJobStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrintJobService_UpdatePrintJobStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrintJobServiceServer).UpdatePrintJobStatus(ctx, req.(*UpdatePrintJobStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrintJobService_CancelPrintJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelPrintJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrintJobServiceServer).CancelPrintJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrintJobService_CancelPrintJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrintJobServiceServer).CancelPrintJob(ctx, req.(*CancelPrintJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrintJobService_DeletePrintJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePrintJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrintJobServiceServer).DeletePrintJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrintJobService_DeletePrintJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrintJobServiceServer).DeletePrintJob(ctx, req.(*DeletePrintJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PrintJobService_ServiceDesc is the grpc.ServiceDesc for PrintJobService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PrintJobService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "raft3d.v1.PrintJobService",
+	HandlerType: (*PrintJobServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreatePrintJob",
+			Handler:    _PrintJobService_CreatePrintJob_Handler,
+		},
+		{
+			MethodName: "GetPrintJob",
+			Handler:    _PrintJobService_GetPrintJob_Handler,
+		},
+		{
+			MethodName: "ListPrintJobs",
+			Handler:    _PrintJobService_ListPrintJobs_Handler,
+		},
+		{
+			MethodName: "UpdatePrintJobStatus",
+			Handler:    _PrintJobService_UpdatePrintJobStatus_Handler,
+		},
+		{
+			MethodName: "CancelPrintJob",
+			Handler:    _PrintJobService_CancelPrintJob_Handler,
+		},
+		{
+			MethodName: "DeletePrintJob",
+			Handler:    _PrintJobService_DeletePrintJob_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "raft3d.proto",
+}
+
+const (
+	WatchService_Watch_FullMethodName = "/raft3d.v1.WatchService/Watch"
+)
+
+// WatchServiceClient is the client API for WatchService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// WatchService streams the changes made to resources
+type WatchServiceClient interface {
+	// Watch streams the changes applied after since_index until the client
+	// goes away. Each message holds the changes of one or more log entries.
+	// If the changes since since_index are no longer retained the stream
+	// ends with OUT_OF_RANGE and the client has to read the state again.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchResponse], error)
+}
+
+type watchServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWatchServiceClient(cc grpc.ClientConnInterface) WatchServiceClient {
+	return &watchServiceClient{cc}
+}
+
+func (c *watchServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WatchService_ServiceDesc.Streams[0], WatchService_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, WatchResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WatchService_WatchClient = grpc.ServerStreamingClient[WatchResponse]
+
+// WatchServiceServer is the server API for WatchService service.
+// All implementations must embed UnimplementedWatchServiceServer
+// for forward compatibility.
+//
+// WatchService streams the changes made to resources
+type WatchServiceServer interface {
+	// Watch streams the changes applied after since_index until the client
+	// goes away. Each message holds the changes of one or more log entries.
+	// If the changes since since_index are no longer retained the stream
+	// ends with OUT_OF_RANGE and the client has to read the state again.
+	Watch(*WatchRequest, grpc.ServerStreamingServer[WatchResponse]) error
+	mustEmbedUnimplementedWatchServiceServer()
+}
+
+// UnimplementedWatchServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWatchServiceServer struct{}
+
+func (UnimplementedWatchServiceServer) Watch(*WatchRequest, grpc.ServerStreamingServer[WatchResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedWatchServiceServer) mustEmbedUnimplementedWatchServiceServer() {}
+func (UnimplementedWatchServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeWatchServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WatchServiceServer will
+// result in compilation errors.
+type UnsafeWatchServiceServer interface {
+	mustEmbedUnimplementedWatchServiceServer()
+}
+
+func RegisterWatchServiceServer(s grpc.ServiceRegistrar, srv WatchServiceServer) {
+	// If the following call pancis, it indicates UnimplementedWatchServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&WatchService_ServiceDesc, srv)
+}
+
+func _WatchService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WatchServiceServer).Watch(m, &grpc.GenericServerStream[WatchRequest, WatchResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WatchService_WatchServer = grpc.ServerStreamingServer[WatchResponse]
+
+// WatchService_ServiceDesc is the grpc.ServiceDesc for WatchService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WatchService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "raft3d.v1.WatchService",
+	HandlerType: (*WatchServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _WatchService_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "raft3d.proto",
+}
+
+const (
+	ClusterService_GetClusterStatus_FullMethodName = "/raft3d.v1.ClusterService/GetClusterStatus"
+)
+
+// ClusterServiceClient is the client API for ClusterService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ClusterService reports on the Raft cluster
+type ClusterServiceClient interface {
+	// GetClusterStatus returns the state of this node and the members of the
+	// cluster
+	GetClusterStatus(ctx context.Context, in *GetClusterStatusRequest, opts ...grpc.CallOption) (*ClusterStatus, error)
+}
+
+type clusterServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewClusterServiceClient(cc grpc.ClientConnInterface) ClusterServiceClient {
+	return &clusterServiceClient{cc}
+}
+
+func (c *clusterServiceClient) GetClusterStatus(ctx context.Context, in *GetClusterStatusRequest, opts ...grpc.CallOption) (*ClusterStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClusterStatus)
+	err := c.cc.Invoke(ctx, ClusterService_GetClusterStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ClusterServiceServer is the server API for ClusterService service.
+// All implementations must embed UnimplementedClusterServiceServer
+// for forward compatibility.
+//
+// ClusterService reports on the Raft cluster
+type ClusterServiceServer interface {
+	// GetClusterStatus returns the state of this node and the members of the
+	// cluster
+	GetClusterStatus(context.Context, *GetClusterStatusRequest) (*ClusterStatus, error)
+	mustEmbedUnimplementedClusterServiceServer()
+}
+
+// UnimplementedClusterServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedClusterServiceServer struct{}
+
+func (UnimplementedClusterServiceServer) GetClusterStatus(context.Context, *GetClusterStatusRequest) (*ClusterStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClusterStatus not implemented")
+}
+func (UnimplementedClusterServiceServer) mustEmbedUnimplementedClusterServiceServer() {}
+func (UnimplementedClusterServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeClusterServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ClusterServiceServer will
+// result in compilation errors.
+type UnsafeClusterServiceServer interface {
+	mustEmbedUnimplementedClusterServiceServer()
+}
+
+func RegisterClusterServiceServer(s grpc.ServiceRegistrar, srv ClusterServiceServer) {
+	// If the following call pancis, it indicates UnimplementedClusterServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ClusterService_ServiceDesc, srv)
+}
+
+func _ClusterService_GetClusterStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClusterStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClusterServiceServer).GetClusterStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClusterService_GetClusterStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClusterServiceServer).GetClusterStatus(ctx, req.(*GetClusterStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ClusterService_ServiceDesc is the grpc.ServiceDesc for ClusterService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ClusterService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "raft3d.v1.ClusterService",
+	HandlerType: (*ClusterServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetClusterStatus",
+			Handler:    _ClusterService_GetClusterStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "raft3d.proto",
+}
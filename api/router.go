@@ -2,36 +2,103 @@
 package api
 
 import (
+	"fmt"
+
 	"github.com/devadigapratham/raft3d/api/handlers"
+	"github.com/devadigapratham/raft3d/api/openapi"
 	"github.com/devadigapratham/raft3d/raft"
 	"github.com/gin-gonic/gin"
 )
 
-// SetupRouter sets up the API routes
-func SetupRouter(node *raft.Node) *gin.Engine {
+// SetupRouter sets up the API routes. Requests are authenticated by
+// authenticator, or not at all if it is nil. It fails if the routes and the
+// API description returned by Spec disagree.
+func SetupRouter(node *raft.Node, authenticator *handlers.Authenticator) (*gin.Engine, error) {
 	router := gin.Default()
+	spec := Spec()
 
 	// Create the handler
 	handler := handlers.NewHandler(node)
 
 	// Apply middleware
+	router.Use(handler.RequestIDMiddleware())
+	if authenticator != nil {
+		router.Use(authenticator.Middleware(spec))
+	}
 	router.Use(handler.RaftLeaderMiddleware())
+	router.Use(handler.ValidationMiddleware(spec))
+	router.NoRoute(handler.NoRoute)
 
 	// API group
 	api := router.Group("/api/v1")
 	{
 		// Printer endpoints
 		api.POST("/printers", handler.CreatePrinter)
+		api.GET("/printers/:id", handler.GetPrinter)
+		api.PUT("/printers/:id", handler.ReplacePrinter)
+		api.PATCH("/printers/:id", handler.UpdatePrinter)
+		api.DELETE("/printers/:id", handler.DeletePrinter)
 		api.GET("/printers", handler.GetPrinters)
+		api.GET("/printers/:id/print_jobs", handler.GetPrinterPrintJobs)
+		api.GET("/printers/:id/current_job", handler.GetCurrentPrintJob)
 
 		// Filament endpoints
 		api.POST("/filaments", handler.CreateFilament)
+		api.PUT("/filaments/:id", handler.ReplaceFilament)
 		api.GET("/filaments", handler.GetFilaments)
+		api.GET("/filaments/:id", handler.GetFilament)
+		api.PATCH("/filaments/:id", handler.UpdateFilament)
+		api.DELETE("/filaments/:id", handler.DeleteFilament)
+		api.POST("/filaments/:id/adjustments", handler.AdjustFilamentWeight)
+		api.GET("/filaments/:id/adjustments", handler.GetFilamentAdjustments)
+		api.GET("/filaments/:id/print_jobs", handler.GetFilamentPrintJobs)
+		api.GET("/filaments/:id/reservations", handler.GetFilamentReservations)
 
 		// Print job endpoints
 		api.POST("/print_jobs", handler.CreatePrintJob)
+		api.PUT("/print_jobs/:id", handler.ReplacePrintJob)
 		api.GET("/print_jobs", handler.GetPrintJobs)
+		api.GET("/print_jobs/:id", handler.GetPrintJob)
+		api.DELETE("/print_jobs/:id", handler.DeletePrintJob)
 		api.POST("/print_jobs/:id/status", handler.UpdatePrintJobStatus)
+		api.POST("/print_jobs/:id/cancel", handler.CancelPrintJob)
+		api.GET("/archive/print_jobs", handler.GetArchivedPrintJobs)
+
+		// Transactions
+		api.POST("/transactions", handler.CreateTransaction)
+
+		// Change feed
+		api.GET("/watch", handler.Watch)
+
+		// Audit log
+		api.GET("/audit", handler.GetAuditRecords)
+		api.GET("/audit/export", handler.ExportAuditRecords)
+
+		// Bulk import and export
+		api.POST("/import", handler.Import)
+		api.GET("/export", handler.Export)
+
+		// API keys and role bindings
+		api.POST("/auth/keys", handler.CreateAPIKey)
+		api.GET("/auth/keys", handler.GetAPIKeys)
+		api.GET("/auth/keys/:id", handler.GetAPIKey)
+		api.POST("/auth/keys/:id/rotate", handler.RotateAPIKey)
+		api.DELETE("/auth/keys/:id", handler.DeleteAPIKey)
+		api.GET("/auth/roles", handler.GetRoleBindings)
+		api.PUT("/auth/roles/:subject", handler.SetRoleBinding)
+		api.DELETE("/auth/roles/:subject", handler.DeleteRoleBinding)
+		api.GET("/auth/whoami", handler.WhoAmI)
+
+		// API description
+		api.GET("/openapi.json", handler.GetOpenAPI(spec))
+	}
+
+	// Admin endpoints
+	admin := router.Group("/admin")
+	{
+		admin.GET("/digest", handler.GetDigest)
+		admin.GET("/invariants", handler.CheckInvariants)
+		admin.POST("/invariants/repair", handler.RepairInvariants)
 	}
 
 	// Add a raft status endpoint
@@ -48,5 +115,14 @@ func SetupRouter(node *raft.Node) *gin.Engine {
 		})
 	})
 
-	return router
+	// Every route has to be described, and everything described served
+	var routes []openapi.Route
+	for _, r := range router.Routes() {
+		routes = append(routes, openapi.Route{Method: r.Method, Path: r.Path})
+	}
+	if err := spec.CheckDrift(routes); err != nil {
+		return nil, fmt.Errorf("failed to set up routes: %v", err)
+	}
+
+	return router, nil
 }
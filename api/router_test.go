@@ -0,0 +1,360 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/openapi"
+	"github.com/devadigapratham/raft3d/raft"
+	"github.com/gin-gonic/gin"
+)
+
+// newTestNode starts a single node cluster and waits for it to lead
+func newTestNode(t *testing.T) *raft.Node {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	node, err := raft.NewNode(&raft.Config{NodeID: "n1", RaftAddr: addr, RaftDir: t.TempDir(), Bootstrap: true})
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	t.Cleanup(func() { node.Shutdown() })
+
+	deadline := time.Now().Add(10 * time.Second)
+	for !node.Leader() {
+		if time.Now().After(deadline) {
+			t.Fatal("node didn't become leader")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return node
+}
+
+// example is a request to a route and the status it is expected to get
+type example struct {
+	// route is the method and path of the route, as registered
+	route       string
+	path        string
+	body        string
+	contentType string
+	ifMatch     string
+	status      int
+}
+
+// examples exercise every route, in order, each building on the state the
+// ones before it left behind
+var examples = []example{
+	// Printers
+	{route: "POST /api/v1/printers", path: "/api/v1/printers", body: `{"id": "p1", "company": "Prusa", "model": "MK4"}`, status: 201},
+	{route: "POST /api/v1/printers", path: "/api/v1/printers", body: `{"id": "p1", "company": "Prusa", "model": "MK4"}`, status: 409},
+	{route: "POST /api/v1/printers", path: "/api/v1/printers", body: `{"company": 4}`, status: 422},
+	{route: "GET /api/v1/printers", path: "/api/v1/printers?sort=-model&limit=10", status: 200},
+	{route: "GET /api/v1/printers/:id", path: "/api/v1/printers/p1", status: 200},
+	{route: "GET /api/v1/printers/:id", path: "/api/v1/printers/missing", status: 404},
+	{route: "PUT /api/v1/printers/:id", path: "/api/v1/printers/p2", body: `{"company": "Bambu", "model": "X1"}`, ifMatch: "*", status: 412},
+	{route: "PUT /api/v1/printers/:id", path: "/api/v1/printers/p2", body: `{"company": "Bambu", "model": "X1"}`, status: 200},
+	{route: "PATCH /api/v1/printers/:id", path: "/api/v1/printers/p2", body: `{"model": "X1C"}`, ifMatch: "*", status: 200},
+
+	// Filaments
+	{route: "POST /api/v1/filaments", path: "/api/v1/filaments", body: `{"id": "f1", "type": "PLA", "color": "red", "total_weight_in_grams": 1000}`, status: 201},
+	{route: "GET /api/v1/filaments", path: "/api/v1/filaments?filter=type%20%3D%20PLA", status: 200},
+	{route: "GET /api/v1/filaments/:id", path: "/api/v1/filaments/f1", status: 200},
+	{route: "PUT /api/v1/filaments/:id", path: "/api/v1/filaments/f2", body: `{"type": "PETG", "color": "blue", "total_weight_in_grams": 500}`, status: 200},
+	{route: "PATCH /api/v1/filaments/:id", path: "/api/v1/filaments/f2", body: `{"color": "green"}`, status: 200},
+	{route: "POST /api/v1/filaments/:id/adjustments", path: "/api/v1/filaments/f1/adjustments", body: `{"delta_grams": -50, "reason": "weighed spool"}`, status: 200},
+	{route: "GET /api/v1/filaments/:id/adjustments", path: "/api/v1/filaments/f1/adjustments", status: 200},
+
+	// Print jobs
+	{route: "POST /api/v1/print_jobs", path: "/api/v1/print_jobs", body: `{"id": "j1", "printer_id": "p1", "filament_id": "f1", "filepath": "/prints/part.gcode", "print_weight_in_grams": 100}`, status: 201},
+	{route: "POST /api/v1/print_jobs", path: "/api/v1/print_jobs", body: `{"printer_id": "p1", "filament_id": "f1", "filepath": "/prints/part.gcode", "print_weight_in_grams": 5000}`, status: 422},
+	{route: "PUT /api/v1/print_jobs/:id", path: "/api/v1/print_jobs/j2", body: `{"printer_id": "p2", "filament_id": "f2", "filepath": "/prints/other.gcode", "print_weight_in_grams": 50}`, status: 200},
+	{route: "GET /api/v1/print_jobs", path: "/api/v1/print_jobs?status=Queued", status: 200},
+	{route: "GET /api/v1/print_jobs/:id", path: "/api/v1/print_jobs/j1", status: 200},
+	{route: "POST /api/v1/print_jobs/:id/status", path: "/api/v1/print_jobs/j1/status", body: `{"status": "Running"}`, status: 200},
+	{route: "POST /api/v1/print_jobs/:id/status", path: "/api/v1/print_jobs/j1/status", body: `{"status": "Queued"}`, status: 409},
+	{route: "GET /api/v1/printers/:id/current_job", path: "/api/v1/printers/p1/current_job", status: 200},
+	{route: "GET /api/v1/printers/:id/print_jobs", path: "/api/v1/printers/p1/print_jobs", status: 200},
+	{route: "GET /api/v1/filaments/:id/print_jobs", path: "/api/v1/filaments/f1/print_jobs", status: 200},
+	{route: "GET /api/v1/filaments/:id/reservations", path: "/api/v1/filaments/f1/reservations", status: 200},
+	{route: "POST /api/v1/print_jobs/:id/status", path: "/api/v1/print_jobs/j1/status?status=Done", status: 200},
+	{route: "POST /api/v1/print_jobs/:id/cancel", path: "/api/v1/print_jobs/j2/cancel", body: `{"reason": "wrong file"}`, status: 200},
+	{route: "DELETE /api/v1/print_jobs/:id", path: "/api/v1/print_jobs/j2", status: 204},
+	{route: "GET /api/v1/archive/print_jobs", path: "/api/v1/archive/print_jobs?limit=10", status: 200},
+	{route: "GET /api/v1/archive/print_jobs", path: "/api/v1/archive/print_jobs?cursor=1", status: 400},
+
+	// Transactions
+	{route: "POST /api/v1/transactions", path: "/api/v1/transactions", body: `{
+		"preconditions": [{"type": "exists", "resource": "printers", "id": "p1"}],
+		"operations": [
+			{"type": "ADD_FILAMENT", "filament": {"id": "f3", "type": "PLA", "color": "white", "total_weight_in_grams": 1000}},
+			{"type": "ADD_PRINT_JOB", "print_job": {"id": "j3", "printer_id": "p1", "filament_id": "f3", "filepath": "/prints/part.gcode", "print_weight_in_grams": 100}}
+		]
+	}`, status: 200},
+	{route: "POST /api/v1/transactions", path: "/api/v1/transactions", body: `{
+		"preconditions": [{"type": "not_exists", "resource": "printers", "id": "p1"}],
+		"operations": [{"type": "DELETE_PRINTER", "printer_id": "p1"}]
+	}`, status: 412},
+
+	// Change feed and audit log
+	{route: "GET /api/v1/watch", path: "/api/v1/watch?timeout=10ms", status: 200},
+	{route: "GET /api/v1/audit", path: "/api/v1/audit?limit=5", status: 200},
+	{route: "GET /api/v1/audit/export", path: "/api/v1/audit/export?result=ok", status: 200},
+
+	// Bulk import and export
+	{route: "GET /api/v1/export", path: "/api/v1/export", status: 200},
+	{route: "GET /api/v1/export", path: "/api/v1/export?format=ndjson", status: 200},
+	{route: "GET /api/v1/export", path: "/api/v1/export?format=csv&resource=printers", status: 200},
+	{route: "POST /api/v1/import", path: "/api/v1/import?dry_run=true", body: `{"printers": [{"id": "p9", "company": "Prusa", "model": "Mini"}]}`, status: 200},
+	{route: "POST /api/v1/import", path: "/api/v1/import", body: `{"printers": [{"id": "p9", "company": "Prusa", "model": "Mini"}]}`, status: 201},
+	{route: "POST /api/v1/import", path: "/api/v1/import?resource=printers", contentType: "text/csv", body: "id,company,model\np1,Prusa,MK4\n", status: 422},
+
+	// Authentication
+	{route: "POST /api/v1/auth/keys", path: "/api/v1/auth/keys", body: `{"id": "ci", "role": "operator"}`, status: 201},
+	{route: "GET /api/v1/auth/keys", path: "/api/v1/auth/keys", status: 200},
+	{route: "GET /api/v1/auth/keys/:id", path: "/api/v1/auth/keys/ci", status: 200},
+	{route: "POST /api/v1/auth/keys/:id/rotate", path: "/api/v1/auth/keys/ci/rotate", body: `{"grace_period": "1h"}`, status: 200},
+	{route: "DELETE /api/v1/auth/keys/:id", path: "/api/v1/auth/keys/ci", status: 204},
+	{route: "PUT /api/v1/auth/roles/:subject", path: "/api/v1/auth/roles/alice", body: `{"role": "viewer"}`, status: 200},
+	{route: "GET /api/v1/auth/roles", path: "/api/v1/auth/roles", status: 200},
+	{route: "DELETE /api/v1/auth/roles/:subject", path: "/api/v1/auth/roles/alice", status: 204},
+	{route: "GET /api/v1/auth/whoami", path: "/api/v1/auth/whoami", status: 200},
+
+	// The description itself, admin and status
+	{route: "GET /api/v1/openapi.json", path: "/api/v1/openapi.json", status: 200},
+	{route: "GET /admin/digest", path: "/admin/digest", status: 200},
+	{route: "GET /admin/invariants", path: "/admin/invariants", status: 200},
+	{route: "POST /admin/invariants/repair", path: "/admin/invariants/repair?dry_run=true", status: 200},
+	{route: "GET /status", path: "/status", status: 200},
+
+	// Deletes, last so the resources are there for the rest
+	{route: "DELETE /api/v1/printers/:id", path: "/api/v1/printers/p1", status: 409},
+	{route: "DELETE /api/v1/printers/:id", path: "/api/v1/printers/p2", status: 204},
+	{route: "DELETE /api/v1/filaments/:id", path: "/api/v1/filaments/f2", status: 204},
+}
+
+func TestRouterMatchesSpec(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, err := SetupRouter(newTestNode(t), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := Spec()
+
+	// Every route is described and has an example
+	exercised := make(map[string]bool)
+	for _, ex := range examples {
+		exercised[ex.route] = true
+	}
+	for _, r := range router.Routes() {
+		route := r.Method + " " + r.Path
+		if doc.Operation(r.Method, r.Path) == nil {
+			t.Errorf("route %s is not described", route)
+		}
+		if !exercised[route] {
+			t.Errorf("route %s has no example", route)
+		}
+		delete(exercised, route)
+	}
+	for route := range exercised {
+		t.Errorf("example of %s, which is not a route", route)
+	}
+
+	for _, ex := range examples {
+		method, path, _ := strings.Cut(ex.route, " ")
+		op := doc.Operation(method, path)
+		if op == nil {
+			continue
+		}
+
+		name := method + " " + ex.path
+		if ex.status < 300 {
+			if err := checkRequest(doc, op, &ex); err != "" {
+				t.Errorf("%s: %s", name, err)
+			}
+		}
+
+		req := httptest.NewRequest(method, ex.path, strings.NewReader(ex.body))
+		if ex.body != "" {
+			contentType := ex.contentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			req.Header.Set("Content-Type", contentType)
+		}
+		if ex.ifMatch != "" {
+			req.Header.Set("If-Match", ex.ifMatch)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != ex.status {
+			t.Errorf("%s returned %d, want %d: %s", name, rec.Code, ex.status, rec.Body)
+			continue
+		}
+		if err := checkResponse(doc, op, rec); err != "" {
+			t.Errorf("%s: %s", name, err)
+		}
+	}
+}
+
+// checkRequest describes how an example meant to succeed differs from the
+// request its operation takes, or returns "" if it doesn't
+func checkRequest(doc *openapi.Document, op *openapi.Operation, ex *example) string {
+	if ex.body == "" {
+		if op.RequestBody != nil && op.RequestBody.Required {
+			return "request has no body, described as required"
+		}
+		return ""
+	}
+	if op.RequestBody == nil {
+		return "request has a body, described as having none"
+	}
+	contentType := ex.contentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	content, ok := op.RequestBody.Content[contentType]
+	if !ok {
+		return "request is " + contentType + ", which is not described"
+	}
+	if content.Schema == nil || contentType != "application/json" {
+		return ""
+	}
+
+	v, err := decodeJSON(strings.NewReader(ex.body))
+	if err != nil {
+		return "request is not JSON: " + err.Error()
+	}
+	if err := doc.Validate(content.Schema, v, "body"); err != nil {
+		return err.Error()
+	}
+	if fields := undescribed(doc, content.Schema, v, "body"); len(fields) > 0 {
+		sort.Strings(fields)
+		return "request has fields that are not described: " + strings.Join(fields, ", ")
+	}
+	return ""
+}
+
+// checkResponse describes how a response differs from what its operation
+// says it returns, or returns "" if it doesn't
+func checkResponse(doc *openapi.Document, op *openapi.Operation, rec *httptest.ResponseRecorder) string {
+	resp, ok := op.Responses[strconv.Itoa(rec.Code)]
+	if !ok {
+		resp, ok = op.Responses["default"]
+	}
+	if !ok {
+		return "status " + strconv.Itoa(rec.Code) + " is not described"
+	}
+	if len(resp.Content) == 0 {
+		if rec.Body.Len() > 0 {
+			return "response has a body, described as having none"
+		}
+		return ""
+	}
+
+	mediaType, _, err := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	if err != nil {
+		return "response has no content type"
+	}
+	content, ok := resp.Content[mediaType]
+	if !ok {
+		return "response is " + mediaType + ", which is not described"
+	}
+	if content.Schema == nil {
+		return ""
+	}
+
+	var values []interface{}
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		v, err := decodeJSON(rec.Body)
+		if err != nil {
+			return "response is not JSON: " + err.Error()
+		}
+		values = append(values, v)
+	case mediaType == "application/x-ndjson":
+		scanner := bufio.NewScanner(rec.Body)
+		for scanner.Scan() {
+			v, err := decodeJSON(bytes.NewReader(scanner.Bytes()))
+			if err != nil {
+				return "response line is not JSON: " + err.Error()
+			}
+			values = append(values, v)
+		}
+	default:
+		// Text the schema can say nothing more about
+		return ""
+	}
+
+	for _, v := range values {
+		if err := doc.Validate(content.Schema, v, "response"); err != nil {
+			return err.Error()
+		}
+		if fields := undescribed(doc, content.Schema, v, "response"); len(fields) > 0 {
+			sort.Strings(fields)
+			return "response has fields that are not described: " + strings.Join(fields, ", ")
+		}
+	}
+	return ""
+}
+
+// decodeJSON decodes a JSON value the way Validate expects it, with numbers
+// as json.Number
+func decodeJSON(r io.Reader) (interface{}, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var v interface{}
+	err := dec.Decode(&v)
+	return v, err
+}
+
+// undescribed lists the fields of v its schema doesn't describe. Objects
+// without properties are free-form.
+func undescribed(doc *openapi.Document, s *openapi.Schema, v interface{}, at string) []string {
+	for s != nil && s.Ref != "" {
+		s = doc.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	if s == nil {
+		return nil
+	}
+
+	var fields []string
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(s.Properties) == 0 && s.AdditionalProperties == nil {
+			return nil
+		}
+		for name, value := range v {
+			prop, ok := s.Properties[name]
+			if !ok {
+				prop = s.AdditionalProperties
+			}
+			if prop == nil {
+				fields = append(fields, at+"."+name)
+				continue
+			}
+			fields = append(fields, undescribed(doc, prop, value, at+"."+name)...)
+		}
+	case []interface{}:
+		for i, item := range v {
+			fields = append(fields, undescribed(doc, s.Items, item, at+"["+strconv.Itoa(i)+"]")...)
+		}
+	}
+	return fields
+}
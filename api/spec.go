@@ -0,0 +1,843 @@
+package api
+
+//go:generate go run ../cmd/server openapi -output openapi.json
+
+import (
+	"sync"
+
+	"github.com/devadigapratham/raft3d/api/models"
+	"github.com/devadigapratham/raft3d/api/openapi"
+	"github.com/devadigapratham/raft3d/raft"
+)
+
+// APIVersion is the version of the API description. Bump it whenever an
+// operation or schema changes, and regenerate openapi.json.
+const APIVersion = "1.5.1"
+
+var (
+	specOnce sync.Once
+	spec     *openapi.Document
+)
+
+// Spec returns the OpenAPI description of the routes SetupRouter serves.
+// SetupRouter fails when the two disagree.
+func Spec() *openapi.Document {
+	specOnce.Do(func() {
+		spec = buildSpec()
+	})
+	return spec
+}
+
+func buildSpec() *openapi.Document {
+	doc := openapi.New("Raft3D", APIVersion)
+	doc.Info.Description = "A distributed 3D printer management API replicated with Raft"
+
+	addSchemas(doc)
+	addSecuritySchemes(doc)
+
+	// Printers
+	doc.Add("POST", "/api/v1/printers", &openapi.Operation{
+		OperationID: "createPrinter",
+		Summary:     "Create a printer",
+		Tags:        []string{"printers"},
+		RequestBody: jsonBody(openapi.Ref("Printer")),
+		Responses:   created("The printer", openapi.Ref("Printer")),
+	})
+	doc.Add("GET", "/api/v1/printers", &openapi.Operation{
+		OperationID: "listPrinters",
+		Summary:     "List printers",
+		Tags:        []string{"printers"},
+		Parameters:  listParams(raft.ResourcePrinters),
+		Responses:   ok("A page of printers", arrayOf("Printer")),
+	})
+	doc.Add("GET", "/api/v1/printers/:id", &openapi.Operation{
+		OperationID: "getPrinter",
+		Summary:     "Get a printer",
+		Tags:        []string{"printers"},
+		Parameters:  append([]*openapi.Parameter{idParam()}, asOfParams("Get the resource as it was")...),
+		Responses:   ok("The printer", openapi.Ref("Printer")),
+	})
+	doc.Add("PUT", "/api/v1/printers/:id", &openapi.Operation{
+		OperationID: "replacePrinter",
+		Summary:     "Create or replace a printer",
+		Tags:        []string{"printers"},
+		Parameters:  []*openapi.Parameter{idParam(), ifMatchParam()},
+		RequestBody: jsonBody(openapi.Ref("Printer")),
+		Responses:   ok("The printer", openapi.Ref("Printer")),
+	})
+	doc.Add("PATCH", "/api/v1/printers/:id", &openapi.Operation{
+		OperationID: "updatePrinter",
+		Summary:     "Change fields of a printer",
+		Tags:        []string{"printers"},
+		Parameters:  []*openapi.Parameter{idParam(), ifMatchParam()},
+		RequestBody: jsonBody(openapi.Ref("Printer")),
+		Responses:   ok("The printer", openapi.Ref("Printer")),
+	})
+	doc.Add("DELETE", "/api/v1/printers/:id", &openapi.Operation{
+		OperationID: "deletePrinter",
+		Summary:     "Delete a printer",
+		Tags:        []string{"printers"},
+		Parameters:  []*openapi.Parameter{idParam(), ifMatchParam(), cascadeParam()},
+		Responses:   noContent(),
+	})
+
+	doc.Add("GET", "/api/v1/printers/:id/print_jobs", &openapi.Operation{
+		OperationID: "listPrinterPrintJobs",
+		Summary:     "List the print jobs scheduled on a printer",
+		Tags:        []string{"printers", "print_jobs"},
+		Parameters:  append([]*openapi.Parameter{idParam()}, listParams(raft.ResourcePrintJobs)...),
+		Responses:   ok("A page of print jobs", arrayOf("PrintJob")),
+	})
+	doc.Add("GET", "/api/v1/printers/:id/current_job", &openapi.Operation{
+		OperationID: "getCurrentPrintJob",
+		Summary:     "Get the print job Running on a printer",
+		Tags:        []string{"printers", "print_jobs"},
+		Parameters:  []*openapi.Parameter{idParam()},
+		Responses:   ok("The print job", openapi.Ref("PrintJob")),
+	})
+
+	// Filaments
+	doc.Add("POST", "/api/v1/filaments", &openapi.Operation{
+		OperationID: "createFilament",
+		Summary:     "Create a filament",
+		Tags:        []string{"filaments"},
+		RequestBody: jsonBody(openapi.Ref("Filament")),
+		Responses:   created("The filament", openapi.Ref("Filament")),
+	})
+	doc.Add("GET", "/api/v1/filaments", &openapi.Operation{
+		OperationID: "listFilaments",
+		Summary:     "List filaments",
+		Tags:        []string{"filaments"},
+		Parameters:  listParams(raft.ResourceFilaments),
+		Responses:   ok("A page of filaments", arrayOf("Filament")),
+	})
+	doc.Add("GET", "/api/v1/filaments/:id", &openapi.Operation{
+		OperationID: "getFilament",
+		Summary:     "Get a filament",
+		Tags:        []string{"filaments"},
+		Parameters:  append([]*openapi.Parameter{idParam()}, asOfParams("Get the resource as it was")...),
+		Responses:   ok("The filament", openapi.Ref("Filament")),
+	})
+	doc.Add("PUT", "/api/v1/filaments/:id", &openapi.Operation{
+		OperationID: "replaceFilament",
+		Summary:     "Create or replace a filament",
+		Tags:        []string{"filaments"},
+		Parameters:  []*openapi.Parameter{idParam(), ifMatchParam()},
+		RequestBody: jsonBody(openapi.Ref("Filament")),
+		Responses:   ok("The filament", openapi.Ref("Filament")),
+	})
+	doc.Add("PATCH", "/api/v1/filaments/:id", &openapi.Operation{
+		OperationID: "updateFilament",
+		Summary:     "Change fields of a filament",
+		Tags:        []string{"filaments"},
+		Parameters:  []*openapi.Parameter{idParam(), ifMatchParam()},
+		RequestBody: jsonBody(openapi.Ref("Filament")),
+		Responses:   ok("The filament", openapi.Ref("Filament")),
+	})
+	doc.Add("DELETE", "/api/v1/filaments/:id", &openapi.Operation{
+		OperationID: "deleteFilament",
+		Summary:     "Delete a filament",
+		Tags:        []string{"filaments"},
+		Parameters:  []*openapi.Parameter{idParam(), ifMatchParam(), cascadeParam()},
+		Responses:   noContent(),
+	})
+	doc.Add("POST", "/api/v1/filaments/:id/adjustments", &openapi.Operation{
+		OperationID: "adjustFilamentWeight",
+		Summary:     "Add to or take from the remaining weight of a filament",
+		Tags:        []string{"filaments"},
+		Parameters:  []*openapi.Parameter{idParam(), ifMatchParam()},
+		RequestBody: jsonBody(&openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"delta_grams": {Type: "integer", Format: "int64", Description: "Grams to add, or to take off if negative; not 0"},
+				"reason":      {Type: "string", MinLength: intPtr(1)},
+			},
+			Required: []string{"delta_grams", "reason"},
+		}),
+		Responses: ok("The filament", openapi.Ref("Filament")),
+	})
+	doc.Add("GET", "/api/v1/filaments/:id/adjustments", &openapi.Operation{
+		OperationID: "listFilamentAdjustments",
+		Summary:     "List the weight adjustments of a filament",
+		Tags:        []string{"filaments"},
+		Parameters:  []*openapi.Parameter{idParam(), limitParam(), indexCursorParam()},
+		Responses:   ok("A page of adjustments", page("adjustments", "FilamentAdjustment")),
+	})
+
+	doc.Add("GET", "/api/v1/filaments/:id/print_jobs", &openapi.Operation{
+		OperationID: "listFilamentPrintJobs",
+		Summary:     "List the print jobs using a filament",
+		Tags:        []string{"filaments", "print_jobs"},
+		Parameters:  append([]*openapi.Parameter{idParam()}, listParams(raft.ResourcePrintJobs)...),
+		Responses:   ok("A page of print jobs", arrayOf("PrintJob")),
+	})
+	doc.Add("GET", "/api/v1/filaments/:id/reservations", &openapi.Operation{
+		OperationID: "getFilamentReservations",
+		Summary:     "Get the weight of a filament reserved by print jobs",
+		Tags:        []string{"filaments"},
+		Parameters:  []*openapi.Parameter{idParam()},
+		Responses:   ok("The reserved and free weight", openapi.Ref("FilamentReservations")),
+	})
+
+	// Print jobs
+	doc.Add("POST", "/api/v1/print_jobs", &openapi.Operation{
+		OperationID: "createPrintJob",
+		Summary:     "Queue a print job",
+		Tags:        []string{"print_jobs"},
+		RequestBody: jsonBody(openapi.Ref("PrintJob")),
+		Responses:   created("The print job", openapi.Ref("PrintJob")),
+	})
+	doc.Add("GET", "/api/v1/print_jobs", &openapi.Operation{
+		OperationID: "listPrintJobs",
+		Summary:     "List print jobs",
+		Tags:        []string{"print_jobs"},
+		Parameters:  listParams(raft.ResourcePrintJobs),
+		Responses:   ok("A page of print jobs", arrayOf("PrintJob")),
+	})
+	doc.Add("GET", "/api/v1/print_jobs/:id", &openapi.Operation{
+		OperationID: "getPrintJob",
+		Summary:     "Get a print job with its status history",
+		Tags:        []string{"print_jobs"},
+		Parameters:  append([]*openapi.Parameter{idParam()}, asOfParams("Get the resource as it was")...),
+		Responses:   ok("The print job", openapi.Ref("PrintJob")),
+	})
+	doc.Add("PUT", "/api/v1/print_jobs/:id", &openapi.Operation{
+		OperationID: "replacePrintJob",
+		Summary:     "Create or replace a print job",
+		Tags:        []string{"print_jobs"},
+		Parameters:  []*openapi.Parameter{idParam(), ifMatchParam()},
+		RequestBody: jsonBody(openapi.Ref("PrintJob")),
+		Responses:   ok("The print job", openapi.Ref("PrintJob")),
+	})
+	doc.Add("DELETE", "/api/v1/print_jobs/:id", &openapi.Operation{
+		OperationID: "deletePrintJob",
+		Summary:     "Delete a finished print job",
+		Tags:        []string{"print_jobs"},
+		Parameters:  []*openapi.Parameter{idParam(), ifMatchParam()},
+		Responses:   noContent(),
+	})
+	doc.Add("POST", "/api/v1/print_jobs/:id/status", &openapi.Operation{
+		OperationID: "updatePrintJobStatus",
+		Summary:     "Move a print job to another status",
+		Tags:        []string{"print_jobs"},
+		Parameters: []*openapi.Parameter{
+			idParam(), ifMatchParam(),
+			queryParam("status", statusSchema(), "The new status, when the body doesn't give it"),
+		},
+		RequestBody: optionalJSONBody(&openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"status": statusSchema(),
+				"reason": {Type: "string"},
+			},
+		}),
+		Responses: ok("The print job", openapi.Ref("PrintJob")),
+	})
+	doc.Add("POST", "/api/v1/print_jobs/:id/cancel", &openapi.Operation{
+		OperationID: "cancelPrintJob",
+		Summary:     "Cancel a Queued or Running print job",
+		Tags:        []string{"print_jobs"},
+		Parameters:  []*openapi.Parameter{idParam(), ifMatchParam()},
+		RequestBody: optionalJSONBody(&openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"reason":         {Type: "string"},
+				"consumed_grams": {Type: "integer", Format: "int64", Minimum: float64Ptr(0), Description: "Filament a Running job used up"},
+			},
+		}),
+		Responses: ok("The print job", openapi.Ref("PrintJob")),
+	})
+	doc.Add("GET", "/api/v1/archive/print_jobs", &openapi.Operation{
+		OperationID: "listArchivedPrintJobs",
+		Summary:     "List the print jobs this node archived",
+		Tags:        []string{"print_jobs"},
+		Parameters: []*openapi.Parameter{
+			queryParam("printer_id", &openapi.Schema{Type: "string"}, ""),
+			queryParam("filament_id", &openapi.Schema{Type: "string"}, ""),
+			queryParam("status", &openapi.Schema{Type: "string"}, ""),
+			limitParam(),
+			queryParam("cursor", &openapi.Schema{Type: "integer", Format: "int64", Minimum: float64Ptr(0)}, "The next_cursor of the previous page"),
+		},
+		Responses: ok("A page of archived print jobs", page("print_jobs", "ArchivedPrintJob")),
+	})
+
+	// Transactions
+	doc.Add("POST", "/api/v1/transactions", &openapi.Operation{
+		OperationID: "createTransaction",
+		Summary:     "Apply operations all-or-nothing",
+		Tags:        []string{"transactions"},
+		RequestBody: jsonBody(openapi.Ref("Transaction")),
+		Responses:   ok("The result of each operation", openapi.Ref("TransactionResult")),
+	})
+
+	// Change feed
+	doc.Add("GET", "/api/v1/watch", &openapi.Operation{
+		OperationID: "watch",
+		Summary:     "Wait for or stream changes",
+		Tags:        []string{"watch"},
+		Parameters: []*openapi.Parameter{
+			queryParam("resources", &openapi.Schema{Type: "string"}, "Comma-separated resource types"),
+			queryParam("since_index", &openapi.Schema{Type: "integer", Format: "int64", Minimum: float64Ptr(0)}, ""),
+			queryParam("mode", &openapi.Schema{Type: "string", Enum: []interface{}{"sse"}}, "sse streams server-sent events"),
+			queryParam("timeout", &openapi.Schema{Type: "string"}, "How long a long-poll waits, such as 30s"),
+		},
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "The changes, or a stream of them",
+				Content: map[string]*openapi.MediaType{
+					"application/json": {Schema: &openapi.Schema{
+						Type: "object",
+						Properties: map[string]*openapi.Schema{
+							"changes":    arrayOf("Change"),
+							"last_index": {Type: "integer", Format: "int64"},
+						},
+					}},
+					"text/event-stream": {Schema: &openapi.Schema{Type: "string"}},
+				},
+			},
+			"default": problemResponse(),
+		},
+	})
+
+	// Audit log
+	doc.Add("GET", "/api/v1/audit", &openapi.Operation{
+		OperationID: "listAuditRecords",
+		Summary:     "List audit records",
+		Tags:        []string{"audit"},
+		Parameters:  append(auditParams(), limitParam()),
+		Responses:   ok("A page of audit records", page("records", "AuditRecord")),
+	})
+	doc.Add("GET", "/api/v1/audit/export", &openapi.Operation{
+		OperationID: "exportAuditRecords",
+		Summary:     "Export audit records as JSON lines",
+		Tags:        []string{"audit"},
+		Parameters:  auditParams(),
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "One audit record per line",
+				Content: map[string]*openapi.MediaType{
+					"application/x-ndjson": {Schema: openapi.Ref("AuditRecord")},
+				},
+			},
+			"default": problemResponse(),
+		},
+	})
+
+	// Bulk import and export
+	doc.Add("POST", "/api/v1/import", &openapi.Operation{
+		OperationID: "import",
+		Summary:     "Import printers, filaments and print jobs",
+		Tags:        []string{"import"},
+		Parameters: []*openapi.Parameter{
+			queryParam("dry_run", &openapi.Schema{Type: "boolean"}, "Only check the import"),
+			queryParam("resource", resourceTypeSchema(), "The resource type of a CSV body"),
+		},
+		RequestBody: &openapi.RequestBody{
+			Required: true,
+			Content: map[string]*openapi.MediaType{
+				"application/json": {Schema: openapi.Ref("Export")},
+				// Checked by the handler, row by row
+				"application/x-ndjson": {},
+				"text/csv":             {},
+			},
+		},
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "The report of a dry run",
+				Content:     map[string]*openapi.MediaType{"application/json": {Schema: openapi.Ref("ImportReport")}},
+			},
+			"201": {
+				Description: "The report of the import",
+				Content:     map[string]*openapi.MediaType{"application/json": {Schema: openapi.Ref("ImportReport")}},
+			},
+			"default": problemResponse(),
+		},
+	})
+	doc.Add("GET", "/api/v1/export", &openapi.Operation{
+		OperationID: "export",
+		Summary:     "Export printers, filaments and print jobs",
+		Tags:        []string{"import"},
+		Parameters: []*openapi.Parameter{
+			queryParam("format", &openapi.Schema{Type: "string", Enum: []interface{}{"json", "ndjson", "csv"}}, "json by default"),
+			queryParam("resource", resourceTypeSchema(), "The resource type to export; required for csv"),
+		},
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "Every resource as of the index in X-Applied-Index",
+				Content: map[string]*openapi.MediaType{
+					"application/json": {Schema: openapi.Ref("Export")},
+					"application/x-ndjson": {Schema: &openapi.Schema{
+						Type: "object",
+						Properties: map[string]*openapi.Schema{
+							"type":     resourceTypeSchema(),
+							"resource": {Type: "object"},
+						},
+					}},
+					"text/csv": {Schema: &openapi.Schema{Type: "string"}},
+				},
+			},
+			"default": problemResponse(),
+		},
+	})
+
+	// The description itself
+	doc.Add("GET", "/api/v1/openapi.json", &openapi.Operation{
+		OperationID: "getOpenAPI",
+		Summary:     "Get this description of the API",
+		Tags:        []string{"meta"},
+		Responses:   ok("The OpenAPI document", &openapi.Schema{Type: "object"}),
+	})
+
+	// Authentication
+	doc.Add("POST", "/api/v1/auth/keys", &openapi.Operation{
+		OperationID: "createAPIKey",
+		Summary:     "Create an API key",
+		Tags:        []string{"auth"},
+		RequestBody: jsonBody(openapi.Ref("APIKeyRequest")),
+		Responses:   created("The key, with the only copy of its secret", openapi.Ref("IssuedAPIKey")),
+	})
+	doc.Add("GET", "/api/v1/auth/keys", &openapi.Operation{
+		OperationID: "listAPIKeys",
+		Summary:     "List API keys",
+		Tags:        []string{"auth"},
+		Responses:   ok("The keys", arrayOf("APIKey")),
+	})
+	doc.Add("GET", "/api/v1/auth/keys/:id", &openapi.Operation{
+		OperationID: "getAPIKey",
+		Summary:     "Get an API key",
+		Tags:        []string{"auth"},
+		Parameters:  []*openapi.Parameter{idParam()},
+		Responses:   ok("The key", openapi.Ref("APIKey")),
+	})
+	doc.Add("POST", "/api/v1/auth/keys/:id/rotate", &openapi.Operation{
+		OperationID: "rotateAPIKey",
+		Summary:     "Give an API key a new secret",
+		Tags:        []string{"auth"},
+		Parameters:  []*openapi.Parameter{idParam(), ifMatchParam()},
+		RequestBody: optionalJSONBody(&openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"grace_period": {Type: "string", Description: "How long the old secret keeps working, such as 1h"},
+			},
+		}),
+		Responses: ok("The key, with the only copy of its new secret", openapi.Ref("IssuedAPIKey")),
+	})
+	doc.Add("DELETE", "/api/v1/auth/keys/:id", &openapi.Operation{
+		OperationID: "deleteAPIKey",
+		Summary:     "Delete an API key",
+		Tags:        []string{"auth"},
+		Parameters:  []*openapi.Parameter{idParam(), ifMatchParam()},
+		Responses:   noContent(),
+	})
+	doc.Add("GET", "/api/v1/auth/roles", &openapi.Operation{
+		OperationID: "listRoleBindings",
+		Summary:     "List the roles bound to JWT subjects",
+		Tags:        []string{"auth"},
+		Responses:   ok("The role bindings", arrayOf("RoleBinding")),
+	})
+	doc.Add("PUT", "/api/v1/auth/roles/:subject", &openapi.Operation{
+		OperationID: "setRoleBinding",
+		Summary:     "Bind a role to a JWT subject",
+		Tags:        []string{"auth"},
+		Parameters:  []*openapi.Parameter{subjectParam(), ifMatchParam()},
+		RequestBody: jsonBody(&openapi.Schema{
+			Type:       "object",
+			Properties: map[string]*openapi.Schema{"role": roleSchema()},
+			Required:   []string{"role"},
+		}),
+		Responses: ok("The role binding", openapi.Ref("RoleBinding")),
+	})
+	doc.Add("DELETE", "/api/v1/auth/roles/:subject", &openapi.Operation{
+		OperationID: "deleteRoleBinding",
+		Summary:     "Remove the role bound to a JWT subject",
+		Tags:        []string{"auth"},
+		Parameters:  []*openapi.Parameter{subjectParam(), ifMatchParam()},
+		Responses:   noContent(),
+	})
+	doc.Add("GET", "/api/v1/auth/whoami", &openapi.Operation{
+		OperationID:  "whoAmI",
+		Summary:      "Get the caller and its role",
+		Tags:         []string{"auth"},
+		Responses:    ok("The caller", openapi.Ref("Principal")),
+		RequiredRole: string(models.RoleViewer),
+	})
+
+	// Admin
+	doc.Add("GET", "/admin/digest", &openapi.Operation{
+		OperationID: "getDigest",
+		Summary:     "Get the state digest",
+		Tags:        []string{"admin"},
+		Parameters: []*openapi.Parameter{
+			queryParam("index", &openapi.Schema{Type: "integer", Format: "int64", Minimum: float64Ptr(0)}, "The index to get the digest at"),
+		},
+		Responses: ok("The digest", openapi.Ref("DigestResponse")),
+	})
+	doc.Add("GET", "/admin/invariants", &openapi.Operation{
+		OperationID: "checkInvariants",
+		Summary:     "Check the state invariants",
+		Tags:        []string{"admin"},
+		Responses:   ok("The violations and their repairs", openapi.Ref("InvariantReport")),
+	})
+	doc.Add("POST", "/admin/invariants/repair", &openapi.Operation{
+		OperationID: "repairInvariants",
+		Summary:     "Repair the state invariants",
+		Tags:        []string{"admin"},
+		Parameters: []*openapi.Parameter{
+			queryParam("dry_run", &openapi.Schema{Type: "boolean"}, "Only report the repairs"),
+		},
+		Responses: ok("The report and what was applied", &openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"report":  openapi.Ref("InvariantReport"),
+				"applied": {Type: "integer"},
+				"errors":  {Type: "array", Items: &openapi.Schema{Type: "string"}},
+			},
+		}),
+	})
+	doc.Add("GET", "/status", &openapi.Operation{
+		OperationID:  "getStatus",
+		Summary:      "Get the Raft status of this node",
+		Tags:         []string{"admin"},
+		Responses:    ok("The status", &openapi.Schema{Type: "object"}),
+		RequiredRole: string(models.RoleViewer),
+	})
+
+	setRequiredRoles(doc)
+	return doc
+}
+
+// addSecuritySchemes describes the ways callers authenticate. Every
+// operation takes either.
+func addSecuritySchemes(doc *openapi.Document) {
+	doc.Components.SecuritySchemes = map[string]*openapi.SecurityScheme{
+		"apiKey": {
+			Type:        "apiKey",
+			Description: "An API key, as <id>.<secret>",
+			Name:        "X-API-Key",
+			In:          "header",
+		},
+		"bearer": {
+			Type:         "http",
+			Description:  "A JWT signed with HMAC or RSA, whose subject has a role binding",
+			Scheme:       "bearer",
+			BearerFormat: "JWT",
+		},
+	}
+	doc.Security = []openapi.SecurityRequirement{
+		{"apiKey": {}},
+		{"bearer": {}},
+	}
+}
+
+// setRequiredRoles gives every operation without one the least role it
+// needs. Reads need viewer and writes operator. Managing who may use the
+// API, the audit log and the admin operations need admin.
+func setRequiredRoles(doc *openapi.Document) {
+	for _, item := range doc.Paths {
+		for method, op := range *item {
+			if op.RequiredRole != "" {
+				continue
+			}
+			op.RequiredRole = string(defaultRole(method, op))
+		}
+	}
+}
+
+func defaultRole(method string, op *openapi.Operation) models.Role {
+	for _, tag := range op.Tags {
+		if tag == "auth" || tag == "audit" || tag == "admin" {
+			return models.RoleAdmin
+		}
+	}
+	if method == "get" || method == "head" {
+		return models.RoleViewer
+	}
+	return models.RoleOperator
+}
+
+// addSchemas adds the schemas of the resources and responses
+func addSchemas(doc *openapi.Document) {
+	for _, v := range []interface{}{
+		models.Printer{},
+		models.Filament{},
+		models.PrintJob{},
+		models.Transaction{},
+		raft.TransactionResult{},
+		raft.FilamentAdjustment{},
+		raft.FilamentReservations{},
+		raft.ArchivedPrintJob{},
+		raft.Change{},
+		raft.AuditRecord{},
+		raft.DigestResponse{},
+		raft.InvariantReport{},
+		models.APIKey{},
+		models.RoleBinding{},
+	} {
+		doc.SchemaOf(v)
+	}
+
+	schemas := doc.Components.Schemas
+	schemas["Filament"].Properties["type"].Description = "PLA, PETG, ABS or TPU, in any case"
+	schemas["PrintJob"].Properties["status"].Description = "Queued, Running, Done or Canceled"
+	schemas["Command"].Properties["type"].Enum = commandTypes()
+	// Who issued a command is taken from the request that carries it
+	for _, name := range []string{"actor", "client_ip", "request_id"} {
+		delete(schemas["Command"].Properties, name)
+	}
+	schemas["Export"] = &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"index":      {Type: "integer", Format: "int64", Minimum: float64Ptr(0)},
+			"printers":   arrayOf("Printer"),
+			"filaments":  arrayOf("Filament"),
+			"print_jobs": arrayOf("PrintJob"),
+		},
+	}
+	importError := &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"resource": {Type: "string"},
+			"row":      {Type: "integer"},
+			"id":       {Type: "string"},
+			"error":    {Type: "string"},
+		},
+	}
+	schemas["ImportReport"] = &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"dry_run":  {Type: "boolean"},
+			"valid":    {Type: "boolean"},
+			"counts":   {Type: "object", AdditionalProperties: &openapi.Schema{Type: "integer"}},
+			"errors":   {Type: "array", Items: importError},
+			"imported": {Type: "integer"},
+		},
+	}
+
+	// Secret hashes are never shown
+	delete(schemas["APIKey"].Properties, "secret_hash")
+	delete(schemas["APIKey"].Properties, "previous_secret_hash")
+	schemas["APIKey"].Properties["role"] = roleSchema()
+	schemas["RoleBinding"].Properties["role"] = roleSchema()
+	schemas["APIKeyRequest"] = &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"id":          {Type: "string", Description: "Generated if left out"},
+			"description": {Type: "string"},
+			"role":        roleSchema(),
+			"expires_at":  {Type: "string", Format: "date-time"},
+		},
+		Required: []string{"role"},
+	}
+	issued := &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{}}
+	for name, property := range schemas["APIKey"].Properties {
+		issued.Properties[name] = property
+	}
+	issued.Properties["api_key"] = &openapi.Schema{Type: "string", Description: "The key to present, shown only once"}
+	schemas["IssuedAPIKey"] = issued
+	schemas["Principal"] = &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"subject": {Type: "string"},
+			"role":    roleSchema(),
+			"method":  {Type: "string", Enum: []interface{}{"api_key", "jwt", "bootstrap", "none"}},
+		},
+	}
+	schemas["Problem"] = &openapi.Schema{
+		Type:        "object",
+		Description: "An RFC 7807 problem",
+		Properties: map[string]*openapi.Schema{
+			"type":       {Type: "string"},
+			"title":      {Type: "string"},
+			"status":     {Type: "integer"},
+			"code":       {Type: "string"},
+			"detail":     {Type: "string"},
+			"instance":   {Type: "string"},
+			"request_id": {Type: "string"},
+
+			// Extensions some problems carry
+			"location":        {Type: "string", Description: "Where the invalid value is, such as body.reason"},
+			"operation":       {Type: "integer", Description: "The position of the transaction operation that failed"},
+			"precondition":    {Type: "integer", Description: "The position of the transaction precondition that failed"},
+			"leader":          {Type: "string", Description: "The Raft address of the leader to send writes to"},
+			"resync_required": {Type: "boolean"},
+			"errors":          {Type: "array", Items: importError, Description: "The resources an import failed on"},
+		},
+		Required: []string{"type", "title", "status", "code"},
+	}
+}
+
+func commandTypes() []interface{} {
+	var types []interface{}
+	for _, t := range []models.CommandType{
+		models.AddPrinter, models.AddFilament, models.AddPrintJob, models.UpdatePrintJob,
+		models.UpsertPrinter, models.UpsertFilament, models.UpsertPrintJob, models.PurgePrintJobs,
+		models.UpdatePrinter, models.DeletePrinter, models.UpdateFilament, models.DeleteFilament,
+		models.AdjustFilamentWeight, models.DeletePrintJob, models.ImportPrintJob, models.CommitTransaction,
+	} {
+		types = append(types, string(t))
+	}
+	return types
+}
+
+// auditCommandTypes lists the commands audit records can be for, including
+// those that can't be part of a transaction
+func auditCommandTypes() []interface{} {
+	return append(commandTypes(), string(models.ReportDivergence))
+}
+
+func resourceTypeSchema() *openapi.Schema {
+	return &openapi.Schema{Type: "string", Enum: []interface{}{raft.ResourcePrinters, raft.ResourceFilaments, raft.ResourcePrintJobs}}
+}
+
+func statusSchema() *openapi.Schema {
+	return &openapi.Schema{Type: "string", Enum: []interface{}{"Queued", "Running", "Done", "Canceled"}}
+}
+
+func roleSchema() *openapi.Schema {
+	return &openapi.Schema{Type: "string", Enum: []interface{}{
+		string(models.RoleViewer), string(models.RoleOperator), string(models.RoleAdmin),
+	}}
+}
+
+func subjectParam() *openapi.Parameter {
+	return &openapi.Parameter{Name: "subject", In: "path", Required: true, Schema: &openapi.Schema{Type: "string"}}
+}
+
+func idParam() *openapi.Parameter {
+	return &openapi.Parameter{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "string"}}
+}
+
+func ifMatchParam() *openapi.Parameter {
+	return &openapi.Parameter{
+		Name:        "If-Match",
+		In:          "header",
+		Description: "The ETag the resource has to have, or * for any version of an existing resource",
+		Schema:      &openapi.Schema{Type: "string"},
+	}
+}
+
+func queryParam(name string, schema *openapi.Schema, description string) *openapi.Parameter {
+	return &openapi.Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+func cascadeParam() *openapi.Parameter {
+	return queryParam("cascade", &openapi.Schema{Type: "string", Enum: []interface{}{raft.CascadeCancel}},
+		"cancel cancels the jobs still using the resource")
+}
+
+func limitParam() *openapi.Parameter {
+	return queryParam("limit", &openapi.Schema{Type: "integer", Format: "int32", Minimum: float64Ptr(1), Maximum: float64Ptr(1000)},
+		"The page size, 100 by default")
+}
+
+func indexCursorParam() *openapi.Parameter {
+	return queryParam("cursor", &openapi.Schema{Type: "integer", Format: "int64", Minimum: float64Ptr(0)},
+		"The next_cursor of the previous page")
+}
+
+// listParams returns the parameters of a resource list
+func listParams(resourceType string) []*openapi.Parameter {
+	params := []*openapi.Parameter{
+		limitParam(),
+		queryParam("cursor", &openapi.Schema{Type: "string"}, "The X-Next-Cursor of the previous page"),
+		queryParam("sort", &openapi.Schema{Type: "string"}, "Comma-separated fields, descending when prefixed with -"),
+		queryParam("filter", &openapi.Schema{Type: "string"}, "A filter expression, such as status = Queued and print_weight_in_grams > 100"),
+	}
+	params = append(params, asOfParams("List the resources as they were")...)
+	for _, field := range raft.FilterFields(resourceType) {
+		params = append(params, queryParam(field, &openapi.Schema{Type: "string"}, "Only list resources whose "+field+" equals this"))
+	}
+	return params
+}
+
+// asOfParams returns the parameters of a historical read, described as
+// read " at this log index"
+func asOfParams(read string) []*openapi.Parameter {
+	return []*openapi.Parameter{
+		queryParam("as_of_index", &openapi.Schema{Type: "integer", Format: "int64", Minimum: float64Ptr(0)}, read+" at this log index"),
+		queryParam("as_of_time", &openapi.Schema{Type: "string", Format: "date-time"}, read+" at this time"),
+	}
+}
+
+func auditParams() []*openapi.Parameter {
+	return []*openapi.Parameter{
+		queryParam("actor", &openapi.Schema{Type: "string"}, ""),
+		queryParam("command", &openapi.Schema{Type: "string", Enum: auditCommandTypes()}, ""),
+		queryParam("resource_type", &openapi.Schema{Type: "string"}, ""),
+		queryParam("resource_id", &openapi.Schema{Type: "string"}, ""),
+		queryParam("result", &openapi.Schema{Type: "string", Enum: []interface{}{raft.AuditResultOK, raft.AuditResultError}}, ""),
+		queryParam("from", &openapi.Schema{Type: "string", Format: "date-time"}, ""),
+		queryParam("to", &openapi.Schema{Type: "string", Format: "date-time"}, ""),
+		indexCursorParam(),
+	}
+}
+
+func jsonBody(schema *openapi.Schema) *openapi.RequestBody {
+	body := optionalJSONBody(schema)
+	body.Required = true
+	return body
+}
+
+func optionalJSONBody(schema *openapi.Schema) *openapi.RequestBody {
+	return &openapi.RequestBody{
+		Content: map[string]*openapi.MediaType{"application/json": {Schema: schema}},
+	}
+}
+
+func arrayOf(name string) *openapi.Schema {
+	return &openapi.Schema{Type: "array", Items: openapi.Ref(name)}
+}
+
+// page is the schema of a page of items listed under key, with the cursor
+// of the next page
+func page(key, name string) *openapi.Schema {
+	return &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			key:           arrayOf(name),
+			"next_cursor": {Type: "string"},
+		},
+	}
+}
+
+func problemResponse() *openapi.Response {
+	return &openapi.Response{
+		Description: "A problem",
+		Content: map[string]*openapi.MediaType{
+			"application/problem+json": {Schema: openapi.Ref("Problem")},
+		},
+	}
+}
+
+func response(status, description string, schema *openapi.Schema) map[string]*openapi.Response {
+	return map[string]*openapi.Response{
+		status: {
+			Description: description,
+			Content:     map[string]*openapi.MediaType{"application/json": {Schema: schema}},
+		},
+		"default": problemResponse(),
+	}
+}
+
+func ok(description string, schema *openapi.Schema) map[string]*openapi.Response {
+	return response("200", description, schema)
+}
+
+func created(description string, schema *openapi.Schema) map[string]*openapi.Response {
+	return response("201", description, schema)
+}
+
+func noContent() map[string]*openapi.Response {
+	return map[string]*openapi.Response{
+		"204":     {Description: "Deleted"},
+		"default": problemResponse(),
+	}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
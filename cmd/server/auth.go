@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/devadigapratham/raft3d/api/auth"
+	"github.com/devadigapratham/raft3d/api/handlers"
+	"github.com/devadigapratham/raft3d/config"
+	"github.com/devadigapratham/raft3d/raft"
+)
+
+// minBootstrapKeyLength keeps bootstrap keys from being guessable
+const minBootstrapKeyLength = 16
+
+// loadAuthenticator builds the Authenticator of the APIs from the key files
+// in the configuration. It returns nil when authentication is off.
+func loadAuthenticator(cfg *config.Config, node *raft.Node) (*handlers.Authenticator, error) {
+	if !cfg.Auth {
+		return nil, nil
+	}
+
+	var bootstrapKey string
+	if cfg.AuthBootstrapKeyFile != "" {
+		key, err := readKeyFile(cfg.AuthBootstrapKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bootstrap key: %v", err)
+		}
+		if len(key) < minBootstrapKeyLength {
+			return nil, fmt.Errorf("bootstrap key must be at least %d characters", minBootstrapKeyLength)
+		}
+		bootstrapKey = string(key)
+	}
+
+	var verifier *auth.JWTVerifier
+	if cfg.JWTHMACSecretFile != "" || cfg.JWTRSAPublicKeyFile != "" {
+		verifier = &auth.JWTVerifier{
+			Issuer:   cfg.JWTIssuer,
+			Audience: cfg.JWTAudience,
+		}
+		if cfg.JWTHMACSecretFile != "" {
+			secret, err := readKeyFile(cfg.JWTHMACSecretFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read JWT HMAC secret: %v", err)
+			}
+			verifier.HMACSecret = secret
+		}
+		if cfg.JWTRSAPublicKeyFile != "" {
+			data, err := os.ReadFile(cfg.JWTRSAPublicKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read JWT RSA public key: %v", err)
+			}
+			if verifier.RSAPublicKey, err = auth.ParseRSAPublicKey(data); err != nil {
+				return nil, fmt.Errorf("failed to parse JWT RSA public key: %v", err)
+			}
+		}
+	}
+
+	if bootstrapKey == "" {
+		log.Printf("Authentication is on without a bootstrap key: only existing API keys and bound JWT subjects can call the APIs, and digest checks can't reach other nodes")
+	}
+	return handlers.NewAuthenticator(node, bootstrapKey, verifier), nil
+}
+
+// readKeyFile reads a key from a file, without surrounding whitespace
+func readKeyFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return nil, fmt.Errorf("%s is empty", path)
+	}
+	return []byte(key), nil
+}
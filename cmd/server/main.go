@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,9 +12,22 @@ import (
 	"github.com/devadigapratham/raft3d/api"
 	"github.com/devadigapratham/raft3d/config"
 	"github.com/devadigapratham/raft3d/raft"
+	"google.golang.org/grpc"
 )
 
 func main() {
+	// Offline replay of a stopped node's log
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		runReplay(config.ParseReplayFlags(os.Args[2:]))
+		return
+	}
+
+	// Writing or checking the OpenAPI document
+	if len(os.Args) > 1 && os.Args[1] == "openapi" {
+		runOpenAPI(config.ParseOpenAPIFlags(os.Args[2:]))
+		return
+	}
+
 	// Parse command line flags
 	cfg := config.ParseFlags()
 
@@ -40,6 +54,21 @@ func main() {
 		RaftDir:   cfg.RaftDir,
 		Bootstrap: cfg.Bootstrap,
 		Peers:     cfg.Peers,
+
+		StateBackend:        cfg.StateBackend,
+		SnapshotCompression: cfg.SnapshotCompression,
+		HistoryRetention: raft.HistoryRetention{
+			Indexes:  cfg.HistoryRetainIndexes,
+			Duration: cfg.HistoryRetainDuration,
+		},
+		AuditRetention: raft.AuditRetention{
+			Indexes:  cfg.AuditRetainIndexes,
+			Duration: cfg.AuditRetainDuration,
+		},
+		InvariantChecks: raft.InvariantChecks{
+			AfterApply:   cfg.CheckInvariantsAfterApply,
+			AfterRestore: cfg.CheckInvariantsAfterRestore,
+		},
 	}
 
 	node, err := raft.NewNode(raftConfig)
@@ -47,11 +76,23 @@ func main() {
 		log.Fatalf("Failed to create Raft node: %v", err)
 	}
 
-	// Create transport
+	// Load the credentials callers authenticate with
+	authenticator, err := loadAuthenticator(cfg, node)
+	if err != nil {
+		log.Fatalf("Failed to set up authentication: %v", err)
+	}
+
+	// Create transport, which calls other nodes with the bootstrap key
 	transport := raft.NewTransport(node)
+	if authenticator != nil {
+		transport.APIKey = authenticator.BootstrapKey
+	}
 
 	// Setup HTTP router
-	router := api.SetupRouter(node)
+	router, err := api.SetupRouter(node, authenticator)
+	if err != nil {
+		log.Fatalf("Failed to set up HTTP router: %v", err)
+	}
 
 	// Add Raft transport handler
 	http.Handle("/raft/", http.StripPrefix("/raft", transport.RaftHandler()))
@@ -70,6 +111,22 @@ func main() {
 		}
 	}()
 
+	// Start the gRPC server if it has an address
+	var grpcServer *grpc.Server
+	if cfg.GRPCAddr != "" {
+		listener, err := net.Listen("tcp", cfg.GRPCAddr)
+		if err != nil {
+			log.Fatalf("Failed to listen for gRPC: %v", err)
+		}
+		grpcServer = api.SetupGRPCServer(node, authenticator)
+		go func() {
+			log.Printf("Starting gRPC server on %s", cfg.GRPCAddr)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("Failed to start gRPC server: %v", err)
+			}
+		}()
+	}
+
 	// Join the cluster if needed
 	if cfg.JoinAddr != "" && !cfg.Bootstrap {
 		log.Printf("Joining cluster at %s", cfg.JoinAddr)
@@ -79,12 +136,33 @@ func main() {
 		}
 	}
 
+	// Periodically check replicas for divergence
+	stopDigestChecks := make(chan struct{})
+	if cfg.DigestCheckInterval > 0 {
+		go transport.RunDigestChecks(cfg.DigestCheckInterval, stopDigestChecks)
+	}
+
+	// Purge expired print jobs while leading
+	stopJanitor := make(chan struct{})
+	retention := raft.JobRetention{
+		MaxAge:         cfg.JobRetainDuration,
+		KeepPerPrinter: cfg.JobRetainPerPrinter,
+	}
+	if (retention.MaxAge > 0 || retention.KeepPerPrinter > 0) && cfg.JanitorInterval > 0 {
+		go node.RunJanitor(retention, cfg.JanitorInterval, stopJanitor)
+	}
+
 	// Handle shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	log.Println("Shutting down...")
+	close(stopDigestChecks)
+	close(stopJanitor)
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
 
 	// Close the store
 	if err := store.Close(); err != nil {
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/devadigapratham/raft3d/api"
+	"github.com/devadigapratham/raft3d/config"
+	"github.com/gin-gonic/gin"
+)
+
+// runOpenAPI writes the OpenAPI document describing the HTTP API, or checks
+// that a written one is up to date. Building the router first fails if the
+// routes and the document disagree.
+func runOpenAPI(cfg *config.OpenAPIConfig) {
+	gin.SetMode(gin.ReleaseMode)
+	if _, err := api.SetupRouter(nil, nil); err != nil {
+		log.Fatalf("Failed to set up HTTP router: %v", err)
+	}
+
+	doc, err := json.MarshalIndent(api.Spec(), "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to encode OpenAPI document: %v", err)
+	}
+	doc = append(doc, '\n')
+
+	if cfg.Check {
+		written, err := os.ReadFile(cfg.Output)
+		if err != nil {
+			log.Fatalf("Failed to read OpenAPI document: %v", err)
+		}
+		if !bytes.Equal(written, doc) {
+			log.Fatalf("%s is out of date, regenerate it with go generate ./api", cfg.Output)
+		}
+		return
+	}
+
+	if cfg.Output == "" {
+		os.Stdout.Write(doc)
+		return
+	}
+	if err := os.WriteFile(cfg.Output, doc, 0644); err != nil {
+		log.Fatalf("Failed to write OpenAPI document: %v", err)
+	}
+}
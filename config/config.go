@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 )
 
 // Config represents the application configuration
@@ -17,6 +18,57 @@ type Config struct {
 	Bootstrap bool
 	JoinAddr  string
 	Peers     []string
+
+	// GRPCAddr is the address the gRPC API is served on, empty disables it
+	GRPCAddr string
+
+	// StateBackend selects where the FSM keeps its state (memory or bolt)
+	StateBackend string
+
+	// SnapshotCompression is the compression used for new snapshots
+	// (none, gzip or zstd)
+	SnapshotCompression string
+
+	// HistoryRetainIndexes and HistoryRetainDuration bound the history
+	// kept for as-of queries, both 0 disables it
+	HistoryRetainIndexes  uint64
+	HistoryRetainDuration time.Duration
+
+	// AuditRetainIndexes and AuditRetainDuration bound the audit log, both
+	// 0 keeps every record
+	AuditRetainIndexes  uint64
+	AuditRetainDuration time.Duration
+
+	// DigestCheckInterval is how often the leader compares state digests
+	// across the cluster, 0 disables the check
+	DigestCheckInterval time.Duration
+
+	// CheckInvariantsAfterApply and CheckInvariantsAfterRestore make the
+	// FSM check its invariants after every apply or restore
+	CheckInvariantsAfterApply   bool
+	CheckInvariantsAfterRestore bool
+
+	// JobRetainDuration and JobRetainPerPrinter bound how long finished
+	// print jobs are kept before the janitor purges them, both 0 keeps
+	// them forever. JanitorInterval is how often the janitor runs.
+	JobRetainDuration   time.Duration
+	JobRetainPerPrinter int
+	JanitorInterval     time.Duration
+
+	// Auth makes every request to the APIs authenticate with an API key
+	// or a JWT
+	Auth bool
+	// AuthBootstrapKeyFile holds an admin API key that isn't stored in
+	// the FSM, for creating the first keys and for nodes calling each
+	// other. Every node needs the same one.
+	AuthBootstrapKeyFile string
+	// JWTHMACSecretFile and JWTRSAPublicKeyFile hold the keys JWTs are
+	// verified with, JWTs are refused without either
+	JWTHMACSecretFile   string
+	JWTRSAPublicKeyFile string
+	// JWTIssuer and JWTAudience, if set, must match the iss and aud claims
+	JWTIssuer   string
+	JWTAudience string
 }
 
 // ParseFlags parses command line flags and returns a Config
@@ -28,9 +80,28 @@ func ParseFlags() *Config {
 	flag.StringVar(&config.RaftAddr, "raft-addr", "", "Raft transport address (required)")
 	flag.StringVar(&config.RaftDir, "raft-dir", "", "Raft storage directory (required)")
 	flag.StringVar(&config.HTTPAddr, "http-addr", "", "HTTP API address (required)")
+	flag.StringVar(&config.GRPCAddr, "grpc-addr", "", "gRPC API address (empty disables the gRPC API)")
 	flag.BoolVar(&config.Bootstrap, "bootstrap", false, "Bootstrap the cluster")
 	flag.StringVar(&config.JoinAddr, "join", "", "Join address of an existing node")
 	peersStr := flag.String("peers", "", "Comma-separated list of peer addresses")
+	flag.StringVar(&config.StateBackend, "state-backend", "memory", "Where the FSM keeps its state: memory or bolt")
+	flag.StringVar(&config.SnapshotCompression, "snapshot-compression", "none", "Compression for new snapshots: none, gzip or zstd")
+	flag.Uint64Var(&config.HistoryRetainIndexes, "history-retain-indexes", 10000, "Log indexes of history kept for as-of queries (0 for no index limit, both history limits 0 disables history)")
+	flag.DurationVar(&config.HistoryRetainDuration, "history-retain-duration", 0, "How long history is kept for as-of queries (0 for no time limit)")
+	flag.Uint64Var(&config.AuditRetainIndexes, "audit-retain-indexes", 100000, "Log indexes of audit records kept (0 for no index limit, both audit limits 0 keeps every record)")
+	flag.DurationVar(&config.AuditRetainDuration, "audit-retain-duration", 0, "How long audit records are kept (0 for no time limit)")
+	flag.DurationVar(&config.DigestCheckInterval, "digest-check-interval", 30*time.Second, "How often the leader compares state digests across the cluster (0 disables)")
+	flag.BoolVar(&config.CheckInvariantsAfterApply, "check-invariants-after-apply", false, "Check the FSM invariants after every applied entry (slow, for debugging)")
+	flag.DurationVar(&config.JobRetainDuration, "job-retain-duration", 0, "How long finished print jobs are kept before they are archived (0 for no time limit)")
+	flag.IntVar(&config.JobRetainPerPrinter, "job-retain-per-printer", 0, "Finished print jobs kept per printer before older ones are archived (0 for no count limit, both job limits 0 keeps jobs forever)")
+	flag.DurationVar(&config.JanitorInterval, "janitor-interval", 10*time.Minute, "How often the leader purges expired print jobs")
+	flag.BoolVar(&config.CheckInvariantsAfterRestore, "check-invariants-after-restore", false, "Check the FSM invariants after restoring a snapshot")
+	flag.BoolVar(&config.Auth, "auth", false, "Require an API key or JWT on every request to the APIs")
+	flag.StringVar(&config.AuthBootstrapKeyFile, "auth-bootstrap-key-file", "", "File holding an admin API key kept out of the FSM, for creating the first keys and for calls between nodes")
+	flag.StringVar(&config.JWTHMACSecretFile, "jwt-hmac-secret-file", "", "File holding the secret HS256, HS384 and HS512 JWTs are verified with")
+	flag.StringVar(&config.JWTRSAPublicKeyFile, "jwt-rsa-public-key-file", "", "PEM file holding the public key RS256, RS384 and RS512 JWTs are verified with")
+	flag.StringVar(&config.JWTIssuer, "jwt-issuer", "", "Issuer JWTs must name in their iss claim (empty accepts any)")
+	flag.StringVar(&config.JWTAudience, "jwt-audience", "", "Audience JWTs must name in their aud claim (empty accepts any)")
 
 	// Parse flags
 	flag.Parse()
@@ -67,3 +138,80 @@ func ParseFlags() *Config {
 
 	return config
 }
+
+// ReplayConfig represents the configuration of the replay command
+type ReplayConfig struct {
+	RaftDir    string
+	UntilIndex uint64
+	// UntilTime, if set, replays up to the last entry appended at or
+	// before it instead
+	UntilTime time.Time
+	Output    string
+}
+
+// ParseReplayFlags parses the flags of the replay command and returns a ReplayConfig
+func ParseReplayFlags(args []string) *ReplayConfig {
+	config := &ReplayConfig{}
+
+	// Define flags
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	fs.StringVar(&config.RaftDir, "raft-dir", "", "Raft storage directory of a stopped node (required)")
+	fs.Uint64Var(&config.UntilIndex, "until-index", 0, "Last log index to apply (default: the whole log)")
+	untilTime := fs.String("until-time", "", "Apply the entries appended up to this RFC 3339 time (default: the whole log)")
+	fs.StringVar(&config.Output, "output", "", "File to write the resulting state to (default: stdout)")
+
+	// Parse flags
+	fs.Parse(args)
+
+	if *untilTime != "" {
+		if config.UntilIndex != 0 {
+			fmt.Fprintf(os.Stderr, "-until-index and -until-time can't be combined\n")
+			fs.Usage()
+			os.Exit(1)
+		}
+		t, err := time.Parse(time.RFC3339, *untilTime)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid -until-time, expected RFC 3339: %v\n", err)
+			fs.Usage()
+			os.Exit(1)
+		}
+		config.UntilTime = t
+	}
+
+	// Validate required flags
+	if config.RaftDir == "" {
+		fmt.Fprintf(os.Stderr, "Raft directory is required\n")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	return config
+}
+
+// OpenAPIConfig represents the configuration of the openapi command
+type OpenAPIConfig struct {
+	Output string
+	Check  bool
+}
+
+// ParseOpenAPIFlags parses the flags of the openapi command and returns an
+// OpenAPIConfig
+func ParseOpenAPIFlags(args []string) *OpenAPIConfig {
+	config := &OpenAPIConfig{}
+
+	// Define flags
+	fs := flag.NewFlagSet("openapi", flag.ExitOnError)
+	fs.StringVar(&config.Output, "output", "", "File to write the OpenAPI document to (default: stdout)")
+	fs.BoolVar(&config.Check, "check", false, "Fail unless the file given by -output is up to date")
+
+	// Parse flags
+	fs.Parse(args)
+
+	if config.Check && config.Output == "" {
+		fmt.Fprintf(os.Stderr, "-check needs the file to check in -output\n")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	return config
+}
@@ -0,0 +1,249 @@
+package raft
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+// ArchivedPrintJob is a purged or deleted print job as kept in the archive
+type ArchivedPrintJob struct {
+	// Index is the log index of the entry that purged or deleted the job
+	Index      uint64           `json:"index"`
+	ArchivedAt time.Time        `json:"archived_at"`
+	Job        *models.PrintJob `json:"job"`
+}
+
+// ErrInvalidCursor is returned for an archive cursor that doesn't point at
+// the start of a record
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ArchiveFilter selects archived print jobs. Zero fields match everything.
+type ArchiveFilter struct {
+	PrinterID  string
+	FilamentID string
+	Status     string
+}
+
+// matches reports whether an archived job passes the filter
+func (af *ArchiveFilter) matches(archived *ArchivedPrintJob) bool {
+	job := archived.Job
+	switch {
+	case job == nil:
+		return false
+	case af.PrinterID != "" && job.PrinterID != af.PrinterID:
+		return false
+	case af.FilamentID != "" && job.FilamentID != af.FilamentID:
+		return false
+	case af.Status != "" && job.Status != af.Status:
+		return false
+	}
+	return true
+}
+
+// jobArchive is a local, append-only JSON lines file of purged print jobs.
+// Every node archives the purges it applies. Entries it archived before are
+// skipped when the log is applied again after a restart; purges a node only
+// learns about through a snapshot never reach its archive.
+type jobArchive struct {
+	mu        sync.Mutex
+	path      string
+	file      *os.File
+	lastIndex uint64
+}
+
+// openJobArchive opens the archive at path, creating it if needed. A torn
+// last line, left by a crash in the middle of a write, is cut off so the
+// next record starts on a line of its own.
+func openJobArchive(path string) (*jobArchive, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open job archive: %v", err)
+	}
+
+	a := &jobArchive{path: path, file: file}
+	var end int64
+	err = a.scan(0, func(archived *ArchivedPrintJob, next int64) bool {
+		if archived.Index > a.lastIndex {
+			a.lastIndex = archived.Index
+		}
+		end = next
+		return true
+	})
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read job archive: %v", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read job archive: %v", err)
+	}
+	if info.Size() > end {
+		log.Printf("Cutting a torn record of %d bytes off the end of job archive %s", info.Size()-end, path)
+		if err := file.Truncate(end); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to truncate job archive: %v", err)
+		}
+	}
+	return a, nil
+}
+
+// append archives the jobs purged by the entry at index, unless the archive
+// already holds that entry
+func (a *jobArchive) append(index uint64, archivedAt time.Time, jobs []*models.PrintJob) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if index <= a.lastIndex || len(jobs) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, job := range jobs {
+		if err := enc.Encode(&ArchivedPrintJob{Index: index, ArchivedAt: archivedAt, Job: job}); err != nil {
+			return err
+		}
+	}
+	// A crash can cut the write short. Scans ignore the torn line it
+	// leaves, and it is dropped when the archive is opened again.
+	if _, err := a.file.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	a.lastIndex = index
+	return nil
+}
+
+// scan calls fn for every archived job from byte offset on, with the offset
+// of the next record, until fn returns false. The offset has to be where a
+// record starts, or scan fails with ErrInvalidCursor. A torn last line is
+// ignored.
+func (a *jobArchive) scan(offset int64, fn func(archived *ArchivedPrintJob, next int64) bool) error {
+	file, err := os.Open(a.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Records start at the beginning or right after a newline
+	start := offset
+	if offset > 0 {
+		start--
+	}
+	if _, err := file.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+	r := bufio.NewReader(file)
+	if offset > 0 {
+		b, err := r.ReadByte()
+		if err == io.EOF || (err == nil && b != '\n') {
+			return fmt.Errorf("%w: %d is not the start of an archive record", ErrInvalidCursor, offset)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		offset += int64(len(line))
+
+		var archived ArchivedPrintJob
+		if err := json.Unmarshal(line, &archived); err != nil {
+			return fmt.Errorf("corrupt archive record before offset %d: %v", offset, err)
+		}
+		if !fn(&archived, offset) {
+			return nil
+		}
+	}
+}
+
+func (a *jobArchive) close() error {
+	return a.file.Close()
+}
+
+// OpenJobArchive makes the FSM archive the print jobs it purges to the JSON
+// lines file at path
+func (f *FSM) OpenJobArchive(path string) error {
+	archive, err := openJobArchive(path)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.archive = archive
+	return nil
+}
+
+// archivePurged archives the print jobs among the deleted resources, as
+// they were when they were deleted. An archive that can't be written must not
+// stop the FSM, so failures are only logged. The caller must hold the write
+// lock.
+func (f *FSM) archivePurged(index uint64, appendedAt time.Time, deleted []*mutation) {
+	var jobs []*models.PrintJob
+	for _, c := range deleted {
+		if c.bucket != bucketPrintJobs {
+			continue
+		}
+		var job models.PrintJob
+		if err := json.Unmarshal(c.before, &job); err != nil {
+			log.Printf("Failed to decode purged print job %s: %v", c.id, err)
+			continue
+		}
+		jobs = append(jobs, &job)
+	}
+
+	if err := f.archive.append(index, appendedAt, jobs); err != nil {
+		log.Printf("Failed to archive print jobs purged at index %d: %v", index, err)
+	}
+}
+
+// GetArchivedPrintJobs returns up to limit archived jobs that pass filter,
+// oldest first, starting at the archive offset cursor. The second return
+// value is the cursor to continue from, or 0 when there are no more.
+func (f *FSM) GetArchivedPrintJobs(filter ArchiveFilter, cursor int64, limit int) ([]*ArchivedPrintJob, int64, error) {
+	f.mu.RLock()
+	archive := f.archive
+	f.mu.RUnlock()
+
+	jobs := make([]*ArchivedPrintJob, 0)
+	if archive == nil {
+		return jobs, 0, nil
+	}
+
+	var next, offset int64
+	offset = cursor
+	err := archive.scan(cursor, func(archived *ArchivedPrintJob, end int64) bool {
+		if filter.matches(archived) {
+			if len(jobs) == limit {
+				next = offset
+				return false
+			}
+			jobs = append(jobs, archived)
+		}
+		offset = end
+		return true
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return jobs, next, nil
+}
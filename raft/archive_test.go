@@ -0,0 +1,84 @@
+package raft
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+func TestJobArchiveTornRecordAndCursor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive.jsonl")
+	a, err := openJobArchive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := a.append(5, at, []*models.PrintJob{{ID: "j1", Status: "Done"}, {ID: "j2", Status: "Done"}}); err != nil {
+		t.Fatal(err)
+	}
+	a.close()
+
+	// A crash in the middle of the next write leaves a torn line
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(`{"index":9,"archived_at":"2024-01-0`); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	a, err = openJobArchive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.close()
+	if a.lastIndex != 5 {
+		t.Errorf("archive is at index %d, want 5", a.lastIndex)
+	}
+	// The entry whose write was torn is archived again on its own line
+	if err := a.append(9, at, []*models.PrintJob{{ID: "j3", Status: "Canceled"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	f := newTestFSM(t)
+	f.archive = a
+	var ids []string
+	var offsets []int64
+	var cursor int64
+	for {
+		jobs, next, err := f.GetArchivedPrintJobs(ArchiveFilter{}, cursor, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, archived := range jobs {
+			ids = append(ids, archived.Job.ID)
+		}
+		if next == 0 {
+			break
+		}
+		offsets = append(offsets, next)
+		cursor = next
+	}
+	if len(ids) != 3 || ids[0] != "j1" || ids[1] != "j2" || ids[2] != "j3" {
+		t.Fatalf("archive lists %v, want j1, j2 and j3", ids)
+	}
+
+	// Cursors that don't point at the start of a record are rejected
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cursor := range []int64{1, offsets[0] - 1, offsets[0] + 3, info.Size() + 1} {
+		if _, _, err := f.GetArchivedPrintJobs(ArchiveFilter{}, cursor, 10); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("cursor %d returned %v, want %v", cursor, err, ErrInvalidCursor)
+		}
+	}
+	if jobs, next, err := f.GetArchivedPrintJobs(ArchiveFilter{}, info.Size(), 10); err != nil || len(jobs) != 0 || next != 0 {
+		t.Errorf("cursor at the end returned %d jobs, next %d and %v, want an empty last page", len(jobs), next, err)
+	}
+}
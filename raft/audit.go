@@ -0,0 +1,266 @@
+package raft
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+// bucketAudit holds an audit record for every applied command, keyed by
+// log index
+const bucketAudit = "audit"
+
+const (
+	// maxAuditPrune bounds how many audit records a single apply deletes,
+	// so catching up on a lowered retention doesn't stall applies
+	maxAuditPrune = 1000
+
+	// DefaultAuditRetainIndexes is the default number of log indexes the
+	// audit log reaches back
+	DefaultAuditRetainIndexes = 100000
+)
+
+// AuditRetention bounds how far back the audit log reaches. A zero field
+// doesn't limit it; both zero keeps every record.
+type AuditRetention struct {
+	// Indexes is the number of log indexes to keep audit records for
+	Indexes uint64
+	// Duration is how long to keep audit records for, by leader time
+	Duration time.Duration
+}
+
+// SetAuditRetention sets how far back the audit log reaches. Records past
+// it are pruned gradually as new entries are applied.
+func (f *FSM) SetAuditRetention(retention AuditRetention) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.auditRetention = retention
+}
+
+// Audit results
+const (
+	AuditResultOK    = "ok"
+	AuditResultError = "error"
+)
+
+// AuditChange is a resource change made by an audited command
+type AuditChange struct {
+	Type   string          `json:"type"`
+	ID     string          `json:"id"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditRecord describes who applied which command, and what came of it
+type AuditRecord struct {
+	Index        uint64             `json:"index"`
+	Time         time.Time          `json:"time"`
+	Actor        string             `json:"actor"`
+	ClientIP     string             `json:"client_ip,omitempty"`
+	RequestID    string             `json:"request_id,omitempty"`
+	Reason       string             `json:"reason,omitempty"`
+	Command      models.CommandType `json:"command"`
+	ResourceType string             `json:"resource_type,omitempty"`
+	ResourceID   string             `json:"resource_id,omitempty"`
+	Changes      []AuditChange      `json:"changes,omitempty"`
+	Result       string             `json:"result"`
+	Error        string             `json:"error,omitempty"`
+}
+
+// AuditFilter selects audit records. Zero fields match everything.
+type AuditFilter struct {
+	Actor        string
+	Command      models.CommandType
+	ResourceType string
+	ResourceID   string
+	Result       string
+	From         time.Time
+	To           time.Time
+}
+
+// matches reports whether a record passes the filter
+func (af *AuditFilter) matches(record *AuditRecord) bool {
+	switch {
+	case af.Actor != "" && record.Actor != af.Actor:
+		return false
+	case af.Command != "" && record.Command != af.Command:
+		return false
+	case af.ResourceType != "" && record.ResourceType != af.ResourceType:
+		return false
+	case af.ResourceID != "" && record.ResourceID != af.ResourceID:
+		return false
+	case af.Result != "" && record.Result != af.Result:
+		return false
+	case !af.From.IsZero() && record.Time.Before(af.From):
+		return false
+	case !af.To.IsZero() && record.Time.After(af.To):
+		return false
+	}
+	return true
+}
+
+// auditKey builds the key of the audit record of the entry at index
+func auditKey(index uint64) string {
+	return fmt.Sprintf("%020d", index)
+}
+
+// commandResource returns the type and ID of the resource a command targets
+func commandResource(cmd *models.Command) (string, string) {
+	switch {
+	case cmd.Printer != nil:
+		return bucketPrinters, cmd.Printer.ID
+	case cmd.Filament != nil:
+		return bucketFilaments, cmd.Filament.ID
+	case cmd.PrintJob != nil:
+		return bucketPrintJobs, cmd.PrintJob.ID
+	case cmd.JobID != "":
+		return bucketPrintJobs, cmd.JobID
+	case cmd.PrinterID != "":
+		return bucketPrinters, cmd.PrinterID
+	case cmd.FilamentID != "":
+		return bucketFilaments, cmd.FilamentID
+	case cmd.APIKey != nil:
+		return bucketAPIKeys, cmd.APIKey.ID
+	case cmd.KeyID != "":
+		return bucketAPIKeys, cmd.KeyID
+	case cmd.RoleBinding != nil:
+		return bucketRoleBindings, cmd.RoleBinding.Subject
+	case cmd.Subject != "":
+		return bucketRoleBindings, cmd.Subject
+	}
+	return "", ""
+}
+
+// newAuditRecord builds the audit record of a command applied at index
+func newAuditRecord(index uint64, appendedAt time.Time, cmd *models.Command, changes []*mutation, applyErr error) *AuditRecord {
+	record := &AuditRecord{
+		Index:     index,
+		Time:      appendedAt,
+		Actor:     cmd.Actor,
+		ClientIP:  cmd.ClientIP,
+		RequestID: cmd.RequestID,
+		Reason:    cmd.Reason,
+		Command:   cmd.Type,
+		Result:    AuditResultOK,
+	}
+	record.ResourceType, record.ResourceID = commandResource(cmd)
+	for _, c := range changes {
+		before, after := c.before, c.after
+		if c.bucket == bucketAPIKeys {
+			before, after = redactAPIKey(before), redactAPIKey(after)
+		}
+		record.Changes = append(record.Changes, AuditChange{
+			Type:   c.bucket,
+			ID:     c.id,
+			Before: before,
+			After:  after,
+		})
+	}
+	if applyErr != nil {
+		record.Result = AuditResultError
+		record.Error = applyErr.Error()
+	}
+	return record
+}
+
+// putAuditRecord stores an audit record
+func putAuditRecord(tx stateTx, record *AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return tx.put(bucketAudit, auditKey(record.Index), data)
+}
+
+// recordAudit stores an audit record and prunes the oldest records that
+// are out of retention. Retention is judged by log index and leader time
+// only, so every replica prunes the same records. The caller must hold the
+// write lock.
+func (f *FSM) recordAudit(tx stateTx, record *AuditRecord) error {
+	if err := putAuditRecord(tx, record); err != nil {
+		return err
+	}
+
+	retention := f.auditRetention
+	if retention.Indexes == 0 && retention.Duration == 0 {
+		return nil
+	}
+	var expired []string
+	err := tx.forEach(bucketAudit, func(key string, data []byte) error {
+		var old struct {
+			Index uint64    `json:"index"`
+			Time  time.Time `json:"time"`
+		}
+		if err := json.Unmarshal(data, &old); err != nil {
+			return err
+		}
+
+		expiredByIndex := retention.Indexes != 0 && old.Index+retention.Indexes <= record.Index
+		expiredByTime := retention.Duration != 0 && !old.Time.IsZero() && !record.Time.IsZero() &&
+			record.Time.Sub(old.Time) > retention.Duration
+		if (!expiredByIndex && !expiredByTime) || len(expired) == maxAuditPrune {
+			return errStopIteration
+		}
+		expired = append(expired, key)
+		return nil
+	})
+	if err != nil && err != errStopIteration {
+		return err
+	}
+
+	for _, key := range expired {
+		if err := tx.delete(bucketAudit, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForEachAuditRecord calls fn for every audit record after index afterIndex
+// that passes filter, in log order, until fn returns false. Records are
+// keyed by index, so the records up to afterIndex are skipped without being
+// read.
+func (f *FSM) ForEachAuditRecord(filter AuditFilter, afterIndex uint64, fn func(record *AuditRecord) bool) error {
+	err := f.state.view(func(tx stateTx) error {
+		return tx.forEachFrom(bucketAudit, auditKey(afterIndex+1), func(key string, data []byte) error {
+			var record AuditRecord
+			if err := json.Unmarshal(data, &record); err != nil {
+				return err
+			}
+			if !filter.matches(&record) {
+				return nil
+			}
+			if !fn(&record) {
+				return errStopIteration
+			}
+			return nil
+		})
+	})
+	if err == errStopIteration {
+		return nil
+	}
+	return err
+}
+
+// GetAuditRecords returns up to limit audit records after index afterIndex
+// that pass filter, in log order. The second return value is the index to
+// continue from, or 0 when there are no more records.
+func (f *FSM) GetAuditRecords(filter AuditFilter, afterIndex uint64, limit int) ([]*AuditRecord, uint64, error) {
+	records := make([]*AuditRecord, 0)
+	var next uint64
+	err := f.ForEachAuditRecord(filter, afterIndex, func(record *AuditRecord) bool {
+		if len(records) == limit {
+			next = records[len(records)-1].Index
+			return false
+		}
+		records = append(records, record)
+		return true
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return records, next, nil
+}
@@ -0,0 +1,50 @@
+package raft
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+func TestAuditRetentionAndCursor(t *testing.T) {
+	f := newTestFSM(t)
+	f.SetAuditRetention(AuditRetention{Indexes: 5})
+	for i := 0; i < 10; i++ {
+		f.apply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: fmt.Sprintf("p%d", i%8), Company: "Prusa", Model: "MK4"}, Actor: "alice"})
+	}
+
+	// Indexes 6 to 10 are kept, including the failures at 9 and 10
+	records, next, err := f.GetAuditRecords(AuditFilter{}, 0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 5 || records[0].Index != 6 || next != 0 {
+		t.Fatalf("got %d records from index %d, want 5 from index 6", len(records), records[0].Index)
+	}
+	if records[4].Result != AuditResultError {
+		t.Errorf("record 10 is %s, want the failed add of p1 again", records[4].Result)
+	}
+
+	// Pages start right after their cursor
+	records, next, err = f.GetAuditRecords(AuditFilter{Actor: "alice"}, 7, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Index != 8 || records[1].Index != 9 || next != 9 {
+		t.Errorf("got %+v and cursor %d, want records 8 and 9 and cursor 9", records, next)
+	}
+
+	// Retention by time follows the leader's clock
+	f.SetAuditRetention(AuditRetention{Duration: time.Minute})
+	f.now = f.now.Add(time.Hour)
+	f.mustApply(&models.Command{Type: models.DeletePrinter, PrinterID: "p0"})
+	records, _, err = f.GetAuditRecords(AuditFilter{}, 0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Index != 11 {
+		t.Errorf("got %d records, want only the one at index 11", len(records))
+	}
+}
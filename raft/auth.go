@@ -0,0 +1,174 @@
+package raft
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+// Buckets holding who may use the API. They are replicated and snapshotted
+// like resources, but kept out of the history, change feed and exports.
+const (
+	bucketAPIKeys      = "api_keys"
+	bucketRoleBindings = "role_bindings"
+)
+
+// Resource types of the auth buckets, as named in the audit log
+const (
+	ResourceAPIKeys      = bucketAPIKeys
+	ResourceRoleBindings = bucketRoleBindings
+)
+
+// applyCreateAPIKey stores a new API key, created at the time the entry was
+// appended
+func applyCreateAPIKey(tx *fsmTx, cmd *models.Command) error {
+	if cmd.APIKey == nil {
+		return errorf(ErrValidation, "api key is nil")
+	}
+	if err := models.ValidateID(cmd.APIKey.ID); err != nil {
+		return errorf(ErrValidation, "%v", err)
+	}
+	if !models.IsValidRole(cmd.APIKey.Role) {
+		return errorf(ErrValidation, "invalid role: %s", cmd.APIKey.Role)
+	}
+	if cmd.APIKey.SecretHash == "" {
+		return errorf(ErrValidation, "api key has no secret hash")
+	}
+
+	key := *cmd.APIKey
+	key.CreatedAt = tx.appendedAt
+	key.PreviousSecretHash = ""
+	key.PreviousExpiresAt = nil
+	key.RotatedAt = nil
+	return tx.create(bucketAPIKeys, key.ID, &key)
+}
+
+// applyRotateAPIKey gives an API key a new secret. The old one stays valid
+// for the command's grace period.
+func applyRotateAPIKey(tx *fsmTx, cmd *models.Command) error {
+	if cmd.APIKey == nil || cmd.APIKey.SecretHash == "" {
+		return errorf(ErrValidation, "api key has no secret hash")
+	}
+	if cmd.GraceSeconds < 0 {
+		return errorf(ErrValidation, "grace period can't be negative")
+	}
+	key, err := getResource[models.APIKey](tx.tx, bucketAPIKeys, cmd.APIKey.ID)
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		return errorf(ErrNotFound, "api key with ID %s does not exist", cmd.APIKey.ID)
+	}
+
+	key.PreviousSecretHash = ""
+	key.PreviousExpiresAt = nil
+	if cmd.GraceSeconds > 0 {
+		expiresAt := tx.appendedAt.Add(time.Duration(cmd.GraceSeconds) * time.Second)
+		key.PreviousSecretHash = key.SecretHash
+		key.PreviousExpiresAt = &expiresAt
+	}
+	key.SecretHash = cmd.APIKey.SecretHash
+	rotatedAt := tx.appendedAt
+	key.RotatedAt = &rotatedAt
+	return tx.put(bucketAPIKeys, key.ID, key)
+}
+
+// applyDeleteAPIKey deletes an API key, which stops working at once
+func applyDeleteAPIKey(tx *fsmTx, cmd *models.Command) error {
+	data, err := tx.tx.get(bucketAPIKeys, cmd.KeyID)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return errorf(ErrNotFound, "api key with ID %s does not exist", cmd.KeyID)
+	}
+	return tx.delete(bucketAPIKeys, cmd.KeyID)
+}
+
+// applySetRoleBinding creates or replaces the role binding of a subject
+func applySetRoleBinding(tx *fsmTx, cmd *models.Command) error {
+	if cmd.RoleBinding == nil {
+		return errorf(ErrValidation, "role binding is nil")
+	}
+	if cmd.RoleBinding.Subject == "" {
+		return errorf(ErrValidation, "role binding has no subject")
+	}
+	if !models.IsValidRole(cmd.RoleBinding.Role) {
+		return errorf(ErrValidation, "invalid role: %s", cmd.RoleBinding.Role)
+	}
+	return tx.put(bucketRoleBindings, cmd.RoleBinding.Subject, cmd.RoleBinding)
+}
+
+// applyDeleteRoleBinding deletes the role binding of a subject
+func applyDeleteRoleBinding(tx *fsmTx, cmd *models.Command) error {
+	data, err := tx.tx.get(bucketRoleBindings, cmd.Subject)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return errorf(ErrNotFound, "role binding for %s does not exist", cmd.Subject)
+	}
+	return tx.delete(bucketRoleBindings, cmd.Subject)
+}
+
+// redactAPIKey drops the secret hashes from an encoded API key, so they
+// never reach the audit log
+func redactAPIKey(data []byte) []byte {
+	if len(data) == 0 {
+		return data
+	}
+	var key models.APIKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil
+	}
+	redacted, err := json.Marshal(key.Redacted())
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
+// GetAPIKey returns an API key by ID, with its secret hashes
+func (f *FSM) GetAPIKey(id string) (*models.APIKey, bool) {
+	var key *models.APIKey
+	f.view(func(tx stateTx) error {
+		var err error
+		key, err = getResource[models.APIKey](tx, bucketAPIKeys, id)
+		return err
+	})
+	return key, key != nil
+}
+
+// GetAPIKeys returns all API keys, ordered by ID
+func (f *FSM) GetAPIKeys() []*models.APIKey {
+	var keys []*models.APIKey
+	f.view(func(tx stateTx) error {
+		var err error
+		keys, err = listResources[models.APIKey](tx, bucketAPIKeys)
+		return err
+	})
+	return keys
+}
+
+// GetRoleBinding returns the role binding of a subject
+func (f *FSM) GetRoleBinding(subject string) (*models.RoleBinding, bool) {
+	var binding *models.RoleBinding
+	f.view(func(tx stateTx) error {
+		var err error
+		binding, err = getResource[models.RoleBinding](tx, bucketRoleBindings, subject)
+		return err
+	})
+	return binding, binding != nil
+}
+
+// GetRoleBindings returns all role bindings, ordered by subject
+func (f *FSM) GetRoleBindings() []*models.RoleBinding {
+	var bindings []*models.RoleBinding
+	f.view(func(tx stateTx) error {
+		var err error
+		bindings, err = listResources[models.RoleBinding](tx, bucketRoleBindings)
+		return err
+	})
+	return bindings
+}
@@ -0,0 +1,63 @@
+package raft
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/raft"
+)
+
+// ClusterStatus is the state of a node and the members of its cluster
+type ClusterStatus struct {
+	NodeID        string          `json:"node_id"`
+	State         string          `json:"state"`
+	IsLeader      bool            `json:"is_leader"`
+	LeaderAddress string          `json:"leader_address"`
+	LeaderID      string          `json:"leader_id"`
+	AppliedIndex  uint64          `json:"applied_index"`
+	LastLogIndex  uint64          `json:"last_log_index"`
+	Members       []ClusterMember `json:"members"`
+}
+
+// ClusterMember is a server in the cluster configuration
+type ClusterMember struct {
+	ID       string `json:"id"`
+	Address  string `json:"address"`
+	Suffrage string `json:"suffrage"`
+	IsLeader bool   `json:"is_leader"`
+}
+
+// LeaderID returns the ID of the current leader, empty if there is none
+func (n *Node) LeaderID() string {
+	_, id := n.raft.LeaderWithID()
+	return string(id)
+}
+
+// ClusterStatus returns the state of the node and the members of the
+// cluster as it knows them
+func (n *Node) ClusterStatus() (*ClusterStatus, error) {
+	future := n.raft.GetConfiguration()
+	if err := future.Error(); err != nil {
+		return nil, fmt.Errorf("failed to get cluster configuration: %v", err)
+	}
+
+	leaderAddr, leaderID := n.raft.LeaderWithID()
+	status := &ClusterStatus{
+		NodeID:        string(n.id),
+		State:         n.raft.State().String(),
+		IsLeader:      n.raft.State() == raft.Leader,
+		LeaderAddress: string(leaderAddr),
+		LeaderID:      string(leaderID),
+		AppliedIndex:  n.fsm.AppliedIndex(),
+		LastLogIndex:  n.raft.LastIndex(),
+		Members:       make([]ClusterMember, 0, len(future.Configuration().Servers)),
+	}
+	for _, server := range future.Configuration().Servers {
+		status.Members = append(status.Members, ClusterMember{
+			ID:       string(server.ID),
+			Address:  string(server.Address),
+			Suffrage: server.Suffrage.String(),
+			IsLeader: server.ID == leaderID,
+		})
+	}
+	return status, nil
+}
@@ -0,0 +1,79 @@
+package raft
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// digestHistorySize is the number of per-index digests kept for
+// cross-replica comparison
+const digestHistorySize = 1024
+
+// stateDigest is an order-independent hash of the FSM state. Each resource
+// contributes the SHA-256 of its bucket, ID and stored encoding, and the
+// contributions are XORed together, so a single resource can be added or
+// removed without rehashing everything else.
+type stateDigest [sha256.Size]byte
+
+// toggle adds a resource to the digest, or removes it if it is already in
+func (d *stateDigest) toggle(bucket, id string, data []byte) {
+	h := sha256.New()
+	h.Write([]byte(bucket))
+	h.Write([]byte{0})
+	h.Write([]byte(id))
+	h.Write([]byte{0})
+	h.Write(data)
+
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	for i := range d {
+		d[i] ^= sum[i]
+	}
+}
+
+// String returns the digest in hex
+func (d stateDigest) String() string {
+	return hex.EncodeToString(d[:])
+}
+
+// digestEntry records the digest right after a log index was applied
+type digestEntry struct {
+	index  uint64
+	digest stateDigest
+}
+
+// recordDigest marks index as applied and remembers the digest at it.
+// The caller must hold the write lock.
+func (f *FSM) recordDigest(index uint64) {
+	f.appliedIndex = index
+	if len(f.digests) == digestHistorySize {
+		copy(f.digests, f.digests[1:])
+		f.digests = f.digests[:digestHistorySize-1]
+	}
+	f.digests = append(f.digests, digestEntry{index: index, digest: f.digest})
+}
+
+// Digest returns the last applied log index and the state digest at it
+func (f *FSM) Digest() (uint64, string) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.appliedIndex, f.digest.String()
+}
+
+// DigestAt returns the state digest right after index was applied, if it is
+// still in the digest history
+func (f *FSM) DigestAt(index uint64) (string, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for i := len(f.digests) - 1; i >= 0; i-- {
+		if f.digests[i].index == index {
+			return f.digests[i].digest.String(), true
+		}
+		if f.digests[i].index < index {
+			break
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,170 @@
+package raft
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
+	metrics "github.com/hashicorp/go-metrics/compat"
+	"github.com/hashicorp/raft"
+)
+
+// DigestResponse is the body served by the digest admin endpoint
+type DigestResponse struct {
+	AppliedIndex uint64 `json:"applied_index"`
+	Digest       string `json:"digest"`
+}
+
+// errDigestUnavailable is returned when a member no longer retains the
+// digest at the requested index
+var errDigestUnavailable = fmt.Errorf("digest not available at requested index")
+
+// fetchDigest asks the node at httpAddr for its state digest. An index of 0
+// asks for the digest at its last applied index.
+func (t *Transport) fetchDigest(httpAddr string, index uint64) (*DigestResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	url := httpAddr + "/admin/digest"
+	if index != 0 {
+		url = fmt.Sprintf("%s?index=%d", url, index)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	t.authenticate(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errDigestUnavailable
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received non-success response: %d", resp.StatusCode)
+	}
+
+	var digest DigestResponse
+	if err := json.NewDecoder(resp.Body).Decode(&digest); err != nil {
+		return nil, err
+	}
+	return &digest, nil
+}
+
+// CheckDigests compares the state digest of every cluster member with the
+// leader's at the highest index all reachable members have applied. It only
+// does anything on the leader. Divergent members are logged and counted in
+// the raft3d.fsm.digest_mismatch metric, and reported in the returned error.
+func (t *Transport) CheckDigests() error {
+	if !t.node.Leader() {
+		return nil
+	}
+
+	future := t.node.raft.GetConfiguration()
+	if err := future.Error(); err != nil {
+		return fmt.Errorf("failed to get cluster configuration: %v", err)
+	}
+
+	// Find the highest index every reachable member has applied
+	commonIndex, _ := t.node.fsm.Digest()
+	members := make(map[raft.ServerID]string)
+	for _, server := range future.Configuration().Servers {
+		if server.Address == t.node.transport.LocalAddr() {
+			continue
+		}
+
+		httpAddr, err := httpAddrForRaftAddr(string(server.Address))
+		if err != nil {
+			log.Printf("Skipping digest check of %s: %v", server.ID, err)
+			continue
+		}
+		digest, err := t.fetchDigest(httpAddr, 0)
+		if err != nil {
+			log.Printf("Skipping digest check of %s: %v", server.ID, err)
+			continue
+		}
+
+		members[server.ID] = httpAddr
+		if digest.AppliedIndex < commonIndex {
+			commonIndex = digest.AppliedIndex
+		}
+	}
+	if len(members) == 0 || commonIndex == 0 {
+		return nil
+	}
+
+	expected, ok := t.node.fsm.DigestAt(commonIndex)
+	if !ok {
+		return fmt.Errorf("leader no longer retains the digest at index %d", commonIndex)
+	}
+
+	// Compare everyone at that index
+	var diverged []raft.ServerID
+	var details []string
+	for id, httpAddr := range members {
+		digest, err := t.fetchDigest(httpAddr, commonIndex)
+		if err != nil {
+			log.Printf("Skipping digest check of %s at index %d: %v", id, commonIndex, err)
+			continue
+		}
+		if digest.Digest != expected {
+			log.Printf("FSM divergence detected: %s has digest %s at index %d, leader has %s",
+				id, digest.Digest, commonIndex, expected)
+			metrics.IncrCounter([]string{"raft3d", "fsm", "digest_mismatch"}, 1)
+			diverged = append(diverged, id)
+			details = append(details, fmt.Sprintf("%s has %s", id, digest.Digest))
+		}
+	}
+	metrics.SetGauge([]string{"raft3d", "fsm", "digest_check_index"}, float32(commonIndex))
+
+	if len(diverged) > 0 {
+		sort.Strings(details)
+		t.reportDivergence(fmt.Sprintf("members diverged from the leader at index %d: leader has %s, %s",
+			commonIndex, expected, strings.Join(details, ", ")))
+		return fmt.Errorf("members %v diverged from the leader at index %d", diverged, commonIndex)
+	}
+	return nil
+}
+
+// divergenceActor is the actor of divergence reports in the audit log
+const divergenceActor = "digest-check"
+
+// reportDivergence records a divergence in the replicated audit log, where
+// clients can follow it with the audit query's cursor
+func (t *Transport) reportDivergence(reason string) {
+	err := t.node.Apply(&models.Command{
+		Type:   models.ReportDivergence,
+		Reason: reason,
+		Actor:  divergenceActor,
+	})
+	if err != nil {
+		log.Printf("Failed to record divergence in the audit log: %v", err)
+	}
+}
+
+// RunDigestChecks runs CheckDigests every interval until stop is closed
+func (t *Transport) RunDigestChecks(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := t.CheckDigests(); err != nil {
+				log.Printf("Digest check failed: %v", err)
+			}
+		}
+	}
+}
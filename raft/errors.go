@@ -0,0 +1,49 @@
+package raft
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kinds of errors commands fail with. Callers tell them apart with
+// errors.Is; conflicts have their own errors next to the code returning
+// them, such as ErrAlreadyExists, ErrInUse, ErrJobNotFinished and
+// ErrVersionConflict.
+var (
+	// ErrNotFound is returned when the resource a command targets doesn't
+	// exist
+	ErrNotFound = errors.New("not found")
+	// ErrInsufficientFilament is returned when a filament doesn't have the
+	// weight a command needs of it
+	ErrInsufficientFilament = errors.New("insufficient filament")
+	// ErrInvalidTransition is returned when a print job can't move from its
+	// status to the requested one
+	ErrInvalidTransition = errors.New("invalid status transition")
+	// ErrValidation is returned for commands that are malformed or make no
+	// sense, whatever the state
+	ErrValidation = errors.New("invalid command")
+	// ErrUnavailable is returned when a command can't be committed to the
+	// log, such as when this node isn't the leader or lost leadership
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// kindError is an error of one of the kinds above. Its message is that of
+// the underlying error alone.
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+// errorf formats an error of the given kind. Like fmt.Errorf, it wraps an
+// error given with %w.
+func errorf(kind error, format string, args ...interface{}) error {
+	return &kindError{kind: kind, err: fmt.Errorf(format, args...)}
+}
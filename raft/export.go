@@ -0,0 +1,88 @@
+package raft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+// StateExport is a read of every printer, filament and print job as of one
+// applied index. Applies carry on while it is read.
+type StateExport struct {
+	// Index is the applied index the export reflects
+	Index uint64
+
+	view    stateTx
+	release func()
+}
+
+// Export opens a consistent read of the current state. The caller has to
+// Close it.
+func (f *FSM) Export() (*StateExport, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	view, release, err := f.state.readView()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state for export: %v", err)
+	}
+	return &StateExport{Index: f.appliedIndex, view: view, release: release}, nil
+}
+
+// ForEach calls fn with the JSON of every resource of a type, in ID order,
+// stopping at the first error fn returns. The data is only valid during
+// the call.
+func (e *StateExport) ForEach(resourceType string, fn func(data json.RawMessage) error) error {
+	if !containsBucket(resourceBuckets, resourceType) {
+		return errorf(ErrValidation, "unknown resource type: %s", resourceType)
+	}
+	return e.view.forEach(resourceType, func(id string, data []byte) error {
+		return fn(data)
+	})
+}
+
+// Close releases the read of the state
+func (e *StateExport) Close() {
+	e.release()
+}
+
+// errDryRun makes the backend discard the writes of a dry run
+var errDryRun = errors.New("dry run")
+
+// DryRun applies commands in order to the current state as Apply would,
+// each seeing the effects of the ones before it that succeeded, and then
+// discards everything. It returns the error of each command, nil for those
+// that would succeed. Commands proposed afterwards can still fail if the
+// state changes in between.
+func (f *FSM) DryRun(cmds []*models.Command) ([]error, error) {
+	// The transaction is never committed and applying reads the indexes
+	// without changing them, so keeping Apply out is enough
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	errs := make([]error, len(cmds))
+	err := f.state.update(func(stx stateTx) error {
+		tx := newFSMTx(stx, f.appliedIndex+1, time.Now())
+		for i, cmd := range cmds {
+			// Applying fills in and changes parts of a command, which
+			// belong to the caller
+			data, err := json.Marshal(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to copy command: %v", err)
+			}
+			var copied models.Command
+			if err := json.Unmarshal(data, &copied); err != nil {
+				return fmt.Errorf("failed to copy command: %v", err)
+			}
+			_, errs[i] = f.applyCommand(tx, &copied)
+		}
+		return errDryRun
+	})
+	if !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return errs, nil
+}
@@ -0,0 +1,76 @@
+package raft
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+func TestDryRunAlongsideApplies(t *testing.T) {
+	for _, b := range testBackends {
+		t.Run(b.name, func(t *testing.T) {
+			f := newTestFSMOn(t, b.open(t))
+			f.mustApply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: "p1", Company: "Prusa", Model: "MK4"}})
+			f.mustApply(&models.Command{Type: models.AddFilament, Filament: &models.Filament{
+				ID: "f1", Type: "PLA", Color: "red", TotalWeightInGrams: 1000, RemainingWeightInGrams: 1000,
+			}})
+
+			// The second job only fits if the first one isn't there, and
+			// the third only if the first one is
+			cmds := []*models.Command{
+				{Type: models.AddPrintJob, PrintJob: &models.PrintJob{ID: "dry1", PrinterID: "p1", FilamentID: "f1", Filepath: "/prints/part.gcode", PrintWeightInGrams: 600}},
+				{Type: models.AddPrintJob, PrintJob: &models.PrintJob{ID: "dry2", PrinterID: "p1", FilamentID: "f1", Filepath: "/prints/part.gcode", PrintWeightInGrams: 600}},
+				{Type: models.AddPrintJob, PrintJob: &models.PrintJob{ID: "dry3", PrinterID: "p1", FilamentID: "f2", Filepath: "/prints/part.gcode", PrintWeightInGrams: 10}},
+			}
+
+			var wg sync.WaitGroup
+			errs := make(chan error, 1)
+			done := make(chan struct{})
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					select {
+					case <-done:
+						return
+					default:
+					}
+					results, err := f.DryRun(cmds)
+					if err != nil {
+						errs <- err
+						return
+					}
+					if results[0] != nil || !errors.Is(results[1], ErrInsufficientFilament) || !errors.Is(results[2], ErrValidation) {
+						errs <- fmt.Errorf("dry run returned %v", results)
+						return
+					}
+				}
+			}()
+
+			for i := 0; i < 50; i++ {
+				f.mustApply(&models.Command{Type: models.UpdatePrinter, PrinterID: "p1", Patch: []byte(fmt.Sprintf(`{"model":"MK%d"}`, i))})
+			}
+			close(done)
+			wg.Wait()
+			select {
+			case err := <-errs:
+				t.Fatal(err)
+			default:
+			}
+
+			// Nothing of the dry runs is left behind
+			for _, cmd := range cmds {
+				if _, ok := f.GetPrintJob(cmd.PrintJob.ID); ok {
+					t.Errorf("dry run left job %s behind", cmd.PrintJob.ID)
+				}
+			}
+			if got := f.ReservedGrams("f1"); got != 0 {
+				t.Errorf("filament f1 has %d g reserved, want 0 g", got)
+			}
+			checkJobIndex(t, f.FSM)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package raft
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+// applyUpdateFilament patches an existing filament. The remaining weight
+// only changes through AdjustFilamentWeight, which keeps track of why.
+func applyUpdateFilament(tx *fsmTx, cmd *models.Command) error {
+	filament, err := getResource[models.Filament](tx.tx, bucketFilaments, cmd.FilamentID)
+	if err != nil {
+		return err
+	}
+	if filament == nil {
+		return errorf(ErrNotFound, "filament with ID %s does not exist", cmd.FilamentID)
+	}
+
+	patched, err := patchResource(filament, cmd.Patch, "remaining_weight_in_grams")
+	if err != nil {
+		return err
+	}
+	if !models.IsValidFilamentType(patched.Type) {
+		return errorf(ErrValidation, "invalid filament type")
+	}
+	return tx.put(bucketFilaments, patched.ID, patched)
+}
+
+// applyUpsertFilament creates or replaces a filament. A replaced filament
+// keeps its remaining weight, which only adjustments change.
+func applyUpsertFilament(tx *fsmTx, cmd *models.Command) error {
+	existing, err := getResource[models.Filament](tx.tx, bucketFilaments, cmd.Filament.ID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		cmd.Filament.RemainingWeightInGrams = existing.RemainingWeightInGrams
+	}
+	return tx.put(bucketFilaments, cmd.Filament.ID, cmd.Filament)
+}
+
+// applyDeleteFilament deletes a filament. While Queued or Running jobs
+// reserve it the delete fails, unless the command cascades and cancels them
+// along with it.
+func (f *FSM) applyDeleteFilament(tx *fsmTx, cmd *models.Command) error {
+	filament, err := getResource[models.Filament](tx.tx, bucketFilaments, cmd.FilamentID)
+	if err != nil {
+		return err
+	}
+	if filament == nil {
+		return errorf(ErrNotFound, "filament with ID %s does not exist", cmd.FilamentID)
+	}
+
+	active, err := f.txJobs(tx, f.jobIndex.byFilament[filament.ID], func(job *models.PrintJob) bool {
+		return job.FilamentID == filament.ID && isReserving(job.Status)
+	})
+	if err != nil {
+		return err
+	}
+	if err := f.cascade(tx, cmd, active); err != nil {
+		return fmt.Errorf("filament with ID %s: %w", filament.ID, err)
+	}
+
+	return tx.delete(bucketFilaments, filament.ID)
+}
+
+// applyAdjustFilamentWeight changes the remaining weight of a filament by
+// a delta, such as after weighing the spool. It can't take the weight below
+// what Queued and Running jobs have reserved.
+func (f *FSM) applyAdjustFilamentWeight(tx *fsmTx, cmd *models.Command) error {
+	filament, err := getResource[models.Filament](tx.tx, bucketFilaments, cmd.FilamentID)
+	if err != nil {
+		return err
+	}
+	if filament == nil {
+		return errorf(ErrNotFound, "filament with ID %s does not exist", cmd.FilamentID)
+	}
+
+	if cmd.DeltaGrams == 0 {
+		return errorf(ErrValidation, "delta must not be 0")
+	}
+	if cmd.Reason == "" {
+		return errorf(ErrValidation, "reason is required")
+	}
+
+	// Adding weight is always allowed, even if it doesn't make up for all
+	// that is missing
+	remaining := filament.RemainingWeightInGrams + cmd.DeltaGrams
+	if cmd.DeltaGrams < 0 {
+		if remaining < 0 {
+			return errorf(ErrInsufficientFilament, "remaining weight can't go below 0 g, it is %d g", filament.RemainingWeightInGrams)
+		}
+		if reserved := f.reservedGrams(tx, filament.ID); remaining < reserved {
+			return errorf(ErrInsufficientFilament, "remaining weight can't go below the %d g reserved by print jobs", reserved)
+		}
+	}
+
+	filament.RemainingWeightInGrams = remaining
+	return tx.put(bucketFilaments, filament.ID, filament)
+}
+
+// FilamentAdjustment is a change made to a filament's remaining weight by
+// AdjustFilamentWeight
+type FilamentAdjustment struct {
+	Index                  uint64    `json:"index"`
+	Time                   time.Time `json:"time"`
+	Actor                  string    `json:"actor"`
+	DeltaGrams             int       `json:"delta_grams"`
+	RemainingWeightInGrams int       `json:"remaining_weight_in_grams"`
+	Reason                 string    `json:"reason,omitempty"`
+}
+
+// GetFilamentAdjustments returns up to limit weight adjustments of a
+// filament after index afterIndex, oldest first, as kept in the audit log.
+// The second return value is the index to continue from, or 0 when there
+// are no more.
+func (f *FSM) GetFilamentAdjustments(filamentID string, afterIndex uint64, limit int) ([]*FilamentAdjustment, uint64, error) {
+	filter := AuditFilter{
+		Command:      models.AdjustFilamentWeight,
+		ResourceType: bucketFilaments,
+		ResourceID:   filamentID,
+		Result:       AuditResultOK,
+	}
+	records, next, err := f.GetAuditRecords(filter, afterIndex, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	adjustments := make([]*FilamentAdjustment, 0, len(records))
+	for _, record := range records {
+		adjustment := &FilamentAdjustment{
+			Index:  record.Index,
+			Time:   record.Time,
+			Actor:  record.Actor,
+			Reason: record.Reason,
+		}
+		for _, c := range record.Changes {
+			var before, after models.Filament
+			if c.Type != bucketFilaments || c.ID != filamentID ||
+				json.Unmarshal(c.Before, &before) != nil || json.Unmarshal(c.After, &after) != nil {
+				continue
+			}
+			adjustment.DeltaGrams = after.RemainingWeightInGrams - before.RemainingWeightInGrams
+			adjustment.RemainingWeightInGrams = after.RemainingWeightInGrams
+		}
+		adjustments = append(adjustments, adjustment)
+	}
+	return adjustments, next, nil
+}
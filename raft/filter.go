@@ -0,0 +1,530 @@
+package raft
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+// Kinds of filterable fields
+const (
+	fieldString = "string"
+	fieldNumber = "number"
+	fieldTime   = "time"
+)
+
+// filterField describes a field resources can be filtered on
+type filterField struct {
+	kind string
+	// valid, if set, checks a value given for the field
+	valid func(s string) bool
+	// fold compares values without regard to case
+	fold bool
+}
+
+// filterSchemas lists the fields each resource type can be filtered on
+var filterSchemas = map[string]map[string]filterField{
+	bucketPrinters: {
+		"id":            {kind: fieldString},
+		"company":       {kind: fieldString},
+		"model":         {kind: fieldString},
+		"version":       {kind: fieldNumber},
+		"created_index": {kind: fieldNumber},
+	},
+	bucketFilaments: {
+		"id":                        {kind: fieldString},
+		"type":                      {kind: fieldString, valid: models.IsValidFilamentType, fold: true},
+		"color":                     {kind: fieldString},
+		"total_weight_in_grams":     {kind: fieldNumber},
+		"remaining_weight_in_grams": {kind: fieldNumber},
+		"version":                   {kind: fieldNumber},
+		"created_index":             {kind: fieldNumber},
+	},
+	bucketPrintJobs: {
+		"id":                    {kind: fieldString},
+		"printer_id":            {kind: fieldString},
+		"filament_id":           {kind: fieldString},
+		"filepath":              {kind: fieldString},
+		"status":                {kind: fieldString, valid: models.IsValidPrintJobStatus},
+		"print_weight_in_grams": {kind: fieldNumber},
+		"finished_at":           {kind: fieldTime},
+		"version":               {kind: fieldNumber},
+		"created_index":         {kind: fieldNumber},
+	},
+}
+
+// FilterFields returns the fields a resource type can be filtered on, in
+// alphabetical order
+func FilterFields(resourceType string) []string {
+	fields := make([]string, 0, len(filterSchemas[resourceType]))
+	for name := range filterSchemas[resourceType] {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// filterExpr is a node of a parsed filter
+type filterExpr interface {
+	match(fields map[string]interface{}) bool
+}
+
+type andExpr []filterExpr
+
+func (e andExpr) match(fields map[string]interface{}) bool {
+	for _, sub := range e {
+		if !sub.match(fields) {
+			return false
+		}
+	}
+	return true
+}
+
+type orExpr []filterExpr
+
+func (e orExpr) match(fields map[string]interface{}) bool {
+	for _, sub := range e {
+		if sub.match(fields) {
+			return true
+		}
+	}
+	return false
+}
+
+type notExpr struct {
+	sub filterExpr
+}
+
+func (e notExpr) match(fields map[string]interface{}) bool {
+	return !e.sub.match(fields)
+}
+
+// comparison compares a field with a value. A field the resource doesn't
+// have, like finished_at of an unfinished job, only matches !=.
+type comparison struct {
+	field string
+	kind  string
+	fold  bool
+	op    string
+	// Exactly one of these holds the value, depending on kind
+	str    string
+	number float64
+	time   time.Time
+}
+
+func (e *comparison) match(fields map[string]interface{}) bool {
+	var cmp int
+	switch v := fields[e.field].(type) {
+	case string:
+		switch e.kind {
+		case fieldString:
+			if e.fold {
+				v = strings.ToUpper(v)
+			}
+			cmp = strings.Compare(v, e.str)
+		case fieldTime:
+			t, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return e.op == "!="
+			}
+			cmp = t.Compare(e.time)
+		default:
+			return e.op == "!="
+		}
+	case float64:
+		if e.kind != fieldNumber {
+			return e.op == "!="
+		}
+		switch {
+		case v < e.number:
+			cmp = -1
+		case v > e.number:
+			cmp = 1
+		}
+	default:
+		return e.op == "!="
+	}
+
+	switch e.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+// Filter selects resources of one type. The zero value of a *Filter, nil,
+// matches everything.
+type Filter struct {
+	resourceType string
+	expr         andExpr
+}
+
+// ParseFilter parses a filter on a resource type. Comparisons take the form
+// field op value, with op one of = != < <= > >=, and combine with and, or,
+// not and parentheses. Values with spaces or parentheses are double-quoted,
+// and times are given in RFC 3339.
+func ParseFilter(resourceType, s string) (*Filter, error) {
+	schema, ok := filterSchemas[resourceType]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource type: %s", resourceType)
+	}
+
+	f := &Filter{resourceType: resourceType, expr: andExpr{}}
+	if strings.TrimSpace(s) == "" {
+		return f, nil
+	}
+	p := &filterParser{schema: schema, resourceType: resourceType, s: s}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	switch tok := p.next(); tok.kind {
+	case tokenEOF:
+	case tokenInvalid:
+		return nil, p.errorf(tok.pos, "%s", tok.text)
+	default:
+		return nil, p.errorf(tok.pos, "unexpected %q, expected \"and\" or \"or\"", tok.text)
+	}
+	f.expr = append(f.expr, expr)
+	return f, nil
+}
+
+// Equal narrows the filter down to resources whose field equals value
+func (f *Filter) Equal(field, value string) error {
+	c, err := newComparison(filterSchemas[f.resourceType], f.resourceType, field, "=", value)
+	if err != nil {
+		return err
+	}
+	f.expr = append(f.expr, c)
+	return nil
+}
+
+// Match reports whether a resource passes the filter
+func (f *Filter) Match(resource interface{}) bool {
+	if f == nil || len(f.expr) == 0 {
+		return true
+	}
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return false
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return false
+	}
+	return f.expr.match(fields)
+}
+
+// equalities returns the values that fields must equal for the whole filter
+// to match, as far as the top level of the filter tells
+func (f *Filter) equalities() map[string]string {
+	eq := make(map[string]string)
+	if f == nil {
+		return eq
+	}
+	var walk func(e andExpr)
+	walk = func(e andExpr) {
+		for _, sub := range e {
+			switch sub := sub.(type) {
+			case andExpr:
+				walk(sub)
+			case *comparison:
+				if sub.op == "=" && sub.kind == fieldString {
+					eq[sub.field] = sub.str
+				}
+			}
+		}
+	}
+	walk(f.expr)
+	return eq
+}
+
+// FilterResources returns the resources that pass filter
+func FilterResources[T any](resources []*T, filter *Filter) []*T {
+	matched := make([]*T, 0, len(resources))
+	for _, r := range resources {
+		if filter.Match(r) {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+// QueryPrinters returns the printers that pass filter
+func (f *FSM) QueryPrinters(filter *Filter) []*models.Printer {
+	return FilterResources(f.GetPrinters(), filter)
+}
+
+// QueryFilaments returns the filaments that pass filter
+func (f *FSM) QueryFilaments(filter *Filter) []*models.Filament {
+	return FilterResources(f.GetFilaments(), filter)
+}
+
+// newComparison checks a comparison against the schema and parses its value
+func newComparison(schema map[string]filterField, resourceType, field, op, value string) (*comparison, error) {
+	def, ok := schema[field]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q for %s, expected one of %s",
+			field, resourceType, strings.Join(FilterFields(resourceType), ", "))
+	}
+	c := &comparison{field: field, kind: def.kind, op: op}
+	switch def.kind {
+	case fieldString:
+		if def.valid != nil && !def.valid(value) {
+			return nil, fmt.Errorf("invalid %s %q", field, value)
+		}
+		c.str = value
+		if def.fold {
+			c.fold = true
+			c.str = strings.ToUpper(value)
+		}
+	case fieldNumber:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s expects a number, got %q", field, value)
+		}
+		c.number = n
+	case fieldTime:
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s expects an RFC 3339 time, got %q", field, value)
+		}
+		c.time = t
+	}
+	return c, nil
+}
+
+// Kinds of filter tokens
+const (
+	tokenEOF = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenLParen
+	tokenRParen
+	// tokenInvalid holds what is wrong with the input as its text
+	tokenInvalid
+)
+
+type filterToken struct {
+	kind int
+	text string
+	pos  int
+}
+
+// filterParser is a recursive descent parser over the filter grammar:
+//
+//	or         = and { "or" and }
+//	and        = unary { "and" unary }
+//	unary      = "not" unary | "(" or ")" | comparison
+//	comparison = field op value
+type filterParser struct {
+	schema       map[string]filterField
+	resourceType string
+	s            string
+	pos          int
+	peeked       *filterToken
+}
+
+func (p *filterParser) errorf(pos int, format string, args ...interface{}) error {
+	return fmt.Errorf("invalid filter at position %d: %s", pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *filterParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	or := orExpr{left}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, right)
+	}
+	if len(or) == 1 {
+		return left, nil
+	}
+	return or, nil
+}
+
+func (p *filterParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	and := andExpr{left}
+	for p.keyword("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, right)
+	}
+	if len(and) == 1 {
+		return left, nil
+	}
+	return and, nil
+}
+
+func (p *filterParser) parseUnary() (filterExpr, error) {
+	if p.keyword("not") {
+		sub, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{sub}, nil
+	}
+
+	tok := p.next()
+	switch tok.kind {
+	case tokenInvalid:
+		return nil, p.errorf(tok.pos, "%s", tok.text)
+
+	case tokenLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, p.errorf(closing.pos, "expected )")
+		}
+		return expr, nil
+
+	case tokenWord:
+		op := p.next()
+		if op.kind == tokenInvalid {
+			return nil, p.errorf(op.pos, "%s", op.text)
+		}
+		if op.kind != tokenOp {
+			return nil, p.errorf(op.pos, "expected one of = != < <= > >= after %s", tok.text)
+		}
+		value := p.nextValue()
+		if value.kind == tokenInvalid {
+			return nil, p.errorf(value.pos, "%s", value.text)
+		}
+		if value.kind != tokenWord && value.kind != tokenString {
+			return nil, p.errorf(value.pos, "expected a value after %s %s", tok.text, op.text)
+		}
+		c, err := newComparison(p.schema, p.resourceType, tok.text, op.text, value.text)
+		if err != nil {
+			// Point at the value unless the field is what is wrong
+			pos := value.pos
+			if _, ok := p.schema[tok.text]; !ok {
+				pos = tok.pos
+			}
+			return nil, p.errorf(pos, "%v", err)
+		}
+		return c, nil
+
+	case tokenEOF:
+		return nil, p.errorf(tok.pos, "unexpected end, expected a comparison")
+	default:
+		return nil, p.errorf(tok.pos, "unexpected %q, expected a comparison", tok.text)
+	}
+}
+
+// keyword consumes the next token if it is the keyword kw
+func (p *filterParser) keyword(kw string) bool {
+	tok := p.peek()
+	if tok.kind == tokenWord && strings.EqualFold(tok.text, kw) {
+		p.peeked = nil
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) peek() filterToken {
+	if p.peeked == nil {
+		tok := p.scan(false)
+		p.peeked = &tok
+	}
+	return *p.peeked
+}
+
+func (p *filterParser) next() filterToken {
+	tok := p.peek()
+	p.peeked = nil
+	return tok
+}
+
+// nextValue reads the token after an operator, where a bare word may hold
+// characters like : and - that appear in times
+func (p *filterParser) nextValue() filterToken {
+	if p.peeked != nil {
+		return p.next()
+	}
+	return p.scan(true)
+}
+
+// scan reads the next token from the input
+func (p *filterParser) scan(value bool) filterToken {
+	for p.pos < len(p.s) && unicode.IsSpace(rune(p.s[p.pos])) {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos == len(p.s) {
+		return filterToken{kind: tokenEOF, pos: start}
+	}
+
+	switch ch := p.s[p.pos]; {
+	case ch == '(':
+		p.pos++
+		return filterToken{kind: tokenLParen, text: "(", pos: start}
+	case ch == ')':
+		p.pos++
+		return filterToken{kind: tokenRParen, text: ")", pos: start}
+	case ch == '"':
+		var b strings.Builder
+		for p.pos++; p.pos < len(p.s); p.pos++ {
+			switch c := p.s[p.pos]; {
+			case c == '\\' && p.pos+1 < len(p.s):
+				p.pos++
+				b.WriteByte(p.s[p.pos])
+			case c == '"':
+				p.pos++
+				return filterToken{kind: tokenString, text: b.String(), pos: start}
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return filterToken{kind: tokenInvalid, text: "unterminated string", pos: start}
+	case strings.ContainsRune("=!<>", rune(ch)):
+		for p.pos < len(p.s) && strings.ContainsRune("=!<>", rune(p.s[p.pos])) {
+			p.pos++
+		}
+		text := p.s[start:p.pos]
+		switch text {
+		case "=", "!=", "<", "<=", ">", ">=":
+			return filterToken{kind: tokenOp, text: text, pos: start}
+		}
+		return filterToken{kind: tokenInvalid, text: fmt.Sprintf("unknown operator %s", text), pos: start}
+	}
+
+	for p.pos < len(p.s) {
+		ch := p.s[p.pos]
+		if unicode.IsSpace(rune(ch)) || ch == '(' || ch == ')' || ch == '"' ||
+			(!value && strings.ContainsRune("=!<>", rune(ch))) {
+			break
+		}
+		p.pos++
+	}
+	return filterToken{kind: tokenWord, text: p.s[start:p.pos], pos: start}
+}
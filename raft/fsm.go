@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"sync"
 
 	"github.com/devadigapratham/raft3d/api/models"
@@ -15,18 +16,160 @@ type FSM struct {
 	mu sync.RWMutex
 
 	// Our application state
-	printers  map[string]*models.Printer
-	filaments map[string]*models.Filament
-	printJobs map[string]*models.PrintJob
+	state stateBackend
+
+	// Secondary indexes over print jobs
+	jobIndex *jobIndex
+
+	// snapshotCompression is the compression used for new snapshots
+	snapshotCompression string
+
+	// historyRetention bounds the history, historyFloor is the oldest
+	// index it can rebuild the state at
+	historyRetention HistoryRetention
+	historyFloor     uint64
+
+	// auditRetention bounds the audit log
+	auditRetention AuditRetention
+
+	// archive receives purged and deleted print jobs, if set
+	archive *jobArchive
+
+	// invariantChecks selects when the invariants are checked automatically
+	invariantChecks InvariantChecks
+
+	// updated is closed and replaced whenever resources change
+	updated chan struct{}
+
+	// Digest of the state above, for detecting divergence between replicas
+	appliedIndex uint64
+	digest       stateDigest
+	digests      []digestEntry
 }
 
-// NewFSM creates a new Finite State Machine for the Raft cluster
+// NewFSM creates a new Finite State Machine for the Raft cluster, keeping
+// its state in memory
 func NewFSM() *FSM {
-	return &FSM{
-		printers:  make(map[string]*models.Printer),
-		filaments: make(map[string]*models.Filament),
-		printJobs: make(map[string]*models.PrintJob),
+	f, err := newFSM(newMemoryBackend())
+	if err != nil {
+		// An empty in-memory backend can't fail to load
+		panic(err)
+	}
+	return f
+}
+
+// NewBoltFSM creates a new Finite State Machine keeping its state in the
+// BoltDB file at path, picking up any state already stored there
+func NewBoltFSM(path string) (*FSM, error) {
+	state, err := newBoltBackend(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open FSM state: %v", err)
+	}
+
+	f, err := newFSM(state)
+	if err != nil {
+		state.close()
+		return nil, err
+	}
+	return f, nil
+}
+
+func newFSM(state stateBackend) (*FSM, error) {
+	f := &FSM{
+		state:               state,
+		jobIndex:            newJobIndex(),
+		snapshotCompression: SnapshotCompressionNone,
+		historyRetention: HistoryRetention{
+			Indexes: DefaultHistoryRetainIndexes,
+		},
+		auditRetention: AuditRetention{
+			Indexes: DefaultAuditRetainIndexes,
+		},
+		updated: make(chan struct{}),
+	}
+	if err := state.update(indexResourceHistory); err != nil {
+		return nil, fmt.Errorf("failed to index history: %v", err)
+	}
+	if err := f.rebuild(); err != nil {
+		return nil, err
 	}
+	return f, nil
+}
+
+// rebuild reloads the applied index, history floor, indexes and digest from
+// the backend.
+// The caller must hold the write lock unless the FSM isn't shared yet.
+func (f *FSM) rebuild() error {
+	return f.state.view(func(tx stateTx) error {
+		appliedIndex, err := getAppliedIndex(tx)
+		if err != nil {
+			return fmt.Errorf("failed to read applied index: %v", err)
+		}
+		f.appliedIndex = appliedIndex
+
+		// State written without history can't be rolled back at all
+		floor, ok, err := getHistoryFloor(tx)
+		if err != nil {
+			return fmt.Errorf("failed to read history floor: %v", err)
+		}
+		if !ok {
+			floor = appliedIndex
+		}
+		f.historyFloor = floor
+
+		jobs, err := listResources[models.PrintJob](tx, bucketPrintJobs)
+		if err != nil {
+			return err
+		}
+		f.jobIndex.rebuild(jobs)
+
+		f.digest = stateDigest{}
+		for _, bucket := range digestBuckets {
+			err := tx.forEach(bucket, func(id string, data []byte) error {
+				f.digest.toggle(bucket, id, data)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		f.digests = nil
+		f.recordDigest(f.appliedIndex)
+		return nil
+	})
+}
+
+// SetSnapshotCompression sets the compression used for new snapshots. Any
+// supported compression can be restored regardless of this setting.
+func (f *FSM) SetSnapshotCompression(compression string) error {
+	if _, ok := snapshotCompressions[compression]; !ok {
+		return fmt.Errorf("unknown snapshot compression: %s", compression)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.snapshotCompression = compression
+	return nil
+}
+
+// AppliedIndex returns the index of the last applied log entry
+func (f *FSM) AppliedIndex() uint64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.appliedIndex
+}
+
+// Close releases the FSM's state backend and job archive
+func (f *FSM) Close() error {
+	if f.archive != nil {
+		if err := f.archive.close(); err != nil {
+			f.state.close()
+			return err
+		}
+	}
+	return f.state.close()
 }
 
 // Apply applies a Raft log entry to the FSM
@@ -34,92 +177,314 @@ func (f *FSM) Apply(log *raft.Log) interface{} {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
+	// A persistent backend already holds the effects of entries applied
+	// before a restart
+	if log.Index <= f.appliedIndex {
+		return nil
+	}
+	defer f.recordDigest(log.Index)
+
 	// Unmarshal the command
 	var cmd models.Command
 	if err := json.Unmarshal(log.Data, &cmd); err != nil {
-		return fmt.Errorf("failed to unmarshal command: %v", err)
+		err = fmt.Errorf("failed to unmarshal command: %v", err)
+		f.skip(newAuditRecord(log.Index, log.AppendedAt, &cmd, nil, err))
+		return err
+	}
+
+	// Process the command in a single transaction, so a failing command
+	// leaves no trace
+	var tx *fsmTx
+	var result interface{}
+	var historyFloor uint64
+	err := f.state.update(func(stx stateTx) error {
+		tx = newFSMTx(stx, log.Index, log.AppendedAt)
+		var err error
+		if result, err = f.applyCommand(tx, &cmd); err != nil {
+			return err
+		}
+
+		historyFloor, err = f.recordHistory(stx, log.Index, log.AppendedAt, cmd.Type, tx.changes)
+		if err != nil {
+			return fmt.Errorf("failed to record history: %v", err)
+		}
+		if err := f.recordAudit(stx, newAuditRecord(log.Index, log.AppendedAt, &cmd, tx.changes, nil)); err != nil {
+			return fmt.Errorf("failed to record audit: %v", err)
+		}
+		return putAppliedIndex(stx, log.Index)
+	})
+	if err != nil {
+		f.skip(newAuditRecord(log.Index, log.AppendedAt, &cmd, nil, err))
+		return err
+	}
+
+	f.historyFloor = historyFloor
+	f.commit(tx.changes)
+	if len(tx.changes) > 0 {
+		f.notify()
+	}
+	// Deleted jobs are archived whichever command deleted them, be it a
+	// purge, a delete or a transaction
+	if f.archive != nil {
+		f.archivePurged(log.Index, log.AppendedAt, tx.deleted)
+	}
+	if f.invariantChecks.AfterApply {
+		// The deferred recordDigest would publish the index too late
+		f.appliedIndex = log.Index
+		f.runInvariantChecks()
+	}
+	return result
+}
+
+// skip records that a log entry was applied without changing any resources,
+// along with the audit record of its failure. The caller must hold the write
+// lock.
+func (f *FSM) skip(record *AuditRecord) {
+	err := f.state.update(func(tx stateTx) error {
+		if err := f.recordAudit(tx, record); err != nil {
+			return err
+		}
+		return putAppliedIndex(tx, record.Index)
+	})
+	if err != nil {
+		log.Printf("Failed to record applied index %d: %v", record.Index, err)
+	}
+}
+
+// commit folds the changes of a committed update into the indexes and the
+// digest. The caller must hold the write lock.
+func (f *FSM) commit(changes []*mutation) {
+	for _, c := range changes {
+		if c.before != nil {
+			f.digest.toggle(c.bucket, c.id, c.before)
+		}
+		if c.after != nil {
+			f.digest.toggle(c.bucket, c.id, c.after)
+		}
+
+		if c.bucket != bucketPrintJobs {
+			continue
+		}
+		var job models.PrintJob
+		if c.before != nil && json.Unmarshal(c.before, &job) == nil {
+			f.jobIndex.remove(&job)
+		}
+		job = models.PrintJob{}
+		if c.after != nil && json.Unmarshal(c.after, &job) == nil {
+			f.jobIndex.add(&job)
+		}
+	}
+}
+
+// applyCommand processes a command based on its type. Most commands have
+// no result beyond success; transactions return a *TransactionResult.
+func (f *FSM) applyCommand(tx *fsmTx, cmd *models.Command) (interface{}, error) {
+	if err := checkExpectedVersion(tx.tx, cmd); err != nil {
+		return nil, err
 	}
 
-	// Process the command based on its type
 	switch cmd.Type {
-	case models.AddPrinter:
+	case models.AddPrinter, models.UpsertPrinter:
 		if cmd.Printer == nil {
-			return fmt.Errorf("printer is nil")
+			return nil, errorf(ErrValidation, "printer is nil")
 		}
-		f.printers[cmd.Printer.ID] = cmd.Printer
-		return nil
+		if cmd.Type == models.AddPrinter {
+			return nil, tx.create(bucketPrinters, cmd.Printer.ID, cmd.Printer)
+		}
+		return nil, tx.put(bucketPrinters, cmd.Printer.ID, cmd.Printer)
 
-	case models.AddFilament:
+	case models.AddFilament, models.UpsertFilament:
 		if cmd.Filament == nil {
-			return fmt.Errorf("filament is nil")
+			return nil, errorf(ErrValidation, "filament is nil")
 		}
-		f.filaments[cmd.Filament.ID] = cmd.Filament
-		return nil
+		if cmd.Type == models.AddFilament {
+			return nil, tx.create(bucketFilaments, cmd.Filament.ID, cmd.Filament)
+		}
+		return nil, applyUpsertFilament(tx, cmd)
 
-	case models.AddPrintJob:
-		if cmd.PrintJob == nil {
-			return fmt.Errorf("print job is nil")
+	case models.AddPrintJob, models.UpsertPrintJob, models.ImportPrintJob:
+		return nil, f.applyAddPrintJob(tx, cmd, cmd.Type == models.UpsertPrintJob)
+
+	case models.UpdatePrintJob:
+		return nil, f.applyUpdatePrintJob(tx, cmd)
+	case models.DeletePrintJob:
+		return nil, applyDeletePrintJob(tx, cmd)
+
+	case models.UpdatePrinter:
+		return nil, applyUpdatePrinter(tx, cmd)
+
+	case models.DeletePrinter:
+		return nil, f.applyDeletePrinter(tx, cmd)
+
+	case models.UpdateFilament:
+		return nil, applyUpdateFilament(tx, cmd)
+
+	case models.DeleteFilament:
+		return nil, f.applyDeleteFilament(tx, cmd)
+
+	case models.AdjustFilamentWeight:
+		return nil, f.applyAdjustFilamentWeight(tx, cmd)
+
+	case models.PurgePrintJobs:
+		return nil, applyPurgePrintJobs(tx, cmd)
+
+	case models.CommitTransaction:
+		return f.applyTransaction(tx, cmd)
+
+	case models.CreateAPIKey:
+		return nil, applyCreateAPIKey(tx, cmd)
+	case models.RotateAPIKey:
+		return nil, applyRotateAPIKey(tx, cmd)
+	case models.DeleteAPIKey:
+		return nil, applyDeleteAPIKey(tx, cmd)
+	case models.SetRoleBinding:
+		return nil, applySetRoleBinding(tx, cmd)
+	case models.DeleteRoleBinding:
+		return nil, applyDeleteRoleBinding(tx, cmd)
+
+	case models.ReportDivergence:
+		// Only the audit record Apply writes for every command is kept
+		if cmd.Reason == "" {
+			return nil, errorf(ErrValidation, "divergence report has no reason")
 		}
+		return nil, nil
 
-		// Validate printer and filament exist
-		if _, ok := f.printers[cmd.PrintJob.PrinterID]; !ok {
-			return fmt.Errorf("printer with ID %s does not exist", cmd.PrintJob.PrinterID)
+	default:
+		return nil, errorf(ErrValidation, "unknown command type: %s", cmd.Type)
+	}
+}
+
+// applyAddPrintJob queues a new print job. With upsert it may also replace
+// an existing job, which keeps its status. A Queued or Running job keeps its
+// filament and weight too, since they are what it has reserved.
+func (f *FSM) applyAddPrintJob(tx *fsmTx, cmd *models.Command, upsert bool) error {
+	if cmd.PrintJob == nil {
+		return errorf(ErrValidation, "print job is nil")
+	}
+
+	existing, err := getResource[models.PrintJob](tx.tx, bucketPrintJobs, cmd.PrintJob.ID)
+	if err != nil {
+		return err
+	}
+	if existing != nil && !upsert {
+		return fmt.Errorf("%w: %s %s", ErrAlreadyExists, bucketPrintJobs, cmd.PrintJob.ID)
+	}
+
+	// Initialize status to Queued. A replaced job keeps its lifecycle, and
+	// an imported one the lifecycle it came with.
+	switch {
+	case cmd.Type == models.ImportPrintJob:
+		if err := prepareImportedPrintJob(tx, cmd); err != nil {
+			return err
 		}
-		filament, ok := f.filaments[cmd.PrintJob.FilamentID]
-		if !ok {
-			return fmt.Errorf("filament with ID %s does not exist", cmd.PrintJob.FilamentID)
+	case existing != nil:
+		if isReserving(existing.Status) && (cmd.PrintJob.FilamentID != existing.FilamentID ||
+			cmd.PrintJob.PrintWeightInGrams != existing.PrintWeightInGrams) {
+			return fmt.Errorf("%w: print job %s is %s, so its filament and weight can't change",
+				ErrJobNotFinished, existing.ID, existing.Status)
 		}
+		cmd.PrintJob.Status = existing.Status
+		cmd.PrintJob.FinishedAt = existing.FinishedAt
+		cmd.PrintJob.Transitions = existing.Transitions
+	default:
+		cmd.PrintJob.Status = "Queued"
+		cmd.PrintJob.FinishedAt = nil
+		cmd.PrintJob.Transitions = []models.StatusTransition{newTransition(tx, cmd, "", "Queued")}
+	}
 
+	// Validate printer and filament exist
+	printer, err := getResource[models.Printer](tx.tx, bucketPrinters, cmd.PrintJob.PrinterID)
+	if err != nil {
+		return err
+	}
+	if printer == nil {
+		return errorf(ErrValidation, "printer with ID %s does not exist", cmd.PrintJob.PrinterID)
+	}
+	filament, err := getResource[models.Filament](tx.tx, bucketFilaments, cmd.PrintJob.FilamentID)
+	if err != nil {
+		return err
+	}
+	if filament == nil {
+		return errorf(ErrValidation, "filament with ID %s does not exist", cmd.PrintJob.FilamentID)
+	}
+
+	if isReserving(cmd.PrintJob.Status) {
 		// Calculate available filament weight
-		availableWeight := filament.RemainingWeightInGrams
-		for _, job := range f.printJobs {
-			if job.FilamentID == cmd.PrintJob.FilamentID && (job.Status == "Queued" || job.Status == "Running") {
-				availableWeight -= job.PrintWeightInGrams
-			}
+		availableWeight := filament.RemainingWeightInGrams - f.reservedGrams(tx, cmd.PrintJob.FilamentID)
+		if existing != nil && existing.FilamentID == cmd.PrintJob.FilamentID && isReserving(existing.Status) {
+			availableWeight += existing.PrintWeightInGrams
 		}
 
 		// Check if there's enough filament
 		if cmd.PrintJob.PrintWeightInGrams > availableWeight {
-			return fmt.Errorf("not enough filament remaining. Available: %d g, Required: %d g",
+			return errorf(ErrInsufficientFilament, "not enough filament remaining. Available: %d g, Required: %d g",
 				availableWeight, cmd.PrintJob.PrintWeightInGrams)
 		}
+	}
 
-		// Initialize status to Queued
-		cmd.PrintJob.Status = "Queued"
-		f.printJobs[cmd.PrintJob.ID] = cmd.PrintJob
-		return nil
+	return tx.put(bucketPrintJobs, cmd.PrintJob.ID, cmd.PrintJob)
+}
 
-	case models.UpdatePrintJob:
-		job, ok := f.printJobs[cmd.JobID]
-		if !ok {
-			return fmt.Errorf("print job with ID %s does not exist", cmd.JobID)
-		}
+// applyUpdatePrintJob moves a print job to a new status
+func (f *FSM) applyUpdatePrintJob(tx *fsmTx, cmd *models.Command) error {
+	job, err := getResource[models.PrintJob](tx.tx, bucketPrintJobs, cmd.JobID)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return errorf(ErrNotFound, "print job with ID %s does not exist", cmd.JobID)
+	}
 
-		// Validate status transition
-		if err := models.ValidateStatusChange(job.Status, cmd.NewStatus); err != nil {
-			return err
-		}
+	// Validate status transition
+	if err := models.ValidateStatusChange(job.Status, cmd.NewStatus); err != nil {
+		return errorf(ErrInvalidTransition, "%v", err)
+	}
 
-		// Update status
-		oldStatus := job.Status
-		job.Status = cmd.NewStatus
+	// Only a canceled job that was Running has used up part of its filament
+	consumed := 0
+	switch {
+	case cmd.ConsumedGrams < 0:
+		return errorf(ErrValidation, "consumed grams can't be negative")
+	case cmd.ConsumedGrams > 0 && (job.Status != "Running" || cmd.NewStatus != "Canceled"):
+		return errorf(ErrValidation, "consumed grams can only be given when canceling a Running job")
+	case cmd.ConsumedGrams > job.PrintWeightInGrams:
+		return errorf(ErrValidation, "consumed grams can't exceed the job's %d g", job.PrintWeightInGrams)
+	case job.Status == "Running" && cmd.NewStatus == "Done":
+		consumed = job.PrintWeightInGrams
+	default:
+		consumed = cmd.ConsumedGrams
+	}
 
-		// Reduce filament weight if job is done
-		if oldStatus == "Running" && cmd.NewStatus == "Done" {
-			filament, ok := f.filaments[job.FilamentID]
-			if !ok {
-				return fmt.Errorf("filament with ID %s does not exist", job.FilamentID)
-			}
-			filament.RemainingWeightInGrams -= job.PrintWeightInGrams
-			if filament.RemainingWeightInGrams < 0 {
-				filament.RemainingWeightInGrams = 0
-			}
-		}
-		return nil
+	// Update status
+	transition := newTransition(tx, cmd, job.Status, cmd.NewStatus)
+	transition.ConsumedGrams = cmd.ConsumedGrams
+	job.Transitions = append(job.Transitions, transition)
+	job.Status = cmd.NewStatus
+	if models.IsTerminalPrintJobStatus(job.Status) && !tx.appendedAt.IsZero() {
+		finishedAt := tx.appendedAt
+		job.FinishedAt = &finishedAt
+	}
+	if err := tx.put(bucketPrintJobs, job.ID, job); err != nil {
+		return err
+	}
 
-	default:
-		return fmt.Errorf("unknown command type: %s", cmd.Type)
+	// Reduce filament weight by what the job used up
+	if consumed > 0 {
+		filament, err := getResource[models.Filament](tx.tx, bucketFilaments, job.FilamentID)
+		if err != nil {
+			return err
+		}
+		if filament == nil {
+			return errorf(ErrNotFound, "filament with ID %s does not exist", job.FilamentID)
+		}
+		// The reservation check keeps this from going negative as long as
+		// InvariantFilamentReservation holds. Should it be broken, the
+		// print has still happened, so the job finishes and the shortfall
+		// is left for the invariant checker to report and repair.
+		filament.RemainingWeightInGrams -= consumed
+		return tx.put(bucketFilaments, filament.ID, filament)
 	}
+	return nil
 }
 
 // Snapshot returns a snapshot of the FSM state
@@ -127,29 +492,18 @@ func (f *FSM) Snapshot() (raft.FSMSnapshot, error) {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
-	// Create a deep copy of the state
-	printers := make(map[string]*models.Printer)
-	for k, v := range f.printers {
-		printer := *v
-		printers[k] = &printer
-	}
-
-	filaments := make(map[string]*models.Filament)
-	for k, v := range f.filaments {
-		filament := *v
-		filaments[k] = &filament
-	}
-
-	printJobs := make(map[string]*models.PrintJob)
-	for k, v := range f.printJobs {
-		job := *v
-		printJobs[k] = &job
+	// Hold a read view of the backend rather than copying the state, so
+	// applies can carry on while the snapshot is persisted
+	view, release, err := f.state.readView()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state for snapshot: %v", err)
 	}
 
 	return &fsmSnapshot{
-		printers:  printers,
-		filaments: filaments,
-		printJobs: printJobs,
+		appliedIndex: f.appliedIndex,
+		compression:  f.snapshotCompression,
+		view:         view,
+		release:      release,
 	}, nil
 }
 
@@ -157,106 +511,150 @@ func (f *FSM) Snapshot() (raft.FSMSnapshot, error) {
 func (f *FSM) Restore(rc io.ReadCloser) error {
 	defer rc.Close()
 
-	// Read the snapshot data
-	var snapshot fsmSnapshot
-	if err := json.NewDecoder(rc).Decode(&snapshot); err != nil {
-		return err
+	src, err := openSnapshot(rc)
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %v", err)
 	}
+	defer src.close()
 
-	// Restore the state
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	f.printers = snapshot.printers
-	f.filaments = snapshot.filaments
-	f.printJobs = snapshot.printJobs
+	// A persistent backend may already be past the snapshot, in which case
+	// Raft replays the log from the snapshot onwards and Apply skips what
+	// has already been applied
+	if src.appliedIndex() != 0 && src.appliedIndex() <= f.appliedIndex {
+		return nil
+	}
+
+	// Stream the snapshot into a single transaction. It only commits once
+	// the whole snapshot has been read and its checksum verified, so a
+	// corrupt snapshot never half-replaces the state.
+	err = f.state.update(func(tx stateTx) error {
+		for _, bucket := range snapshotBuckets {
+			if err := tx.deleteAll(bucket); err != nil {
+				return err
+			}
+		}
+
+		hasHistoryFloor := false
+		for {
+			bucket, id, data, err := src.next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if !containsBucket(snapshotBuckets, bucket) {
+				return fmt.Errorf("snapshot contains unknown bucket %s", bucket)
+			}
+			if bucket == bucketMeta && id == metaHistoryFloor {
+				hasHistoryFloor = true
+			}
+			if err := tx.put(bucket, id, data); err != nil {
+				return err
+			}
+		}
+
+		// Snapshots taken before history was kept can't be rolled back
+		if !hasHistoryFloor {
+			if err := putHistoryFloor(tx, src.appliedIndex()); err != nil {
+				return err
+			}
+		}
+		if err := indexResourceHistory(tx); err != nil {
+			return err
+		}
+		return putAppliedIndex(tx, src.appliedIndex())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore snapshot: %v", err)
+	}
 
+	defer f.notify()
+	if err := f.rebuild(); err != nil {
+		return err
+	}
+	if f.invariantChecks.AfterRestore {
+		f.runInvariantChecks()
+	}
 	return nil
 }
 
+// view runs fn against the current state. The getters have no way to report
+// a failed read, so it is logged and they return what they have.
+func (f *FSM) view(fn func(tx stateTx) error) {
+	if err := f.state.view(fn); err != nil {
+		log.Printf("Failed to read FSM state: %v", err)
+	}
+}
+
+// The getters below return freshly decoded resources, so callers can hold on
+// to them while Apply keeps changing the state.
+
 // GetPrinters returns all printers
 func (f *FSM) GetPrinters() []*models.Printer {
-	f.mu.RLock()
-	defer f.mu.RUnlock()
-
-	printers := make([]*models.Printer, 0, len(f.printers))
-	for _, printer := range f.printers {
-		printers = append(printers, printer)
-	}
+	printers := make([]*models.Printer, 0)
+	f.view(func(tx stateTx) error {
+		list, err := listResources[models.Printer](tx, bucketPrinters)
+		printers = list
+		return err
+	})
 	return printers
 }
 
 // GetFilaments returns all filaments
 func (f *FSM) GetFilaments() []*models.Filament {
-	f.mu.RLock()
-	defer f.mu.RUnlock()
-
-	filaments := make([]*models.Filament, 0, len(f.filaments))
-	for _, filament := range f.filaments {
-		filaments = append(filaments, filament)
-	}
+	filaments := make([]*models.Filament, 0)
+	f.view(func(tx stateTx) error {
+		list, err := listResources[models.Filament](tx, bucketFilaments)
+		filaments = list
+		return err
+	})
 	return filaments
 }
 
 // GetPrintJobs returns all print jobs
 func (f *FSM) GetPrintJobs() []*models.PrintJob {
-	f.mu.RLock()
-	defer f.mu.RUnlock()
-
-	printJobs := make([]*models.PrintJob, 0, len(f.printJobs))
-	for _, job := range f.printJobs {
-		printJobs = append(printJobs, job)
-	}
+	printJobs := make([]*models.PrintJob, 0)
+	f.view(func(tx stateTx) error {
+		list, err := listResources[models.PrintJob](tx, bucketPrintJobs)
+		printJobs = list
+		return err
+	})
 	return printJobs
 }
 
-// GetPrintJobsByStatus returns print jobs filtered by status
-func (f *FSM) GetPrintJobsByStatus(status string) []*models.PrintJob {
-	f.mu.RLock()
-	defer f.mu.RUnlock()
+// GetPrinter returns a printer by ID
+func (f *FSM) GetPrinter(id string) (*models.Printer, bool) {
+	var printer *models.Printer
+	f.view(func(tx stateTx) error {
+		var err error
+		printer, err = getResource[models.Printer](tx, bucketPrinters, id)
+		return err
+	})
+	return printer, printer != nil
+}
 
-	var jobs []*models.PrintJob
-	for _, job := range f.printJobs {
-		if job.Status == status {
-			jobs = append(jobs, job)
-		}
-	}
-	return jobs
+// GetFilament returns a filament by ID
+func (f *FSM) GetFilament(id string) (*models.Filament, bool) {
+	var filament *models.Filament
+	f.view(func(tx stateTx) error {
+		var err error
+		filament, err = getResource[models.Filament](tx, bucketFilaments, id)
+		return err
+	})
+	return filament, filament != nil
 }
 
 // GetPrintJob returns a print job by ID
 func (f *FSM) GetPrintJob(id string) (*models.PrintJob, bool) {
-	f.mu.RLock()
-	defer f.mu.RUnlock()
-
-	job, ok := f.printJobs[id]
-	return job, ok
-}
-
-// fsmSnapshot implements the raft.FSMSnapshot interface
-type fsmSnapshot struct {
-	printers  map[string]*models.Printer
-	filaments map[string]*models.Filament
-	printJobs map[string]*models.PrintJob
-}
-
-// Persist saves the snapshot to the provided sink
-func (s *fsmSnapshot) Persist(sink raft.SnapshotSink) error {
-	err := func() error {
-		// Encode the snapshot
-		if err := json.NewEncoder(sink).Encode(s); err != nil {
-			return err
-		}
-		return sink.Close()
-	}()
-
-	if err != nil {
-		sink.Cancel()
+	var job *models.PrintJob
+	f.view(func(tx stateTx) error {
+		var err error
+		job, err = getResource[models.PrintJob](tx, bucketPrintJobs, id)
 		return err
-	}
-
-	return nil
+	})
+	return job, job != nil
 }
-
-// Release is a no-op
-func (s *fsmSnapshot) Release() {}
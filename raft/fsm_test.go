@@ -0,0 +1,242 @@
+package raft
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
+	"github.com/hashicorp/raft"
+)
+
+// testFSM feeds commands to an FSM as consecutive log entries and checks
+// the invariants after each of them
+type testFSM struct {
+	*FSM
+	t     testing.TB
+	index uint64
+	now   time.Time
+
+	// skipInvariants is set by tests that break the invariants on purpose
+	skipInvariants bool
+}
+
+// newTestFSM creates an in-memory FSM for a test
+func newTestFSM(t testing.TB) *testFSM {
+	return newTestFSMOn(t, newMemoryBackend())
+}
+
+// newTestFSMOn creates an FSM for a test on top of a state backend
+func newTestFSMOn(t testing.TB, state stateBackend) *testFSM {
+	f, err := newFSM(state)
+	if err != nil {
+		t.Fatalf("failed to create FSM: %v", err)
+	}
+	return &testFSM{
+		FSM: f,
+		t:   t,
+		now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// apply applies cmd as the next log entry, a second after the previous one,
+// and returns what Apply returned. Whether cmd succeeds or not, the state it
+// leaves behind must uphold the invariants.
+func (f *testFSM) apply(cmd *models.Command) interface{} {
+	f.t.Helper()
+
+	data, err := cmd.Marshal()
+	if err != nil {
+		f.t.Fatalf("failed to marshal command: %v", err)
+	}
+	f.index++
+	f.now = f.now.Add(time.Second)
+	result := f.Apply(&raft.Log{Index: f.index, Type: raft.LogCommand, Data: data, AppendedAt: f.now})
+
+	if !f.skipInvariants {
+		report, err := f.CheckInvariants()
+		if err != nil {
+			f.t.Fatalf("failed to check invariants: %v", err)
+		}
+		for _, v := range report.Violations {
+			f.t.Errorf("%s at index %d broke %s on %s %s: %s", cmd.Type, f.index, v.Invariant, v.ResourceType, v.ResourceID, v.Message)
+		}
+		if len(report.Violations) > 0 {
+			f.t.FailNow()
+		}
+	}
+	return result
+}
+
+// mustApply applies cmd and fails the test if it doesn't succeed
+func (f *testFSM) mustApply(cmd *models.Command) {
+	f.t.Helper()
+
+	if err, ok := f.apply(cmd).(error); ok {
+		f.t.Fatalf("failed to apply %s: %v", cmd.Type, err)
+	}
+}
+
+// discardSink is a snapshot sink that throws the snapshot away
+type discardSink struct{}
+
+func (discardSink) Write(p []byte) (int, error) { return len(p), nil }
+func (discardSink) Close() error                { return nil }
+func (discardSink) ID() string                  { return "discard" }
+func (discardSink) Cancel() error               { return nil }
+
+var _ raft.SnapshotSink = discardSink{}
+
+func TestFSMConcurrentApplySnapshotAndReads(t *testing.T) {
+	const (
+		printers = 4
+		jobs     = 300
+		readers  = 4
+	)
+
+	f := newTestFSM(t)
+	for i := 0; i < printers; i++ {
+		f.mustApply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: fmt.Sprintf("p%d", i), Company: "Prusa", Model: "MK4"}})
+	}
+	f.mustApply(&models.Command{Type: models.AddFilament, Filament: &models.Filament{
+		ID: "f1", Type: "PLA", Color: "black", TotalWeightInGrams: 100000, RemainingWeightInGrams: 100000,
+	}})
+
+	// Keep a copy of a printer from before the writes to check that later
+	// writes don't reach into it
+	before, ok := f.GetPrinter("p0")
+	if !ok {
+		t.Fatal("printer p0 not found")
+	}
+	kept := *before
+
+	running, err := ParseFilter(ResourcePrintJobs, "status = Running")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	errs := make(chan error, 1)
+	fail := func(format string, args ...interface{}) {
+		select {
+		case errs <- fmt.Errorf(format, args...):
+		default:
+		}
+	}
+
+	// Snapshots stream a read view while applies keep writing
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			snap, err := f.Snapshot()
+			if err != nil {
+				fail("failed to snapshot: %v", err)
+				return
+			}
+			if err := snap.Persist(discardSink{}); err != nil {
+				fail("failed to persist snapshot: %v", err)
+			}
+			snap.Release()
+		}
+	}()
+
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				for _, job := range f.GetPrintJobs() {
+					if n := len(job.Transitions); n == 0 || job.Transitions[n-1].To != job.Status {
+						fail("job %s is %s but its transitions say otherwise", job.ID, job.Status)
+					}
+					// Getters hand out copies, so this must not show up in
+					// later reads
+					job.Status = "Mangled"
+					job.Transitions = nil
+				}
+
+				filament, ok := f.GetFilament("f1")
+				if !ok {
+					fail("filament f1 not found")
+					continue
+				}
+				if filament.RemainingWeightInGrams < 0 || filament.RemainingWeightInGrams > filament.TotalWeightInGrams {
+					fail("filament f1 has %d g of %d g left", filament.RemainingWeightInGrams, filament.TotalWeightInGrams)
+				}
+				filament.RemainingWeightInGrams = -1
+
+				reservations, ok := f.GetFilamentReservations("f1")
+				if !ok {
+					fail("filament f1 not found")
+					continue
+				}
+				sum := 0
+				for _, r := range reservations.Reservations {
+					sum += r.Grams
+				}
+				if sum != reservations.ReservedGrams ||
+					reservations.FreeGrams != reservations.RemainingWeightInGrams-reservations.ReservedGrams {
+					fail("inconsistent reservations: %+v", reservations)
+				}
+
+				for _, job := range f.QueryPrintJobs(running) {
+					if job.Status != "Running" {
+						fail("status query returned %s job %s", job.Status, job.ID)
+					}
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < jobs; i++ {
+		id := fmt.Sprintf("job%03d", i)
+		f.mustApply(&models.Command{Type: models.AddPrintJob, PrintJob: &models.PrintJob{
+			ID: id, PrinterID: fmt.Sprintf("p%d", i%printers), FilamentID: "f1", Filepath: "/prints/part.gcode", PrintWeightInGrams: 10,
+		}})
+		if i%2 == 0 {
+			f.mustApply(&models.Command{Type: models.UpdatePrintJob, JobID: id, NewStatus: "Running"})
+			f.mustApply(&models.Command{Type: models.UpdatePrintJob, JobID: id, NewStatus: "Done"})
+		}
+		f.mustApply(&models.Command{Type: models.UpdatePrinter, PrinterID: "p0", Patch: json.RawMessage(fmt.Sprintf(`{"model":"MK%d"}`, i))})
+	}
+	close(done)
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		t.Fatal(err)
+	default:
+	}
+
+	if !reflect.DeepEqual(*before, kept) {
+		t.Errorf("printer read before the writes changed to %+v", *before)
+	}
+	for _, job := range f.GetPrintJobs() {
+		if job.Status != "Queued" && job.Status != "Done" {
+			t.Errorf("job %s is %s", job.ID, job.Status)
+		}
+	}
+	filament, _ := f.GetFilament("f1")
+	if want := 100000 - jobs/2*10; filament.RemainingWeightInGrams != want {
+		t.Errorf("filament f1 has %d g left, want %d g", filament.RemainingWeightInGrams, want)
+	}
+	if got, want := f.ReservedGrams("f1"), jobs/2*10; got != want {
+		t.Errorf("filament f1 has %d g reserved, want %d g", got, want)
+	}
+}
@@ -0,0 +1,476 @@
+package raft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+const (
+	// bucketHistory holds a record of every resource change, keyed so that
+	// they sort in the order they were applied
+	bucketHistory = "history"
+
+	// bucketResourceHistory indexes the history by resource, so the
+	// versions of one resource are found without reading anyone else's.
+	// Its keys are resourceHistoryKeys, its values empty.
+	bucketResourceHistory = "resource_history"
+
+	// metaHistoryFloor is the key in bucketMeta of the oldest index the
+	// history can rebuild the state at
+	metaHistoryFloor = "history_floor"
+
+	// maxHistoryPrune bounds how many history records a single apply
+	// deletes, so catching up on a lowered retention doesn't stall applies
+	maxHistoryPrune = 1000
+
+	// DefaultHistoryRetainIndexes is the default number of log indexes the
+	// history reaches back
+	DefaultHistoryRetainIndexes = 10000
+)
+
+// ErrHistoryUnavailable is returned when a historical query reaches further
+// back than the history retains
+var ErrHistoryUnavailable = errors.New("state is no longer retained at the requested point")
+
+// errStopIteration stops a forEach early without reporting an error
+var errStopIteration = errors.New("stop iteration")
+
+// Change is one version of a resource: the state it had before and after
+// the log entry at Index, appended by the leader at Time. Before is empty for
+// created resources and After for deleted ones.
+type Change struct {
+	Index   uint64             `json:"index"`
+	Time    time.Time          `json:"time"`
+	Type    string             `json:"type"`
+	ID      string             `json:"id"`
+	Command models.CommandType `json:"command"`
+	Before  json.RawMessage    `json:"before,omitempty"`
+	After   json.RawMessage    `json:"after,omitempty"`
+}
+
+// historyKey builds the key of the seq'th change made by the entry at index
+func historyKey(index uint64, seq int) string {
+	return fmt.Sprintf("%020d-%06d", index, seq)
+}
+
+// resourceHistoryPrefix is what the resourceHistoryKeys of a resource's
+// changes start with. IDs can't contain a slash, so no resource's prefix is
+// a prefix of another's.
+func resourceHistoryPrefix(resourceType, id string) string {
+	return resourceType + "/" + id + "/"
+}
+
+// resourceHistoryKey builds the key that indexes a change under its
+// resource. It ends in the change's historyKey.
+func resourceHistoryKey(resourceType, id string, index uint64, seq int) string {
+	return resourceHistoryPrefix(resourceType, id) + historyKey(index, seq)
+}
+
+// HistoryRetention bounds how far back the history reaches. A zero field
+// doesn't limit the history; both zero disables it.
+type HistoryRetention struct {
+	// Indexes is the number of log indexes to keep history for
+	Indexes uint64
+	// Duration is how long to keep history for, by leader time
+	Duration time.Duration
+}
+
+// enabled reports whether any history is kept
+func (r HistoryRetention) enabled() bool {
+	return r.Indexes != 0 || r.Duration != 0
+}
+
+// SetHistoryRetention sets how far back the history reaches. Records past
+// it are pruned gradually as new entries are applied.
+func (f *FSM) SetHistoryRetention(retention HistoryRetention) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.historyRetention = retention
+}
+
+// getHistoryFloor reads the oldest index the history can rebuild
+func getHistoryFloor(tx stateTx) (uint64, bool, error) {
+	data, err := tx.get(bucketMeta, metaHistoryFloor)
+	if err != nil || data == nil {
+		return 0, false, err
+	}
+	floor, err := strconv.ParseUint(string(data), 10, 64)
+	return floor, true, err
+}
+
+// putHistoryFloor stores the oldest index the history can rebuild
+func putHistoryFloor(tx stateTx, floor uint64) error {
+	return tx.put(bucketMeta, metaHistoryFloor, []byte(strconv.FormatUint(floor, 10)))
+}
+
+// recordHistory stores the changes made by the entry at index, prunes
+// records that fell out of retention and returns the new history floor. The
+// caller must hold the write lock.
+func (f *FSM) recordHistory(tx stateTx, index uint64, appendedAt time.Time, command models.CommandType, changes []*mutation) (uint64, error) {
+	if !f.historyRetention.enabled() {
+		// Nothing is kept, so nothing before this entry can be rebuilt
+		return index, putHistoryFloor(tx, index)
+	}
+
+	for seq, c := range changes {
+		// API keys and role bindings are not watched or queried as of an
+		// index
+		if !containsBucket(resourceBuckets, c.bucket) {
+			continue
+		}
+		data, err := json.Marshal(&Change{
+			Index:   index,
+			Time:    appendedAt,
+			Type:    c.bucket,
+			ID:      c.id,
+			Command: command,
+			Before:  c.before,
+			After:   c.after,
+		})
+		if err != nil {
+			return 0, err
+		}
+		if err := tx.put(bucketHistory, historyKey(index, seq), data); err != nil {
+			return 0, err
+		}
+		if err := tx.put(bucketResourceHistory, resourceHistoryKey(c.bucket, c.id, index, seq), []byte{}); err != nil {
+			return 0, err
+		}
+	}
+
+	return f.pruneHistory(tx, index, appendedAt)
+}
+
+// pruneHistory deletes the oldest history records that are out of
+// retention and returns the new history floor. Retention is judged by log
+// index and leader time only, so every replica prunes the same records. The
+// caller must hold the write lock.
+func (f *FSM) pruneHistory(tx stateTx, index uint64, appendedAt time.Time) (uint64, error) {
+	retention := f.historyRetention
+
+	var expired, expiredByResource []string
+	floor := f.historyFloor
+	err := tx.forEach(bucketHistory, func(key string, data []byte) error {
+		var record Change
+		if err := json.Unmarshal(data, &record); err != nil {
+			return err
+		}
+
+		expiredByIndex := retention.Indexes != 0 && record.Index+retention.Indexes <= index
+		expiredByTime := retention.Duration != 0 && !record.Time.IsZero() && !appendedAt.IsZero() &&
+			appendedAt.Sub(record.Time) > retention.Duration
+		if (!expiredByIndex && !expiredByTime) || len(expired) == maxHistoryPrune {
+			return errStopIteration
+		}
+
+		expired = append(expired, key)
+		expiredByResource = append(expiredByResource, resourceHistoryPrefix(record.Type, record.ID)+key)
+		floor = record.Index
+		return nil
+	})
+	if err != nil && err != errStopIteration {
+		return 0, err
+	}
+
+	for i, key := range expired {
+		if err := tx.delete(bucketHistory, key); err != nil {
+			return 0, err
+		}
+		if err := tx.delete(bucketResourceHistory, expiredByResource[i]); err != nil {
+			return 0, err
+		}
+	}
+	if floor != f.historyFloor {
+		return floor, putHistoryFloor(tx, floor)
+	}
+	return floor, nil
+}
+
+// IndexAsOf returns the last applied index whose entry the leader appended
+// no later than t
+func (f *FSM) IndexAsOf(t time.Time) (uint64, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var index uint64
+	found, stopped := false, false
+	err := f.state.view(func(tx stateTx) error {
+		// The leader appends entries in time order, so the first change
+		// after t is found by bisecting the indexes rather than reading
+		// every record older than it
+		var searchErr error
+		first := uint64(sort.Search(int(f.appliedIndex+1), func(i int) bool {
+			record, err := firstChangeFrom(tx, uint64(i))
+			if err != nil {
+				searchErr = err
+				return true
+			}
+			return record == nil || record.Time.After(t)
+		}))
+		if searchErr != nil {
+			return searchErr
+		}
+
+		// The change before it, if any, is at the index right before it
+		record, err := firstChangeFrom(tx, first)
+		if err != nil {
+			return err
+		}
+		found, stopped = first > 0, record != nil
+		if found {
+			index = first - 1
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Nothing retained is older than t, so t may predate the history
+	if !found && f.historyFloor != 0 {
+		return 0, ErrHistoryUnavailable
+	}
+	// Entries after the last change didn't change anything
+	if !stopped {
+		index = f.appliedIndex
+	}
+	return index, nil
+}
+
+// firstChangeFrom reads the first history record at or after index, or nil
+// if there is none
+func firstChangeFrom(tx stateTx, index uint64) (*Change, error) {
+	var record *Change
+	err := tx.forEachFrom(bucketHistory, historyKey(index, 0), func(key string, data []byte) error {
+		record = &Change{}
+		if err := json.Unmarshal(data, record); err != nil {
+			return err
+		}
+		return errStopIteration
+	})
+	if err != nil && err != errStopIteration {
+		return nil, err
+	}
+	return record, nil
+}
+
+// documentsAsOf returns the documents of a bucket as they were right after
+// the entry at index was applied, by rolling the current state back through
+// the history
+func (f *FSM) documentsAsOf(bucket string, index uint64) (map[string][]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if index < f.historyFloor {
+		return nil, ErrHistoryUnavailable
+	}
+
+	docs := make(map[string][]byte)
+	err := f.state.view(func(tx stateTx) error {
+		err := tx.forEach(bucket, func(id string, data []byte) error {
+			docs[id] = append([]byte(nil), data...)
+			return nil
+		})
+		if err != nil || index >= f.appliedIndex {
+			return err
+		}
+
+		// The oldest change after index holds the version as of index
+		rolledBack := make(map[string]bool)
+		return tx.forEachFrom(bucketHistory, historyKey(index+1, 0), func(key string, data []byte) error {
+			var record Change
+			if err := json.Unmarshal(data, &record); err != nil {
+				return err
+			}
+			if record.Type != bucket || rolledBack[record.ID] {
+				return nil
+			}
+			rolledBack[record.ID] = true
+			if record.Before == nil {
+				delete(docs, record.ID)
+			} else {
+				docs[record.ID] = record.Before
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// documentAsOf returns the document of one resource as it was right after
+// the entry at index was applied, or nil if it didn't exist then. Only the
+// changes to that resource are read.
+func (f *FSM) documentAsOf(bucket, id string, index uint64) ([]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if index < f.historyFloor {
+		return nil, ErrHistoryUnavailable
+	}
+
+	var doc []byte
+	err := f.state.view(func(tx stateTx) error {
+		data, err := tx.get(bucket, id)
+		if err != nil {
+			return err
+		}
+		// The backend's slice dies with the transaction
+		if data != nil {
+			doc = append([]byte(nil), data...)
+		}
+		if index >= f.appliedIndex {
+			return nil
+		}
+
+		// The oldest change after index holds the version as of index
+		prefix := resourceHistoryPrefix(bucket, id)
+		return tx.forEachFrom(bucketResourceHistory, prefix+historyKey(index+1, 0), func(key string, _ []byte) error {
+			if !strings.HasPrefix(key, prefix) {
+				return errStopIteration
+			}
+			data, err := tx.get(bucketHistory, key[len(prefix):])
+			if err != nil {
+				return err
+			}
+			if data == nil {
+				return fmt.Errorf("history record %s is missing", key)
+			}
+			var record Change
+			if err := json.Unmarshal(data, &record); err != nil {
+				return err
+			}
+			doc = append([]byte(nil), record.Before...)
+			if record.Before == nil {
+				doc = nil
+			}
+			return errStopIteration
+		})
+	})
+	if err != nil && err != errStopIteration {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// resourceAsOf decodes one resource as of index, returning nil if it
+// didn't exist then
+func resourceAsOf[T any](f *FSM, bucket, id string, index uint64) (*T, error) {
+	doc, err := f.documentAsOf(bucket, id, index)
+	if err != nil || doc == nil {
+		return nil, err
+	}
+	var v T
+	if err := json.Unmarshal(doc, &v); err != nil {
+		return nil, fmt.Errorf("failed to decode %s %s: %v", bucket, id, err)
+	}
+	return &v, nil
+}
+
+// indexResourceHistory rebuilds the resource index of the history if it is
+// missing, as in snapshots and state files written before it was kept
+func indexResourceHistory(tx stateTx) error {
+	empty := func(bucket string) (bool, error) {
+		empty := true
+		err := tx.forEach(bucket, func(string, []byte) error {
+			empty = false
+			return errStopIteration
+		})
+		if err != nil && err != errStopIteration {
+			return false, err
+		}
+		return empty, nil
+	}
+	if unindexed, err := empty(bucketResourceHistory); err != nil || !unindexed {
+		return err
+	}
+	if unrecorded, err := empty(bucketHistory); err != nil || unrecorded {
+		return err
+	}
+
+	var keys []string
+	err := tx.forEach(bucketHistory, func(key string, data []byte) error {
+		var record Change
+		if err := json.Unmarshal(data, &record); err != nil {
+			return err
+		}
+		keys = append(keys, resourceHistoryPrefix(record.Type, record.ID)+key)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := tx.put(bucketResourceHistory, key, []byte{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resourcesAsOf decodes a bucket as of index, ordered by ID
+func resourcesAsOf[T any](f *FSM, bucket string, index uint64) ([]*T, error) {
+	docs, err := f.documentsAsOf(bucket, index)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(docs))
+	for id := range docs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	list := make([]*T, 0, len(ids))
+	for _, id := range ids {
+		var v T
+		if err := json.Unmarshal(docs[id], &v); err != nil {
+			return nil, fmt.Errorf("failed to decode %s %s: %v", bucket, id, err)
+		}
+		list = append(list, &v)
+	}
+	return list, nil
+}
+
+// GetPrintersAsOf returns all printers as they were at a log index
+func (f *FSM) GetPrintersAsOf(index uint64) ([]*models.Printer, error) {
+	return resourcesAsOf[models.Printer](f, bucketPrinters, index)
+}
+
+// GetFilamentsAsOf returns all filaments as they were at a log index
+func (f *FSM) GetFilamentsAsOf(index uint64) ([]*models.Filament, error) {
+	return resourcesAsOf[models.Filament](f, bucketFilaments, index)
+}
+
+// GetPrintJobsAsOf returns all print jobs as they were at a log index
+func (f *FSM) GetPrintJobsAsOf(index uint64) ([]*models.PrintJob, error) {
+	return resourcesAsOf[models.PrintJob](f, bucketPrintJobs, index)
+}
+
+// GetPrinterAsOf returns a printer as it was at a log index, or nil if it
+// didn't exist then
+func (f *FSM) GetPrinterAsOf(id string, index uint64) (*models.Printer, error) {
+	return resourceAsOf[models.Printer](f, bucketPrinters, id, index)
+}
+
+// GetFilamentAsOf returns a filament as it was at a log index, or nil if
+// it didn't exist then
+func (f *FSM) GetFilamentAsOf(id string, index uint64) (*models.Filament, error) {
+	return resourceAsOf[models.Filament](f, bucketFilaments, id, index)
+}
+
+// GetPrintJobAsOf returns a print job as it was at a log index, or nil if
+// it didn't exist then
+func (f *FSM) GetPrintJobAsOf(id string, index uint64) (*models.PrintJob, error) {
+	return resourceAsOf[models.PrintJob](f, bucketPrintJobs, id, index)
+}
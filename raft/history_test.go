@@ -0,0 +1,166 @@
+package raft
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+// checkAsOf fails the test unless every printer read on its own as of
+// index matches the list of printers as of index
+func checkAsOf(t *testing.T, f *FSM, ids []string, index uint64) {
+	t.Helper()
+
+	list, err := f.GetPrintersAsOf(index)
+	if err != nil {
+		t.Fatalf("failed to list printers as of %d: %v", index, err)
+	}
+	want := make(map[string]*models.Printer)
+	for _, p := range list {
+		want[p.ID] = p
+	}
+	for _, id := range ids {
+		got, err := f.GetPrinterAsOf(id, index)
+		if err != nil {
+			t.Fatalf("failed to get printer %s as of %d: %v", id, index, err)
+		}
+		g, _ := json.Marshal(got)
+		w, _ := json.Marshal(want[id])
+		if string(g) != string(w) {
+			t.Errorf("printer %s as of %d is %s, the list has %s", id, index, g, w)
+		}
+	}
+}
+
+func TestResourceAsOf(t *testing.T) {
+	f := newTestFSM(t)
+	ids := []string{"p1", "p10", "p2"}
+
+	f.mustApply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: "p1", Company: "Prusa", Model: "MK3"}})
+	f.mustApply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: "p10", Company: "Bambu", Model: "X1"}})
+	f.mustApply(&models.Command{Type: models.UpdatePrinter, PrinterID: "p1", Patch: json.RawMessage(`{"model":"MK4"}`)})
+	f.mustApply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: "p2", Company: "Voron", Model: "2.4"}})
+	f.mustApply(&models.Command{Type: models.DeletePrinter, PrinterID: "p10"})
+	f.mustApply(&models.Command{Type: models.UpdatePrinter, PrinterID: "p1", Patch: json.RawMessage(`{"model":"XL"}`)})
+
+	p1, err := f.GetPrinterAsOf("p1", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p1 == nil || p1.Model != "MK3" || p1.Version != 1 {
+		t.Errorf("printer p1 as of 2 is %+v, want the MK3 at version 1", p1)
+	}
+	for index := uint64(0); index <= f.index; index++ {
+		checkAsOf(t, f.FSM, ids, index)
+	}
+
+	// Pruning drops the index entries with the records
+	f.SetHistoryRetention(HistoryRetention{Indexes: 3})
+	f.mustApply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: "p3", Company: "Prusa", Model: "Mini"}})
+	if _, err := f.GetPrinterAsOf("p1", 2); err != ErrHistoryUnavailable {
+		t.Errorf("reading past the history floor returned %v", err)
+	}
+	for index := f.historyFloor; index <= f.index; index++ {
+		checkAsOf(t, f.FSM, ids, index)
+	}
+	f.view(func(tx stateTx) error {
+		return tx.forEach(bucketResourceHistory, func(key string, _ []byte) error {
+			prefix := len(key) - len(historyKey(0, 0))
+			if data, err := tx.get(bucketHistory, key[prefix:]); err != nil || data == nil {
+				t.Errorf("index entry %s outlived its history record", key)
+			}
+			return nil
+		})
+	})
+
+	// A snapshot without the index, as taken before it was kept, gets it
+	// rebuilt on restore
+	f.mu.Lock()
+	err = f.state.update(func(tx stateTx) error { return tx.deleteAll(bucketResourceHistory) })
+	f.mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	snap, err := f.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &bufferSink{}
+	if err := snap.Persist(sink); err != nil {
+		t.Fatal(err)
+	}
+	snap.Release()
+
+	restored := newTestFSM(t)
+	if err := restored.Restore(io.NopCloser(&sink.Buffer)); err != nil {
+		t.Fatal(err)
+	}
+	for index := restored.historyFloor; index <= f.index; index++ {
+		checkAsOf(t, restored.FSM, ids, index)
+	}
+	p1, err = restored.GetPrinterAsOf("p1", f.index-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p1 == nil || p1.Model != "XL" {
+		t.Errorf("restored printer p1 as of %d is %+v, want the XL", f.index-1, p1)
+	}
+}
+
+// indexAsOfByScan finds the index IndexAsOf should return by reading the
+// whole history
+func indexAsOfByScan(t *testing.T, f *FSM, at time.Time) uint64 {
+	t.Helper()
+
+	index := f.AppliedIndex()
+	var last uint64
+	err := f.state.view(func(tx stateTx) error {
+		return tx.forEach(bucketHistory, func(key string, data []byte) error {
+			var record Change
+			if err := json.Unmarshal(data, &record); err != nil {
+				return err
+			}
+			if record.Time.After(at) {
+				index = last
+				return errStopIteration
+			}
+			last = record.Index
+			return nil
+		})
+	})
+	if err != nil && err != errStopIteration {
+		t.Fatal(err)
+	}
+	return index
+}
+
+func TestIndexAsOfMatchesScan(t *testing.T) {
+	f := newTestFSM(t)
+	start := f.now
+	for i := 0; i < 40; i++ {
+		id := fmt.Sprintf("p%d", i%7)
+		switch i % 4 {
+		case 0, 1:
+			// Some of these collide and fail, leaving entries without changes
+			f.apply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: id, Company: "Prusa", Model: "MK4"}})
+		case 2:
+			f.apply(&models.Command{Type: models.UpdatePrinter, PrinterID: id, Patch: json.RawMessage(`{"model":"XL"}`)})
+		case 3:
+			f.apply(&models.Command{Type: models.DeletePrinter, PrinterID: id})
+		}
+	}
+
+	for at := start.Add(-time.Second); !at.After(f.now.Add(time.Second)); at = at.Add(500 * time.Millisecond) {
+		got, err := f.IndexAsOf(at)
+		if err != nil {
+			t.Fatalf("failed to find the index as of %s: %v", at, err)
+		}
+		if want := indexAsOfByScan(t, f.FSM, at); got != want {
+			t.Errorf("index as of %s is %d, want %d", at.Format(time.RFC3339Nano), got, want)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package raft
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+// jobIndex maintains secondary indexes over print jobs so that lookups and
+// the filament availability check don't have to scan the whole job history.
+// It is owned by the FSM and only touched under the FSM's lock.
+type jobIndex struct {
+	byPrinter  map[string]map[string]struct{}
+	byFilament map[string]map[string]struct{}
+	byStatus   map[string]map[string]struct{}
+
+	// reservedGrams is the weight claimed by Queued and Running jobs, per
+	// filament
+	reservedGrams map[string]int
+}
+
+// newJobIndex creates an empty job index
+func newJobIndex() *jobIndex {
+	return &jobIndex{
+		byPrinter:     make(map[string]map[string]struct{}),
+		byFilament:    make(map[string]map[string]struct{}),
+		byStatus:      make(map[string]map[string]struct{}),
+		reservedGrams: make(map[string]int),
+	}
+}
+
+// isReserving reports whether a job in the given status holds filament
+func isReserving(status string) bool {
+	return status == "Queued" || status == "Running"
+}
+
+// add indexes a job
+func (x *jobIndex) add(job *models.PrintJob) {
+	addToSet(x.byPrinter, job.PrinterID, job.ID)
+	addToSet(x.byFilament, job.FilamentID, job.ID)
+	addToSet(x.byStatus, job.Status, job.ID)
+	if isReserving(job.Status) {
+		x.reservedGrams[job.FilamentID] += job.PrintWeightInGrams
+	}
+}
+
+// remove drops a job from the indexes. It must be called with the job as it
+// was indexed, so call it before mutating an indexed job and add it again
+// afterwards.
+func (x *jobIndex) remove(job *models.PrintJob) {
+	removeFromSet(x.byPrinter, job.PrinterID, job.ID)
+	removeFromSet(x.byFilament, job.FilamentID, job.ID)
+	removeFromSet(x.byStatus, job.Status, job.ID)
+	if isReserving(job.Status) {
+		x.reservedGrams[job.FilamentID] -= job.PrintWeightInGrams
+		if x.reservedGrams[job.FilamentID] == 0 {
+			delete(x.reservedGrams, job.FilamentID)
+		}
+	}
+}
+
+// rebuild reindexes all jobs from scratch
+func (x *jobIndex) rebuild(jobs []*models.PrintJob) {
+	*x = *newJobIndex()
+	for _, job := range jobs {
+		x.add(job)
+	}
+}
+
+func addToSet(sets map[string]map[string]struct{}, key, id string) {
+	set, ok := sets[key]
+	if !ok {
+		set = make(map[string]struct{})
+		sets[key] = set
+	}
+	set[id] = struct{}{}
+}
+
+func removeFromSet(sets map[string]map[string]struct{}, key, id string) {
+	set, ok := sets[key]
+	if !ok {
+		return
+	}
+	delete(set, id)
+	if len(set) == 0 {
+		delete(sets, key)
+	}
+}
+
+// jobsIn returns the jobs whose IDs are in set, ordered by ID like a scan
+// of the bucket. The caller must hold the FSM's read lock, so the state
+// matches the index.
+func (f *FSM) jobsIn(set map[string]struct{}) []*models.PrintJob {
+	ids := make([]string, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	jobs := make([]*models.PrintJob, 0, len(set))
+	f.view(func(tx stateTx) error {
+		for _, id := range ids {
+			job, err := getResource[models.PrintJob](tx, bucketPrintJobs, id)
+			if err != nil {
+				return err
+			}
+			if job != nil {
+				jobs = append(jobs, job)
+			}
+		}
+		return nil
+	})
+	return jobs
+}
+
+// GetPrintJobsByPrinter returns the print jobs scheduled on a printer
+func (f *FSM) GetPrintJobsByPrinter(printerID string) []*models.PrintJob {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.jobsIn(f.jobIndex.byPrinter[printerID])
+}
+
+// GetPrintJobsByFilament returns the print jobs using a filament
+func (f *FSM) GetPrintJobsByFilament(filamentID string) []*models.PrintJob {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.jobsIn(f.jobIndex.byFilament[filamentID])
+}
+
+// GetPrintJobsByStatus returns print jobs filtered by status
+func (f *FSM) GetPrintJobsByStatus(status string) []*models.PrintJob {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.jobsIn(f.jobIndex.byStatus[status])
+}
+
+// ReservedGrams returns the filament weight claimed by Queued and Running
+// jobs on a filament
+func (f *FSM) ReservedGrams(filamentID string) int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.jobIndex.reservedGrams[filamentID]
+}
+
+// FilamentReservations is how much of a filament Queued and Running jobs
+// have claimed, as the availability check for new jobs sees it
+type FilamentReservations struct {
+	FilamentID             string `json:"filament_id"`
+	RemainingWeightInGrams int    `json:"remaining_weight_in_grams"`
+	ReservedGrams          int    `json:"reserved_grams"`
+	// FreeGrams is what a new job can still claim
+	FreeGrams    int           `json:"free_grams"`
+	Reservations []Reservation `json:"reservations"`
+}
+
+// Reservation is the filament a Queued or Running job claims
+type Reservation struct {
+	JobID     string `json:"job_id"`
+	PrinterID string `json:"printer_id"`
+	Status    string `json:"status"`
+	Grams     int    `json:"grams"`
+}
+
+// GetFilamentReservations returns what the jobs on a filament have
+// reserved, oldest job first. The bool is false if the filament doesn't
+// exist.
+func (f *FSM) GetFilamentReservations(filamentID string) (*FilamentReservations, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	filament, exists := f.GetFilament(filamentID)
+	if !exists {
+		return nil, false
+	}
+
+	var jobs []*models.PrintJob
+	for _, job := range f.jobsIn(f.jobIndex.byFilament[filamentID]) {
+		if isReserving(job.Status) {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].CreatedIndex != jobs[j].CreatedIndex {
+			return jobs[i].CreatedIndex < jobs[j].CreatedIndex
+		}
+		return jobs[i].ID < jobs[j].ID
+	})
+
+	reserved := f.jobIndex.reservedGrams[filamentID]
+	r := &FilamentReservations{
+		FilamentID:             filamentID,
+		RemainingWeightInGrams: filament.RemainingWeightInGrams,
+		ReservedGrams:          reserved,
+		FreeGrams:              filament.RemainingWeightInGrams - reserved,
+		Reservations:           make([]Reservation, 0, len(jobs)),
+	}
+	for _, job := range jobs {
+		r.Reservations = append(r.Reservations, Reservation{
+			JobID:     job.ID,
+			PrinterID: job.PrinterID,
+			Status:    job.Status,
+			Grams:     job.PrintWeightInGrams,
+		})
+	}
+	return r, true
+}
+
+// CurrentPrintJob returns the job Running on a printer. Should several be
+// Running, the oldest is returned. The bool is false if none is.
+func (f *FSM) CurrentPrintJob(printerID string) (*models.PrintJob, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var current *models.PrintJob
+	for _, job := range f.jobsIn(f.jobIndex.byPrinter[printerID]) {
+		if job.Status != "Running" {
+			continue
+		}
+		if current == nil || job.CreatedIndex < current.CreatedIndex ||
+			(job.CreatedIndex == current.CreatedIndex && job.ID < current.ID) {
+			current = job
+		}
+	}
+	return current, current != nil
+}
+
+// reservedGrams returns the filament weight claimed by Queued and Running
+// jobs on a filament as of the writes made so far in tx. The index only
+// catches up once tx commits, so the jobs tx changed are accounted for here.
+func (f *FSM) reservedGrams(tx *fsmTx, filamentID string) int {
+	reserved := f.jobIndex.reservedGrams[filamentID]
+	for _, c := range tx.changes {
+		if c.bucket != bucketPrintJobs {
+			continue
+		}
+		var job models.PrintJob
+		if c.before != nil && json.Unmarshal(c.before, &job) == nil &&
+			job.FilamentID == filamentID && isReserving(job.Status) {
+			reserved -= job.PrintWeightInGrams
+		}
+		job = models.PrintJob{}
+		if c.after != nil && json.Unmarshal(c.after, &job) == nil &&
+			job.FilamentID == filamentID && isReserving(job.Status) {
+			reserved += job.PrintWeightInGrams
+		}
+	}
+	return reserved
+}
+
+// QueryPrintJobs returns the print jobs that pass filter. Equalities on
+// printer_id, filament_id or status at the top level of the filter narrow
+// the jobs down through the indexes before the rest of it is evaluated.
+func (f *FSM) QueryPrintJobs(filter *Filter) []*models.PrintJob {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	eq := filter.equalities()
+	var candidates map[string]struct{}
+	indexed := false
+	for _, idx := range []struct {
+		field string
+		sets  map[string]map[string]struct{}
+	}{
+		{"printer_id", f.jobIndex.byPrinter},
+		{"filament_id", f.jobIndex.byFilament},
+		{"status", f.jobIndex.byStatus},
+	} {
+		value, ok := eq[idx.field]
+		if !ok {
+			continue
+		}
+		if set := idx.sets[value]; !indexed || len(set) < len(candidates) {
+			candidates = set
+			indexed = true
+		}
+	}
+
+	var jobs []*models.PrintJob
+	if indexed {
+		jobs = f.jobsIn(candidates)
+	} else {
+		f.view(func(tx stateTx) error {
+			var err error
+			jobs, err = listResources[models.PrintJob](tx, bucketPrintJobs)
+			return err
+		})
+	}
+	return FilterResources(jobs, filter)
+}
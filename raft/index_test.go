@@ -0,0 +1,268 @@
+package raft
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+// bufferSink is a snapshot sink that keeps the snapshot in memory
+type bufferSink struct {
+	bytes.Buffer
+}
+
+func (*bufferSink) Close() error  { return nil }
+func (*bufferSink) ID() string    { return "buffer" }
+func (*bufferSink) Cancel() error { return nil }
+
+// checkJobIndex fails the test unless the job index and everything read
+// through it agree with a full scan of the print jobs
+func checkJobIndex(t *testing.T, f *FSM) {
+	t.Helper()
+
+	jobs := f.GetPrintJobs()
+	expected := newJobIndex()
+	expected.rebuild(jobs)
+	f.mu.RLock()
+	msg := f.jobIndex.diff(expected)
+	f.mu.RUnlock()
+	if msg != "" {
+		t.Fatal(msg)
+	}
+
+	for _, s := range []string{
+		"printer_id = p1",
+		"filament_id = f2",
+		"status = Queued",
+		"status = Running and filament_id = f1",
+		"printer_id = p0 and print_weight_in_grams > 20",
+	} {
+		filter, err := ParseFilter(ResourcePrintJobs, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := json.Marshal(f.QueryPrintJobs(filter))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := json.Marshal(FilterResources(jobs, filter))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("query %q returned %s, a full scan %s", s, got, want)
+		}
+	}
+
+	reserved := make(map[string]int)
+	for _, job := range jobs {
+		if isReserving(job.Status) {
+			reserved[job.FilamentID] += job.PrintWeightInGrams
+		}
+	}
+	for _, id := range []string{"f1", "f2"} {
+		if got := f.ReservedGrams(id); got != reserved[id] {
+			t.Errorf("filament %s has %d g reserved, a full scan says %d g", id, got, reserved[id])
+		}
+	}
+}
+
+func TestJobIndexMatchesFullScan(t *testing.T) {
+	f := newTestFSM(t)
+	for _, id := range []string{"p0", "p1", "p2"} {
+		f.mustApply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: id, Company: "Prusa", Model: "MK4"}})
+	}
+	for _, id := range []string{"f1", "f2"} {
+		f.mustApply(&models.Command{Type: models.AddFilament, Filament: &models.Filament{
+			ID: id, Type: "PLA", Color: "red", TotalWeightInGrams: 1000, RemainingWeightInGrams: 1000,
+		}})
+	}
+	checkJobIndex(t, f.FSM)
+
+	// Add
+	for i := 0; i < 12; i++ {
+		f.mustApply(&models.Command{Type: models.AddPrintJob, PrintJob: &models.PrintJob{
+			ID:                 fmt.Sprintf("job%02d", i),
+			PrinterID:          fmt.Sprintf("p%d", i%3),
+			FilamentID:         fmt.Sprintf("f%d", i%2+1),
+			Filepath:           "/prints/part.gcode",
+			PrintWeightInGrams: 10 + 5*i,
+		}})
+	}
+	checkJobIndex(t, f.FSM)
+
+	// Update through status changes and an upsert that moves a job
+	for _, u := range []struct{ id, status string }{
+		{"job00", "Running"}, {"job00", "Done"},
+		{"job01", "Running"},
+		{"job02", "Canceled"},
+		{"job03", "Running"}, {"job03", "Canceled"},
+	} {
+		f.mustApply(&models.Command{Type: models.UpdatePrintJob, JobID: u.id, NewStatus: u.status})
+	}
+	f.mustApply(&models.Command{Type: models.UpsertPrintJob, PrintJob: &models.PrintJob{
+		ID: "job02", PrinterID: "p2", FilamentID: "f1", Filepath: "/prints/other.gcode", PrintWeightInGrams: 40,
+	}})
+	checkJobIndex(t, f.FSM)
+
+	// Delete, one by one and by purging
+	f.mustApply(&models.Command{Type: models.DeletePrintJob, JobID: "job00"})
+	f.mustApply(&models.Command{Type: models.PurgePrintJobs, JobIDs: []string{"job02", "job03"}})
+	checkJobIndex(t, f.FSM)
+
+	// A failed command must leave the index alone
+	if _, ok := f.apply(&models.Command{Type: models.AddPrintJob, PrintJob: &models.PrintJob{
+		ID: "too-heavy", PrinterID: "p0", FilamentID: "f1", Filepath: "/prints/part.gcode", PrintWeightInGrams: 5000,
+	}}).(error); !ok {
+		t.Fatal("adding a job heavier than its filament succeeded")
+	}
+	checkJobIndex(t, f.FSM)
+
+	// Restore into an FSM with jobs of its own
+	snap, err := f.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &bufferSink{}
+	if err := snap.Persist(sink); err != nil {
+		t.Fatal(err)
+	}
+	snap.Release()
+
+	restored := newTestFSM(t)
+	restored.mustApply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: "p9", Company: "Bambu", Model: "X1"}})
+	restored.mustApply(&models.Command{Type: models.AddFilament, Filament: &models.Filament{
+		ID: "f1", Type: "PLA", Color: "red", TotalWeightInGrams: 1000, RemainingWeightInGrams: 1000,
+	}})
+	restored.mustApply(&models.Command{Type: models.AddPrintJob, PrintJob: &models.PrintJob{
+		ID: "stale", PrinterID: "p9", FilamentID: "f1", Filepath: "/prints/part.gcode", PrintWeightInGrams: 100,
+	}})
+	if err := restored.Restore(io.NopCloser(&sink.Buffer)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := restored.GetPrintJob("stale"); ok {
+		t.Fatal("restore kept a job that isn't in the snapshot")
+	}
+	checkJobIndex(t, restored.FSM)
+}
+
+// seedPrintJobs fills an FSM with n print jobs spread over 100 printers and
+// 50 filaments, bypassing Apply to keep the setup fast
+func seedPrintJobs(b *testing.B, f *FSM, n int) {
+	b.Helper()
+
+	statuses := []string{"Queued", "Running", "Done", "Canceled"}
+	err := f.state.update(func(tx stateTx) error {
+		put := func(bucket, id string, v interface{}) error {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			return tx.put(bucket, id, data)
+		}
+		for i := 0; i < 100; i++ {
+			id := fmt.Sprintf("p%d", i)
+			if err := put(bucketPrinters, id, &models.Printer{ID: id, Company: "Prusa", Model: "MK4"}); err != nil {
+				return err
+			}
+		}
+		for i := 0; i < 50; i++ {
+			id := fmt.Sprintf("f%d", i)
+			if err := put(bucketFilaments, id, &models.Filament{
+				ID: id, Type: "PLA", Color: "red", TotalWeightInGrams: 1000000, RemainingWeightInGrams: 1000000,
+			}); err != nil {
+				return err
+			}
+		}
+		for i := 0; i < n; i++ {
+			id := fmt.Sprintf("job%06d", i)
+			if err := put(bucketPrintJobs, id, &models.PrintJob{
+				ID:                 id,
+				PrinterID:          fmt.Sprintf("p%d", i%100),
+				FilamentID:         fmt.Sprintf("f%d", i%50),
+				Filepath:           "/prints/part.gcode",
+				PrintWeightInGrams: 10,
+				Status:             statuses[i%len(statuses)],
+				CreatedIndex:       uint64(i + 1),
+				Version:            uint64(i + 1),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := f.rebuild(); err != nil {
+		b.Fatal(err)
+	}
+}
+
+func BenchmarkQueryPrintJobs(b *testing.B) {
+	f := NewFSM()
+	seedPrintJobs(b, f, 100000)
+
+	for _, bm := range []struct{ name, filter string }{
+		{"Printer", "printer_id = p7"},
+		{"PrinterAndStatus", "printer_id = p8 and status = Queued"},
+		{"Status", "status = Running"},
+		{"Scan", "print_weight_in_grams > 10"},
+	} {
+		filter, err := ParseFilter(ResourcePrintJobs, bm.filter)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				f.QueryPrintJobs(filter)
+			}
+		})
+	}
+}
+
+func BenchmarkReservedGrams(b *testing.B) {
+	f := NewFSM()
+	seedPrintJobs(b, f, 100000)
+
+	b.Run("Committed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			f.ReservedGrams(fmt.Sprintf("f%d", i%50))
+		}
+	})
+
+	// The availability check inside an apply, with the transaction's own
+	// changes still to be accounted for
+	b.Run("InTransaction", func(b *testing.B) {
+		b.ReportAllocs()
+		f.state.update(func(stx stateTx) error {
+			tx := newFSMTx(stx, 100001, time.Time{})
+			for i := 0; i < 10; i++ {
+				id := fmt.Sprintf("job%06d", i)
+				job, err := getResource[models.PrintJob](stx, bucketPrintJobs, id)
+				if err != nil {
+					b.Fatal(err)
+				}
+				job.Status = "Canceled"
+				if err := tx.put(bucketPrintJobs, id, job); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				f.reservedGrams(tx, fmt.Sprintf("f%d", i%50))
+			}
+			b.StopTimer()
+			// Roll the cancellations back
+			return errStopIteration
+		})
+	})
+}
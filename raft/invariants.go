@@ -0,0 +1,300 @@
+package raft
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/devadigapratham/raft3d/api/models"
+	metrics "github.com/hashicorp/go-metrics/compat"
+)
+
+// Invariants the FSM state is expected to uphold
+const (
+	// InvariantFilamentWeight: a filament's remaining weight is never
+	// negative
+	InvariantFilamentWeight = "filament_weight_non_negative"
+	// InvariantFilamentReservation: Queued and Running jobs never claim more
+	// of a filament than remains of it
+	InvariantFilamentReservation = "filament_reservation_within_remaining"
+	// InvariantJobPrinter: every Queued or Running job's printer exists.
+	// Finished jobs outlive the printers they ran on.
+	InvariantJobPrinter = "job_printer_exists"
+	// InvariantJobFilament: every Queued or Running job's filament exists
+	InvariantJobFilament = "job_filament_exists"
+	// InvariantJobIndex: the job indexes agree with the stored jobs
+	InvariantJobIndex = "job_index_consistent"
+)
+
+// Violation is a broken invariant
+type Violation struct {
+	Invariant    string `json:"invariant"`
+	ResourceType string `json:"resource_type"`
+	ResourceID   string `json:"resource_id,omitempty"`
+	Message      string `json:"message"`
+}
+
+// InvariantReport is the outcome of checking the invariants
+type InvariantReport struct {
+	// AppliedIndex is the index the state was checked at
+	AppliedIndex uint64      `json:"applied_index"`
+	Violations   []Violation `json:"violations"`
+	// Repairs are the commands that would fix the violations they can.
+	// Violations without a corrective command need manual attention.
+	Repairs []*models.Command `json:"repairs"`
+}
+
+// InvariantChecks selects when the FSM checks its invariants by itself.
+// Every check reads the whole state, so these are meant for tests and
+// debugging rather than production.
+type InvariantChecks struct {
+	AfterApply   bool
+	AfterRestore bool
+
+	// OnViolation is called with the violations found by an automatic
+	// check. They are logged if it is nil.
+	OnViolation func(index uint64, violations []Violation)
+}
+
+// SetInvariantChecks sets when the FSM checks its invariants by itself
+func (f *FSM) SetInvariantChecks(checks InvariantChecks) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.invariantChecks = checks
+}
+
+// CheckInvariants checks the invariants against the current state and works
+// out the commands that would repair what is broken
+func (f *FSM) CheckInvariants() (*InvariantReport, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.checkInvariants()
+}
+
+// runInvariantChecks runs an automatic check and reports any violations.
+// The caller must hold the lock.
+func (f *FSM) runInvariantChecks() {
+	report, err := f.checkInvariants()
+	if err != nil {
+		log.Printf("Failed to check invariants at index %d: %v", f.appliedIndex, err)
+		return
+	}
+	if len(report.Violations) == 0 {
+		return
+	}
+
+	metrics.IncrCounter([]string{"raft3d", "fsm", "invariant_violations"}, float32(len(report.Violations)))
+	if f.invariantChecks.OnViolation != nil {
+		f.invariantChecks.OnViolation(report.AppliedIndex, report.Violations)
+		return
+	}
+	for _, v := range report.Violations {
+		log.Printf("Invariant %s violated at index %d by %s %s: %s",
+			v.Invariant, report.AppliedIndex, v.ResourceType, v.ResourceID, v.Message)
+	}
+}
+
+// checkInvariants does the work of CheckInvariants. The caller must hold the
+// lock.
+func (f *FSM) checkInvariants() (*InvariantReport, error) {
+	report := &InvariantReport{
+		AppliedIndex: f.appliedIndex,
+		Violations:   make([]Violation, 0),
+		Repairs:      make([]*models.Command, 0),
+	}
+
+	var printers []*models.Printer
+	var filaments []*models.Filament
+	var jobs []*models.PrintJob
+	err := f.state.view(func(tx stateTx) error {
+		var err error
+		if printers, err = listResources[models.Printer](tx, bucketPrinters); err != nil {
+			return err
+		}
+		if filaments, err = listResources[models.Filament](tx, bucketFilaments); err != nil {
+			return err
+		}
+		jobs, err = listResources[models.PrintJob](tx, bucketPrintJobs)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %v", err)
+	}
+
+	printerIDs := make(map[string]bool, len(printers))
+	for _, p := range printers {
+		printerIDs[p.ID] = true
+	}
+	filamentIDs := make(map[string]bool, len(filaments))
+	for _, fl := range filaments {
+		filamentIDs[fl.ID] = true
+	}
+
+	// Active jobs pointing at missing resources are canceled
+	canceled := make(map[string]bool)
+	for _, job := range jobs {
+		if !isReserving(job.Status) {
+			continue
+		}
+		broken := false
+		if !printerIDs[job.PrinterID] {
+			broken = true
+			report.Violations = append(report.Violations, Violation{
+				Invariant:    InvariantJobPrinter,
+				ResourceType: bucketPrintJobs,
+				ResourceID:   job.ID,
+				Message:      fmt.Sprintf("printer %s does not exist", job.PrinterID),
+			})
+		}
+		if !filamentIDs[job.FilamentID] {
+			broken = true
+			report.Violations = append(report.Violations, Violation{
+				Invariant:    InvariantJobFilament,
+				ResourceType: bucketPrintJobs,
+				ResourceID:   job.ID,
+				Message:      fmt.Sprintf("filament %s does not exist", job.FilamentID),
+			})
+		}
+		if broken {
+			canceled[job.ID] = true
+			report.Repairs = append(report.Repairs, cancelCommand(job))
+		}
+	}
+
+	// Filament weight and reservations, taking the cancellations above
+	// into account
+	for _, fl := range filaments {
+		remaining := fl.RemainingWeightInGrams
+		if remaining < 0 {
+			report.Violations = append(report.Violations, Violation{
+				Invariant:    InvariantFilamentWeight,
+				ResourceType: bucketFilaments,
+				ResourceID:   fl.ID,
+				Message:      fmt.Sprintf("remaining weight is %d g", remaining),
+			})
+			version := fl.Version
+			report.Repairs = append(report.Repairs, &models.Command{
+				Type:            models.AdjustFilamentWeight,
+				FilamentID:      fl.ID,
+				DeltaGrams:      -remaining,
+				Reason:          "remaining weight was negative",
+				ExpectedVersion: &version,
+			})
+			remaining = 0
+		}
+
+		// kept is what stays reserved once the planned cancellations are in
+		reserved, kept := 0, 0
+		var queued []*models.PrintJob
+		for _, job := range jobs {
+			if job.FilamentID != fl.ID || !isReserving(job.Status) {
+				continue
+			}
+			reserved += job.PrintWeightInGrams
+			if canceled[job.ID] {
+				continue
+			}
+			kept += job.PrintWeightInGrams
+			if job.Status == "Queued" {
+				queued = append(queued, job)
+			}
+		}
+		// Against a negative weight, which is repaired to zero above, any
+		// reservation at all is too much
+		if reserved <= remaining {
+			continue
+		}
+		report.Violations = append(report.Violations, Violation{
+			Invariant:    InvariantFilamentReservation,
+			ResourceType: bucketFilaments,
+			ResourceID:   fl.ID,
+			Message: fmt.Sprintf("jobs reserve %d g but only %d g remain",
+				reserved, fl.RemainingWeightInGrams),
+		})
+
+		// Cancel queued jobs, newest first, until the rest fit, so the
+		// oldest keep their reservations. Running jobs are left alone: they
+		// can still finish, and should the spool come up short the weight
+		// goes negative and is repaired above.
+		sort.Slice(queued, func(i, j int) bool {
+			if queued[i].CreatedIndex != queued[j].CreatedIndex {
+				return queued[i].CreatedIndex < queued[j].CreatedIndex
+			}
+			return queued[i].ID < queued[j].ID
+		})
+		for i := len(queued) - 1; i >= 0 && kept > remaining; i-- {
+			kept -= queued[i].PrintWeightInGrams
+			canceled[queued[i].ID] = true
+			report.Repairs = append(report.Repairs, cancelCommand(queued[i]))
+		}
+	}
+
+	// The indexes are local, so rather than being repaired through raft
+	// they are rebuilt on restart
+	expected := newJobIndex()
+	expected.rebuild(jobs)
+	if msg := f.jobIndex.diff(expected); msg != "" {
+		report.Violations = append(report.Violations, Violation{
+			Invariant:    InvariantJobIndex,
+			ResourceType: bucketPrintJobs,
+			Message:      msg,
+		})
+	}
+
+	return report, nil
+}
+
+// cancelCommand builds the command that cancels a job, unless it has
+// changed since it was checked
+func cancelCommand(job *models.PrintJob) *models.Command {
+	version := job.Version
+	return &models.Command{
+		Type:            models.UpdatePrintJob,
+		JobID:           job.ID,
+		NewStatus:       "Canceled",
+		ExpectedVersion: &version,
+	}
+}
+
+// diff describes the first difference between two job indexes, or returns
+// "" if they agree
+func (x *jobIndex) diff(y *jobIndex) string {
+	for _, sets := range []struct {
+		name string
+		x, y map[string]map[string]struct{}
+	}{
+		{"printer", x.byPrinter, y.byPrinter},
+		{"filament", x.byFilament, y.byFilament},
+		{"status", x.byStatus, y.byStatus},
+	} {
+		for key, set := range sets.y {
+			for id := range set {
+				if _, ok := sets.x[key][id]; !ok {
+					return fmt.Sprintf("job %s is missing from the %s index under %s", id, sets.name, key)
+				}
+			}
+		}
+		for key, set := range sets.x {
+			for id := range set {
+				if _, ok := sets.y[key][id]; !ok {
+					return fmt.Sprintf("job %s is in the %s index under %s but shouldn't be", id, sets.name, key)
+				}
+			}
+		}
+	}
+
+	// Missing entries count as nothing reserved
+	for id, grams := range y.reservedGrams {
+		if x.reservedGrams[id] != grams {
+			return fmt.Sprintf("filament %s has %d g reserved in the index, expected %d g", id, x.reservedGrams[id], grams)
+		}
+	}
+	for id, grams := range x.reservedGrams {
+		if y.reservedGrams[id] != grams {
+			return fmt.Sprintf("filament %s has %d g reserved in the index, expected %d g", id, grams, y.reservedGrams[id])
+		}
+	}
+	return ""
+}
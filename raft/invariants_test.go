@@ -0,0 +1,177 @@
+package raft
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+// breakState writes resources straight into the state, bypassing the
+// checks commands make, and rebuilds the indexes. Tests use it to set up
+// broken invariants.
+func breakState(t *testing.T, f *testFSM, resources map[string]interface{}) {
+	t.Helper()
+
+	f.skipInvariants = true
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	err := f.state.update(func(tx stateTx) error {
+		for key, v := range resources {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			var bucket string
+			switch v.(type) {
+			case *models.Printer:
+				bucket = bucketPrinters
+			case *models.Filament:
+				bucket = bucketFilaments
+			case *models.PrintJob:
+				bucket = bucketPrintJobs
+			}
+			if err := tx.put(bucket, key, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.rebuild(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRepairCancelsNewestQueuedJobsFirst(t *testing.T) {
+	f := newTestFSM(t)
+	f.mustApply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: "p1", Company: "Prusa", Model: "MK4"}})
+
+	// The jobs' IDs sort the other way round from their creation
+	job := func(id string, created uint64, status string) *models.PrintJob {
+		return &models.PrintJob{
+			ID: id, PrinterID: "p1", FilamentID: "f1", Filepath: "/prints/part.gcode", PrintWeightInGrams: 40,
+			Status: status, Version: created, CreatedIndex: created,
+		}
+	}
+	breakState(t, f, map[string]interface{}{
+		"f1": &models.Filament{ID: "f1", Type: "PLA", Color: "red", TotalWeightInGrams: 1000, RemainingWeightInGrams: 100, Version: 2, CreatedIndex: 2},
+		"a":  job("a", 9, "Queued"),
+		"b":  job("b", 8, "Queued"),
+		"c":  job("c", 7, "Queued"),
+		"d":  job("d", 10, "Running"),
+	})
+
+	report, err := f.CheckInvariants()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var canceled []string
+	for _, repair := range report.Repairs {
+		canceled = append(canceled, repair.JobID)
+	}
+	// 160 g are reserved of 100 g; the Running job stays and the newest
+	// Queued ones go until the rest fit
+	if len(canceled) != 2 || canceled[0] != "a" || canceled[1] != "b" {
+		t.Fatalf("repairs cancel %v, want a then b", canceled)
+	}
+
+	for _, repair := range report.Repairs {
+		f.mustApply(repair)
+	}
+	report, err = f.CheckInvariants()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Violations) != 0 {
+		t.Errorf("repairs left %+v", report.Violations)
+	}
+	if c, _ := f.GetPrintJob("c"); c.Status != "Queued" {
+		t.Errorf("oldest job is %s, want it to keep its reservation", c.Status)
+	}
+}
+
+func TestFinishingJobReportsShortfall(t *testing.T) {
+	f := newTestFSM(t)
+	f.mustApply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: "p1", Company: "Prusa", Model: "MK4"}})
+	breakState(t, f, map[string]interface{}{
+		"f1": &models.Filament{ID: "f1", Type: "PLA", Color: "red", TotalWeightInGrams: 1000, RemainingWeightInGrams: 30, Version: 2, CreatedIndex: 2},
+		"j1": &models.PrintJob{
+			ID: "j1", PrinterID: "p1", FilamentID: "f1", Filepath: "/prints/part.gcode", PrintWeightInGrams: 50,
+			Status: "Running", Version: 3, CreatedIndex: 3,
+		},
+	})
+
+	// The print is done whatever the spool's record says
+	f.mustApply(&models.Command{Type: models.UpdatePrintJob, JobID: "j1", NewStatus: "Done"})
+	if fl, _ := f.GetFilament("f1"); fl.RemainingWeightInGrams != -20 {
+		t.Errorf("filament has %d g left, want -20 g", fl.RemainingWeightInGrams)
+	}
+
+	report, err := f.CheckInvariants()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Violations) != 1 || report.Violations[0].Invariant != InvariantFilamentWeight {
+		t.Fatalf("report has %+v, want the negative weight", report.Violations)
+	}
+	if len(report.Repairs) != 1 || report.Repairs[0].Type != models.AdjustFilamentWeight || report.Repairs[0].DeltaGrams != 20 {
+		t.Fatalf("report repairs with %+v, want an adjustment of 20 g", report.Repairs)
+	}
+	f.skipInvariants = false
+	f.mustApply(report.Repairs[0])
+	if fl, _ := f.GetFilament("f1"); fl.RemainingWeightInGrams != 0 {
+		t.Errorf("filament has %d g left after the repair, want 0 g", fl.RemainingWeightInGrams)
+	}
+}
+
+func TestNegativeWeightAlsoBreaksReservations(t *testing.T) {
+	f := newTestFSM(t)
+	f.mustApply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: "p1", Company: "Prusa", Model: "MK4"}})
+	breakState(t, f, map[string]interface{}{
+		"f1": &models.Filament{ID: "f1", Type: "PLA", Color: "red", TotalWeightInGrams: 1000, RemainingWeightInGrams: -10, Version: 2, CreatedIndex: 2},
+		"j1": &models.PrintJob{
+			ID: "j1", PrinterID: "p1", FilamentID: "f1", Filepath: "/prints/part.gcode", PrintWeightInGrams: 5,
+			Status: "Queued", Version: 3, CreatedIndex: 3,
+		},
+	})
+
+	report, err := f.CheckInvariants()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var invariants []string
+	for _, v := range report.Violations {
+		invariants = append(invariants, v.Invariant)
+	}
+	if len(invariants) != 2 || invariants[0] != InvariantFilamentWeight || invariants[1] != InvariantFilamentReservation {
+		t.Fatalf("report has %v, want the weight and the reservation", invariants)
+	}
+	for _, repair := range report.Repairs {
+		f.mustApply(repair)
+	}
+	if report, err = f.CheckInvariants(); err != nil || len(report.Violations) != 0 {
+		t.Errorf("repairs left %+v, %v", report, err)
+	}
+}
+
+func TestRepairAdjustsNegativeWeightToZero(t *testing.T) {
+	f := newTestFSM(t)
+	breakState(t, f, map[string]interface{}{
+		"f1": &models.Filament{ID: "f1", Type: "PLA", Color: "red", TotalWeightInGrams: 1000, RemainingWeightInGrams: -25, Version: 1, CreatedIndex: 1},
+	})
+
+	report, err := f.CheckInvariants()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Repairs) != 1 || report.Repairs[0].Type != models.AdjustFilamentWeight || report.Repairs[0].DeltaGrams != 25 {
+		t.Fatalf("repairs are %+v, want an adjustment of 25 g", report.Repairs)
+	}
+	f.mustApply(report.Repairs[0])
+	if fl, _ := f.GetFilament("f1"); fl.RemainingWeightInGrams != 0 {
+		t.Errorf("filament has %d g left, want 0 g", fl.RemainingWeightInGrams)
+	}
+}
@@ -0,0 +1,83 @@
+package raft
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+	"go.etcd.io/bbolt"
+)
+
+// LogReader provides read-only access to the Raft log and snapshots of a
+// node that is not running
+type LogReader struct {
+	logs      *raftboltdb.BoltStore
+	snapshots *raft.FileSnapshotStore
+}
+
+// OpenLogReader opens the Raft log and snapshot store found in raftDir
+func OpenLogReader(raftDir string) (*LogReader, error) {
+	logStorePath := filepath.Join(raftDir, logStoreFile)
+	if _, err := os.Stat(logStorePath); err != nil {
+		return nil, fmt.Errorf("failed to find Raft log: %v", err)
+	}
+
+	// Open the log read-only so a running node is never disturbed. BoltDB
+	// holds an exclusive lock while the node is up, so give up quickly
+	// rather than hang.
+	logs, err := raftboltdb.New(raftboltdb.Options{
+		Path: logStorePath,
+		BoltOptions: &bbolt.Options{
+			ReadOnly: true,
+			Timeout:  time.Second,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Raft log (is the node still running?): %v", err)
+	}
+
+	snapshots, err := raft.NewFileSnapshotStore(raftDir, snapshotsRetained, io.Discard)
+	if err != nil {
+		logs.Close()
+		return nil, fmt.Errorf("failed to open snapshot store: %v", err)
+	}
+
+	return &LogReader{
+		logs:      logs,
+		snapshots: snapshots,
+	}, nil
+}
+
+// FirstIndex returns the first index still present in the log
+func (r *LogReader) FirstIndex() (uint64, error) {
+	return r.logs.FirstIndex()
+}
+
+// LastIndex returns the last index present in the log
+func (r *LogReader) LastIndex() (uint64, error) {
+	return r.logs.LastIndex()
+}
+
+// GetLog reads the log entry at the given index
+func (r *LogReader) GetLog(index uint64, log *raft.Log) error {
+	return r.logs.GetLog(index, log)
+}
+
+// Snapshots lists the available snapshots, newest first
+func (r *LogReader) Snapshots() ([]*raft.SnapshotMeta, error) {
+	return r.snapshots.List()
+}
+
+// OpenSnapshot opens the snapshot with the given ID for reading
+func (r *LogReader) OpenSnapshot(id string) (*raft.SnapshotMeta, io.ReadCloser, error) {
+	return r.snapshots.Open(id)
+}
+
+// Close releases the underlying log store
+func (r *LogReader) Close() error {
+	return r.logs.Close()
+}
@@ -12,8 +12,23 @@ import (
 	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
 )
 
+const (
+	// logStoreFile is the BoltDB file holding the Raft log
+	logStoreFile = "raft-log.db"
+	// stableStoreFile is the BoltDB file holding Raft's stable state
+	stableStoreFile = "raft-stable.db"
+	// fsmStateFile is the BoltDB file holding the FSM state when the bolt
+	// state backend is used
+	fsmStateFile = "fsm.db"
+	// jobArchiveFile is the JSON lines file purged print jobs are archived to
+	jobArchiveFile = "job-archive.jsonl"
+	// snapshotsRetained is the number of snapshots kept on disk
+	snapshotsRetained = 3
+)
+
 // Node represents a node in the Raft cluster
 type Node struct {
+	id        raft.ServerID
 	raft      *raft.Raft
 	fsm       *FSM
 	transport *raft.NetworkTransport
@@ -26,12 +41,53 @@ type Config struct {
 	RaftDir   string
 	Bootstrap bool
 	Peers     []string
+
+	// StateBackend selects where the FSM keeps its state, StateBackendMemory
+	// (the default) or StateBackendBolt
+	StateBackend string
+
+	// SnapshotCompression is the compression used for new snapshots, one
+	// of the SnapshotCompression constants (default none)
+	SnapshotCompression string
+
+	// HistoryRetention bounds the history kept for as-of queries
+	HistoryRetention HistoryRetention
+
+	// AuditRetention bounds the audit log
+	AuditRetention AuditRetention
+
+	// InvariantChecks selects when the FSM checks its invariants by itself
+	InvariantChecks InvariantChecks
 }
 
 // NewNode creates a new Raft node
 func NewNode(config *Config) (*Node, error) {
 	// Create the FSM
-	fsm := NewFSM()
+	var fsm *FSM
+	switch config.StateBackend {
+	case "", StateBackendMemory:
+		fsm = NewFSM()
+	case StateBackendBolt:
+		var err error
+		fsm, err = NewBoltFSM(filepath.Join(config.RaftDir, fsmStateFile))
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown state backend: %s", config.StateBackend)
+	}
+	fsm.SetHistoryRetention(config.HistoryRetention)
+	fsm.SetAuditRetention(config.AuditRetention)
+	fsm.SetInvariantChecks(config.InvariantChecks)
+	if err := fsm.OpenJobArchive(filepath.Join(config.RaftDir, jobArchiveFile)); err != nil {
+		fsm.Close()
+		return nil, err
+	}
+	if config.SnapshotCompression != "" {
+		if err := fsm.SetSnapshotCompression(config.SnapshotCompression); err != nil {
+			return nil, err
+		}
+	}
 
 	// Create Raft configuration
 	raftConfig := raft.DefaultConfig()
@@ -40,14 +96,14 @@ func NewNode(config *Config) (*Node, error) {
 	raftConfig.SnapshotThreshold = 1024
 
 	// Create the BoltDB store for logs
-	logStorePath := filepath.Join(config.RaftDir, "raft-log.db")
+	logStorePath := filepath.Join(config.RaftDir, logStoreFile)
 	logStore, err := raftboltdb.NewBoltStore(logStorePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create BoltDB log store: %v", err)
 	}
 
 	// Create the stable store for data
-	stableStorePath := filepath.Join(config.RaftDir, "raft-stable.db")
+	stableStorePath := filepath.Join(config.RaftDir, stableStoreFile)
 	stableStore, err := raftboltdb.NewBoltStore(stableStorePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create BoltDB stable store: %v", err)
@@ -55,7 +111,7 @@ func NewNode(config *Config) (*Node, error) {
 
 	// Create the snapshot store
 	snapshotStore, err := raft.NewFileSnapshotStore(
-		config.RaftDir, 3, os.Stderr)
+		config.RaftDir, snapshotsRetained, os.Stderr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create snapshot store: %v", err)
 	}
@@ -113,6 +169,7 @@ func NewNode(config *Config) (*Node, error) {
 	}
 
 	return &Node{
+		id:        raftConfig.LocalID,
 		raft:      r,
 		fsm:       fsm,
 		transport: transport,
@@ -121,23 +178,33 @@ func NewNode(config *Config) (*Node, error) {
 
 // Apply applies a command to the Raft log
 func (n *Node) Apply(cmd *models.Command) error {
+	_, err := n.ApplyWithResult(cmd)
+	return err
+}
+
+// ApplyWithResult applies a command to the Raft log and returns what the FSM
+// made of it, such as the *TransactionResult of a transaction. Errors from
+// the FSM are returned as they are, so callers can tell their kinds apart
+// with errors.Is and errors.As. Failing to commit the command is
+// ErrUnavailable.
+func (n *Node) ApplyWithResult(cmd *models.Command) (interface{}, error) {
 	data, err := cmd.Marshal()
 	if err != nil {
-		return fmt.Errorf("failed to marshal command: %v", err)
+		return nil, errorf(ErrValidation, "failed to marshal command: %v", err)
 	}
 
 	// Apply the command to the Raft log
 	future := n.raft.Apply(data, 5*time.Second)
 	if err := future.Error(); err != nil {
-		return fmt.Errorf("failed to apply command to Raft log: %v", err)
+		return nil, errorf(ErrUnavailable, "failed to apply command to Raft log: %v", err)
 	}
 
 	// Check for application error
 	if appErr, ok := future.Response().(error); ok && appErr != nil {
-		return fmt.Errorf("command application failed: %v", appErr)
+		return nil, appErr
 	}
 
-	return nil
+	return future.Response(), nil
 }
 
 // GetFSM returns the FSM
@@ -170,7 +237,14 @@ func (n *Node) Shutdown() error {
 	// Shutdown Raft
 	if n.raft != nil {
 		future := n.raft.Shutdown()
-		return future.Error()
+		if err := future.Error(); err != nil {
+			return err
+		}
+	}
+
+	// Close the FSM state once nothing applies to it anymore
+	if n.fsm != nil {
+		return n.fsm.Close()
 	}
 
 	return nil
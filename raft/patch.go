@@ -0,0 +1,45 @@
+package raft
+
+import (
+	"encoding/json"
+)
+
+// patchResource overlays the top-level fields of a JSON patch on a resource
+// and returns the result. Fields the resource doesn't have and the fields in
+// protected can't be patched; id, version and created_index never can.
+func patchResource[T any](v *T, patch json.RawMessage, protected ...string) (*T, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &fields); err != nil {
+		return nil, errorf(ErrValidation, "invalid patch: %v", err)
+	}
+
+	current, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var merged map[string]json.RawMessage
+	if err := json.Unmarshal(current, &merged); err != nil {
+		return nil, err
+	}
+
+	protected = append(protected, "id", "version", "created_index")
+	for name, value := range fields {
+		if containsBucket(protected, name) {
+			return nil, errorf(ErrValidation, "field %s can't be changed", name)
+		}
+		if _, ok := merged[name]; !ok {
+			return nil, errorf(ErrValidation, "unknown field %s", name)
+		}
+		merged[name] = value
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	var patched T
+	if err := json.Unmarshal(data, &patched); err != nil {
+		return nil, errorf(ErrValidation, "invalid patch: %v", err)
+	}
+	return &patched, nil
+}
@@ -0,0 +1,123 @@
+package raft
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+// CascadeCancel makes deleting a resource cancel the jobs still using it
+const CascadeCancel = "cancel"
+
+// ErrInUse is returned when deleting a resource that Queued or Running jobs
+// still use
+var ErrInUse = errors.New("in use")
+
+// applyUpdatePrinter patches an existing printer
+func applyUpdatePrinter(tx *fsmTx, cmd *models.Command) error {
+	printer, err := getResource[models.Printer](tx.tx, bucketPrinters, cmd.PrinterID)
+	if err != nil {
+		return err
+	}
+	if printer == nil {
+		return errorf(ErrNotFound, "printer with ID %s does not exist", cmd.PrinterID)
+	}
+
+	patched, err := patchResource(printer, cmd.Patch)
+	if err != nil {
+		return err
+	}
+	return tx.put(bucketPrinters, patched.ID, patched)
+}
+
+// applyDeletePrinter deletes a printer. While Queued or Running jobs are
+// scheduled on it the delete fails, unless the command cascades and cancels
+// them along with it.
+func (f *FSM) applyDeletePrinter(tx *fsmTx, cmd *models.Command) error {
+	printer, err := getResource[models.Printer](tx.tx, bucketPrinters, cmd.PrinterID)
+	if err != nil {
+		return err
+	}
+	if printer == nil {
+		return errorf(ErrNotFound, "printer with ID %s does not exist", cmd.PrinterID)
+	}
+
+	active, err := f.txJobs(tx, f.jobIndex.byPrinter[printer.ID], func(job *models.PrintJob) bool {
+		return job.PrinterID == printer.ID && isReserving(job.Status)
+	})
+	if err != nil {
+		return err
+	}
+	if err := f.cascade(tx, cmd, active); err != nil {
+		return fmt.Errorf("printer with ID %s: %w", printer.ID, err)
+	}
+
+	return tx.delete(bucketPrinters, printer.ID)
+}
+
+// cascade deals with the active jobs of a resource being deleted by cmd:
+// without a cascade they block the delete, with CascadeCancel they are
+// canceled on behalf of whoever deletes it
+func (f *FSM) cascade(tx *fsmTx, cmd *models.Command, active []*models.PrintJob) error {
+	switch cmd.Cascade {
+	case "":
+		if len(active) > 0 {
+			return fmt.Errorf("%w: %d Queued or Running print jobs", ErrInUse, len(active))
+		}
+		return nil
+
+	case CascadeCancel:
+		for _, job := range active {
+			err := f.applyUpdatePrintJob(tx, &models.Command{
+				Type:      models.UpdatePrintJob,
+				JobID:     job.ID,
+				NewStatus: "Canceled",
+				Reason:    cmd.Reason,
+				Actor:     cmd.Actor,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return errorf(ErrValidation, "unknown cascade: %s", cmd.Cascade)
+	}
+}
+
+// txJobs returns the jobs that match, as of the writes made so far in tx.
+// set is the index entry expected to hold them; the index only catches up
+// once tx commits, so the jobs tx changed are checked separately. Jobs are
+// ordered by ID.
+func (f *FSM) txJobs(tx *fsmTx, set map[string]struct{}, match func(job *models.PrintJob) bool) ([]*models.PrintJob, error) {
+	ids := make(map[string]struct{}, len(set))
+	for id := range set {
+		ids[id] = struct{}{}
+	}
+	for _, c := range tx.changes {
+		if c.bucket == bucketPrintJobs {
+			ids[c.id] = struct{}{}
+		}
+	}
+
+	sorted := make([]string, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Strings(sorted)
+
+	var jobs []*models.PrintJob
+	for _, id := range sorted {
+		job, err := getResource[models.PrintJob](tx.tx, bucketPrintJobs, id)
+		if err != nil {
+			return nil, err
+		}
+		if job != nil && match(job) {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
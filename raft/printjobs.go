@@ -0,0 +1,61 @@
+package raft
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+// ErrJobNotFinished is returned when deleting a print job that is still
+// Queued or Running
+var ErrJobNotFinished = errors.New("print job not finished")
+
+// applyDeletePrintJob deletes a Done or Canceled print job. Active jobs
+// have to be canceled first.
+func applyDeletePrintJob(tx *fsmTx, cmd *models.Command) error {
+	job, err := getResource[models.PrintJob](tx.tx, bucketPrintJobs, cmd.JobID)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return errorf(ErrNotFound, "print job with ID %s does not exist", cmd.JobID)
+	}
+	if !models.IsTerminalPrintJobStatus(job.Status) {
+		return fmt.Errorf("%w: print job %s is %s", ErrJobNotFinished, job.ID, job.Status)
+	}
+	return tx.delete(bucketPrintJobs, job.ID)
+}
+
+// newTransition records a print job moving from one status to another
+// through cmd
+func newTransition(tx *fsmTx, cmd *models.Command, from, to string) models.StatusTransition {
+	return models.StatusTransition{
+		From:   from,
+		To:     to,
+		At:     tx.appendedAt,
+		Actor:  cmd.Actor,
+		Reason: cmd.Reason,
+	}
+}
+
+// prepareImportedPrintJob checks the status an imported print job comes
+// with. A job without a history gets one starting at its import, and a
+// finished one without a finish time finishes at its import.
+func prepareImportedPrintJob(tx *fsmTx, cmd *models.Command) error {
+	job := cmd.PrintJob
+	if !models.IsValidPrintJobStatus(job.Status) {
+		return errorf(ErrValidation, "invalid status %q for print job %s", job.Status, job.ID)
+	}
+
+	if !models.IsTerminalPrintJobStatus(job.Status) {
+		job.FinishedAt = nil
+	} else if job.FinishedAt == nil {
+		finishedAt := tx.appendedAt
+		job.FinishedAt = &finishedAt
+	}
+	if len(job.Transitions) == 0 {
+		job.Transitions = []models.StatusTransition{newTransition(tx, cmd, "", job.Status)}
+	}
+	return nil
+}
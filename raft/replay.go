@@ -0,0 +1,123 @@
+package raft
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/raft"
+)
+
+// ReplayResult holds the state rebuilt by Replay
+type ReplayResult struct {
+	FSM *FSM
+	// SnapshotIndex is the index of the snapshot replay started from, or 0
+	SnapshotIndex uint64
+	// LastIndex is the index of the last log entry that was replayed
+	LastIndex uint64
+}
+
+// Replay rebuilds the FSM as it stood right after the entry at untilIndex was
+// applied. It restores the newest snapshot at or before untilIndex and then
+// applies the remaining log entries in order. An untilIndex of 0 replays the
+// whole log.
+func Replay(r *LogReader, untilIndex uint64) (*ReplayResult, error) {
+	firstIndex, err := r.FirstIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read first log index: %v", err)
+	}
+	lastIndex, err := r.LastIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read last log index: %v", err)
+	}
+	if untilIndex == 0 || untilIndex > lastIndex {
+		untilIndex = lastIndex
+	}
+
+	result := &ReplayResult{FSM: NewFSM()}
+
+	// Find the newest snapshot that doesn't go past the target
+	snapshots, err := r.Snapshots()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %v", err)
+	}
+	for _, meta := range snapshots {
+		if meta.Index > untilIndex {
+			continue
+		}
+
+		_, rc, err := r.OpenSnapshot(meta.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open snapshot %s: %v", meta.ID, err)
+		}
+		if err := result.FSM.Restore(rc); err != nil {
+			return nil, fmt.Errorf("failed to restore snapshot %s: %v", meta.ID, err)
+		}
+		result.SnapshotIndex = meta.Index
+		result.LastIndex = meta.Index
+		break
+	}
+
+	// Without a snapshot the log must still reach back to the first entry
+	start := result.SnapshotIndex + 1
+	if lastIndex == 0 || untilIndex < start {
+		return result, nil
+	}
+	if firstIndex > start {
+		return nil, fmt.Errorf("log has been compacted up to index %d and no snapshot covers index %d",
+			firstIndex-1, start)
+	}
+
+	for index := start; index <= untilIndex; index++ {
+		var entry raft.Log
+		if err := r.GetLog(index, &entry); err != nil {
+			return nil, fmt.Errorf("failed to read log entry %d: %v", index, err)
+		}
+
+		// Only commands touch the FSM; configuration changes and no-ops
+		// are skipped. Application errors are part of history, exactly as
+		// they were when the entry was first applied.
+		if entry.Type == raft.LogCommand {
+			result.FSM.Apply(&entry)
+		}
+		result.LastIndex = index
+	}
+
+	return result, nil
+}
+
+// ReplayUntilTime rebuilds the FSM as it stood at time t. It replays the log
+// up to the entry before the first one the leader appended after t.
+func ReplayUntilTime(r *LogReader, t time.Time) (*ReplayResult, error) {
+	firstIndex, err := r.FirstIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read first log index: %v", err)
+	}
+	lastIndex, err := r.LastIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read last log index: %v", err)
+	}
+	if lastIndex == 0 {
+		return Replay(r, 0)
+	}
+
+	for index := firstIndex; index <= lastIndex; index++ {
+		var entry raft.Log
+		if err := r.GetLog(index, &entry); err != nil {
+			return nil, fmt.Errorf("failed to read log entry %d: %v", index, err)
+		}
+		if !entry.AppendedAt.After(t) {
+			continue
+		}
+
+		switch {
+		case index > firstIndex:
+			return Replay(r, index-1)
+		case firstIndex == 1:
+			// Nothing had happened yet
+			return &ReplayResult{FSM: NewFSM()}, nil
+		}
+		return nil, fmt.Errorf("log has been compacted up to index %d, which was appended after %s",
+			firstIndex-1, t.Format(time.RFC3339))
+	}
+	return Replay(r, lastIndex)
+}
@@ -0,0 +1,187 @@
+package raft
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+)
+
+// replayStart is when the first entry of a test log was appended
+var replayStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// writeReplayLog writes a Raft directory whose log creates printers p1 to
+// p5 at indexes 1 to 5, a second apart, with a snapshot taken at index 3.
+// The log is compacted up to compactedTo.
+func writeReplayLog(t *testing.T, compactedTo uint64) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	logs, err := raftboltdb.NewBoltStore(filepath.Join(dir, logStoreFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logs.Close()
+	snapshots, err := raft.NewFileSnapshotStore(dir, snapshotsRetained, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := NewFSM()
+	for index := uint64(1); index <= 5; index++ {
+		cmd := &models.Command{Type: models.AddPrinter, Printer: &models.Printer{
+			ID: fmt.Sprintf("p%d", index), Company: "Prusa", Model: "MK4",
+		}}
+		data, err := cmd.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry := &raft.Log{
+			Index: index, Term: 1, Type: raft.LogCommand, Data: data,
+			AppendedAt: replayStart.Add(time.Duration(index-1) * time.Second),
+		}
+		if err := logs.StoreLog(entry); err != nil {
+			t.Fatal(err)
+		}
+		if err, ok := f.Apply(entry).(error); ok {
+			t.Fatal(err)
+		}
+
+		if index == 3 {
+			sink, err := snapshots.Create(raft.SnapshotVersionMax, index, 1, raft.Configuration{}, 1, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			snapshot, err := f.Snapshot()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := snapshot.Persist(sink); err != nil {
+				t.Fatal(err)
+			}
+			snapshot.Release()
+		}
+	}
+	if compactedTo > 0 {
+		if err := logs.DeleteRange(1, compactedTo); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// openReplayLog opens a Raft directory written by writeReplayLog
+func openReplayLog(t *testing.T, dir string) *LogReader {
+	t.Helper()
+
+	r, err := OpenLogReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+// checkReplayed fails the test unless the replay stopped at lastIndex,
+// having created the printers up to it
+func checkReplayed(t *testing.T, result *ReplayResult, snapshotIndex, lastIndex uint64) {
+	t.Helper()
+
+	if result.SnapshotIndex != snapshotIndex || result.LastIndex != lastIndex {
+		t.Errorf("replay started from snapshot %d and stopped at %d, want %d and %d",
+			result.SnapshotIndex, result.LastIndex, snapshotIndex, lastIndex)
+	}
+	for index := uint64(1); index <= 5; index++ {
+		_, exists := result.FSM.GetPrinter(fmt.Sprintf("p%d", index))
+		if exists != (index <= lastIndex) {
+			t.Errorf("printer p%d exists: %v after replaying to %d", index, exists, lastIndex)
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+	r := openReplayLog(t, writeReplayLog(t, 0))
+
+	cases := []struct {
+		name          string
+		untilIndex    uint64
+		snapshotIndex uint64
+		lastIndex     uint64
+	}{
+		{"whole log", 0, 3, 5},
+		{"snapshot and log", 4, 3, 4},
+		{"at the snapshot", 3, 3, 3},
+		{"before the snapshot", 2, 0, 2},
+		{"past the end", 99, 3, 5},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Replay(r, tc.untilIndex)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkReplayed(t, result, tc.snapshotIndex, tc.lastIndex)
+		})
+	}
+}
+
+func TestReplayCompactedLog(t *testing.T) {
+	r := openReplayLog(t, writeReplayLog(t, 3))
+
+	result, err := Replay(r, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReplayed(t, result, 3, 4)
+
+	// Nothing covers the entries before the snapshot any more
+	if _, err := Replay(r, 2); err == nil {
+		t.Error("replay to index 2 of a log compacted up to 3 succeeded")
+	}
+}
+
+func TestReplayUntilTime(t *testing.T) {
+	r := openReplayLog(t, writeReplayLog(t, 0))
+
+	cases := []struct {
+		name      string
+		at        time.Time
+		lastIndex uint64
+	}{
+		{"before the first entry", replayStart.Add(-time.Nanosecond), 0},
+		{"at the first entry", replayStart, 1},
+		{"before an entry", replayStart.Add(3*time.Second - time.Nanosecond), 3},
+		{"at an entry", replayStart.Add(3 * time.Second), 4},
+		{"after an entry", replayStart.Add(3*time.Second + time.Nanosecond), 4},
+		{"after the last entry", replayStart.Add(time.Hour), 5},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ReplayUntilTime(r, tc.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			snapshotIndex := uint64(0)
+			if tc.lastIndex >= 3 {
+				snapshotIndex = 3
+			}
+			checkReplayed(t, result, snapshotIndex, tc.lastIndex)
+		})
+	}
+
+	// Once the entries around the time are compacted it can't be told
+	// which of them came before it
+	r = openReplayLog(t, writeReplayLog(t, 3))
+	if _, err := ReplayUntilTime(r, replayStart.Add(time.Second)); err == nil {
+		t.Error("replay until a time in the compacted part of the log succeeded")
+	}
+	result, err := ReplayUntilTime(r, replayStart.Add(3*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReplayed(t, result, 3, 4)
+}
@@ -0,0 +1,130 @@
+package raft
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+// purgeBatchSize is the number of jobs purged by a single log entry
+const purgeBatchSize = 500
+
+// JobRetention decides how long Done and Canceled jobs are kept. A job is
+// purged once neither limit keeps it; zero limits keep nothing, and with
+// both zero nothing is ever purged.
+type JobRetention struct {
+	// MaxAge keeps jobs that finished less than this long ago
+	MaxAge time.Duration
+	// KeepPerPrinter keeps the last jobs finished on each printer
+	KeepPerPrinter int
+}
+
+// enabled reports whether the retention purges anything
+func (r JobRetention) enabled() bool {
+	return r.MaxAge != 0 || r.KeepPerPrinter != 0
+}
+
+// ExpiredPrintJobs returns the IDs of the finished jobs the retention no
+// longer keeps at time now. Jobs finished before finish times were recorded
+// are only ever purged by KeepPerPrinter.
+func (f *FSM) ExpiredPrintJobs(retention JobRetention, now time.Time) []string {
+	if !retention.enabled() {
+		return nil
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	// Newest first per printer. A finished job doesn't change anymore, so
+	// its version is the index it finished at.
+	byPrinter := make(map[string][]*models.PrintJob)
+	for _, status := range []string{"Done", "Canceled"} {
+		for _, job := range f.jobsIn(f.jobIndex.byStatus[status]) {
+			byPrinter[job.PrinterID] = append(byPrinter[job.PrinterID], job)
+		}
+	}
+
+	var expired []string
+	for _, jobs := range byPrinter {
+		sort.Slice(jobs, func(i, j int) bool {
+			return jobs[i].Version > jobs[j].Version
+		})
+		for rank, job := range jobs {
+			keptByCount := retention.KeepPerPrinter != 0 && rank < retention.KeepPerPrinter
+			keptByAge := retention.MaxAge != 0 &&
+				(job.FinishedAt == nil || now.Sub(*job.FinishedAt) < retention.MaxAge)
+			if !keptByCount && !keptByAge {
+				expired = append(expired, job.ID)
+			}
+		}
+	}
+	sort.Strings(expired)
+	return expired
+}
+
+// applyPurgePrintJobs deletes finished jobs. Jobs that are gone or were
+// restarted in the meantime are skipped, so purging is idempotent.
+func applyPurgePrintJobs(tx *fsmTx, cmd *models.Command) error {
+	for _, id := range cmd.JobIDs {
+		job, err := getResource[models.PrintJob](tx.tx, bucketPrintJobs, id)
+		if err != nil {
+			return err
+		}
+		if job == nil || !models.IsTerminalPrintJobStatus(job.Status) {
+			continue
+		}
+		if err := tx.delete(bucketPrintJobs, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PurgeExpiredPrintJobs proposes purging the finished jobs the retention no
+// longer keeps. It returns the number of jobs it asked to purge.
+func (n *Node) PurgeExpiredPrintJobs(retention JobRetention) (int, error) {
+	expired := n.fsm.ExpiredPrintJobs(retention, time.Now())
+	for start := 0; start < len(expired); start += purgeBatchSize {
+		end := start + purgeBatchSize
+		if end > len(expired) {
+			end = len(expired)
+		}
+		cmd := &models.Command{
+			Type:   models.PurgePrintJobs,
+			JobIDs: expired[start:end],
+			Actor:  "janitor",
+		}
+		if err := n.Apply(cmd); err != nil {
+			return start, fmt.Errorf("failed to purge print jobs: %v", err)
+		}
+	}
+	return len(expired), nil
+}
+
+// RunJanitor purges expired print jobs every interval while this node is
+// the leader, until stop is closed
+func (n *Node) RunJanitor(retention JobRetention, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if !n.Leader() {
+				continue
+			}
+			purged, err := n.PurgeExpiredPrintJobs(retention)
+			if err != nil {
+				log.Printf("Janitor failed: %v", err)
+			}
+			if purged > 0 {
+				log.Printf("Janitor purged %d print jobs", purged)
+			}
+		}
+	}
+}
@@ -0,0 +1,387 @@
+package raft
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/devadigapratham/raft3d/api/models"
+	"github.com/hashicorp/raft"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Snapshots are streamed record by record rather than encoded as one value:
+//
+//	header   "R3DS", format version byte, compression byte (never compressed)
+//	body     records, compressed as the header says
+//	record   uint32 big-endian length, then the record type byte and payload
+//	trailer  an end record followed by the SHA-256 of all record bytes
+//
+// The first record holds the applied index, every following one a document
+// of one of the snapshotBuckets.
+const (
+	snapshotMagic   = "R3DS"
+	snapshotVersion = 1
+
+	// maxSnapshotRecord bounds a single record, so a corrupt length can't
+	// make restore allocate without limit
+	maxSnapshotRecord = 64 << 20
+)
+
+// Snapshot record types
+const (
+	recordEnd          byte = 0
+	recordAppliedIndex byte = 1
+	recordResource     byte = 2
+)
+
+// Supported snapshot compressions
+const (
+	SnapshotCompressionNone = "none"
+	SnapshotCompressionGzip = "gzip"
+	SnapshotCompressionZstd = "zstd"
+)
+
+// snapshotCompressions maps compressions to their header byte
+var snapshotCompressions = map[string]byte{
+	SnapshotCompressionNone: 0,
+	SnapshotCompressionGzip: 1,
+	SnapshotCompressionZstd: 2,
+}
+
+// nopWriteCloser adds a no-op Close to a writer
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// snapshotWriter writes records, hashing them as it goes
+type snapshotWriter struct {
+	w    *bufio.Writer
+	hash hash.Hash
+}
+
+// writeRecord writes a record made of its type and the payload parts
+func (sw *snapshotWriter) writeRecord(typ byte, parts ...[]byte) error {
+	length := 1
+	for _, part := range parts {
+		length += len(part)
+	}
+	if length > maxSnapshotRecord {
+		return fmt.Errorf("snapshot record of %d bytes is too large", length)
+	}
+
+	var header [5]byte
+	binary.BigEndian.PutUint32(header[:4], uint32(length))
+	header[4] = typ
+	out := io.MultiWriter(sw.w, sw.hash)
+	if _, err := out.Write(header[:]); err != nil {
+		return err
+	}
+	for _, part := range parts {
+		if _, err := out.Write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSnapshot streams the state in view to w
+func writeSnapshot(w io.Writer, compression string, appliedIndex uint64, view stateTx) error {
+	code, ok := snapshotCompressions[compression]
+	if !ok {
+		return fmt.Errorf("unknown snapshot compression: %s", compression)
+	}
+	if _, err := w.Write(append([]byte(snapshotMagic), snapshotVersion, code)); err != nil {
+		return err
+	}
+
+	var cw io.WriteCloser
+	switch compression {
+	case SnapshotCompressionGzip:
+		cw = gzip.NewWriter(w)
+	case SnapshotCompressionZstd:
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			return err
+		}
+		cw = zw
+	default:
+		cw = nopWriteCloser{w}
+	}
+
+	sw := &snapshotWriter{
+		w:    bufio.NewWriter(cw),
+		hash: sha256.New(),
+	}
+	if err := sw.writeRecord(recordAppliedIndex, binary.AppendUvarint(nil, appliedIndex)); err != nil {
+		return err
+	}
+	for _, bucket := range snapshotBuckets {
+		err := view.forEach(bucket, func(id string, data []byte) error {
+			prefix := binary.AppendUvarint(nil, uint64(len(bucket)))
+			prefix = append(prefix, bucket...)
+			prefix = binary.AppendUvarint(prefix, uint64(len(id)))
+			prefix = append(prefix, id...)
+			return sw.writeRecord(recordResource, prefix, data)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := sw.writeRecord(recordEnd); err != nil {
+		return err
+	}
+	if _, err := sw.w.Write(sw.hash.Sum(nil)); err != nil {
+		return err
+	}
+
+	if err := sw.w.Flush(); err != nil {
+		return err
+	}
+	return cw.Close()
+}
+
+// snapshotSource yields the contents of a snapshot being restored
+type snapshotSource interface {
+	// appliedIndex returns the log index the snapshot was taken at
+	appliedIndex() uint64
+	// next returns the next document, or io.EOF once all documents have
+	// been read and the snapshot has been verified
+	next() (bucket, id string, data []byte, err error)
+	// close releases any decompressor
+	close()
+}
+
+// openSnapshot starts reading a snapshot, in either the streamed format or
+// the single JSON value written by earlier versions
+func openSnapshot(r io.Reader) (snapshotSource, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(len(snapshotMagic) + 2)
+	if err != nil || string(header[:len(snapshotMagic)]) != snapshotMagic {
+		return openLegacySnapshot(br)
+	}
+	br.Discard(len(header))
+
+	if header[len(snapshotMagic)] != snapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", header[len(snapshotMagic)])
+	}
+
+	sr := &streamSnapshot{
+		hash:   sha256.New(),
+		closer: func() {},
+	}
+	switch code := header[len(snapshotMagic)+1]; code {
+	case snapshotCompressions[SnapshotCompressionNone]:
+		sr.r = br
+	case snapshotCompressions[SnapshotCompressionGzip]:
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		sr.r = bufio.NewReader(gr)
+	case snapshotCompressions[SnapshotCompressionZstd]:
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		sr.r = bufio.NewReader(zr)
+		sr.closer = zr.Close
+	default:
+		return nil, fmt.Errorf("unknown snapshot compression %d", code)
+	}
+
+	// The applied index always comes first
+	typ, payload, err := sr.readRecord()
+	if err != nil {
+		sr.close()
+		return nil, err
+	}
+	index, n := binary.Uvarint(payload)
+	if typ != recordAppliedIndex || n <= 0 {
+		sr.close()
+		return nil, fmt.Errorf("snapshot does not start with its applied index")
+	}
+	sr.index = index
+
+	return sr, nil
+}
+
+// streamSnapshot reads the streamed snapshot format
+type streamSnapshot struct {
+	r      *bufio.Reader
+	hash   hash.Hash
+	closer func()
+	index  uint64
+}
+
+func (sr *streamSnapshot) appliedIndex() uint64 {
+	return sr.index
+}
+
+func (sr *streamSnapshot) close() {
+	sr.closer()
+}
+
+// readRecord reads the next record and adds it to the running checksum
+func (sr *streamSnapshot) readRecord() (byte, []byte, error) {
+	var lengthBuf [4]byte
+	if _, err := io.ReadFull(sr.r, lengthBuf[:]); err != nil {
+		return 0, nil, fmt.Errorf("snapshot is truncated: %v", err)
+	}
+	length := binary.BigEndian.Uint32(lengthBuf[:])
+	if length == 0 || length > maxSnapshotRecord {
+		return 0, nil, fmt.Errorf("snapshot record has invalid length %d", length)
+	}
+
+	record := make([]byte, length)
+	if _, err := io.ReadFull(sr.r, record); err != nil {
+		return 0, nil, fmt.Errorf("snapshot is truncated: %v", err)
+	}
+	sr.hash.Write(lengthBuf[:])
+	sr.hash.Write(record)
+	return record[0], record[1:], nil
+}
+
+// readField reads a uvarint length-prefixed field off the front of payload
+func readField(payload []byte) ([]byte, []byte, error) {
+	length, n := binary.Uvarint(payload)
+	if n <= 0 || uint64(len(payload)-n) < length {
+		return nil, nil, fmt.Errorf("snapshot record is malformed")
+	}
+	return payload[n : n+int(length)], payload[n+int(length):], nil
+}
+
+func (sr *streamSnapshot) next() (string, string, []byte, error) {
+	typ, payload, err := sr.readRecord()
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	switch typ {
+	case recordResource:
+		bucket, rest, err := readField(payload)
+		if err != nil {
+			return "", "", nil, err
+		}
+		id, data, err := readField(rest)
+		if err != nil {
+			return "", "", nil, err
+		}
+		return string(bucket), string(id), data, nil
+
+	case recordEnd:
+		expected := sr.hash.Sum(nil)
+		checksum := make([]byte, len(expected))
+		if _, err := io.ReadFull(sr.r, checksum); err != nil {
+			return "", "", nil, fmt.Errorf("snapshot checksum is missing: %v", err)
+		}
+		if !bytes.Equal(checksum, expected) {
+			return "", "", nil, fmt.Errorf("snapshot checksum mismatch")
+		}
+		return "", "", nil, io.EOF
+
+	default:
+		return "", "", nil, fmt.Errorf("unknown snapshot record type %d", typ)
+	}
+}
+
+// legacySnapshot is the layout of snapshots written before they were
+// streamed
+type legacySnapshot struct {
+	AppliedIndex uint64                      `json:"applied_index"`
+	Printers     map[string]*models.Printer  `json:"printers"`
+	Filaments    map[string]*models.Filament `json:"filaments"`
+	PrintJobs    map[string]*models.PrintJob `json:"print_jobs"`
+}
+
+// legacySource yields the resources of a decoded legacy snapshot
+type legacySource struct {
+	index   uint64
+	pending []*mutation
+}
+
+func openLegacySnapshot(r io.Reader) (snapshotSource, error) {
+	var snapshot legacySnapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+
+	src := &legacySource{index: snapshot.AppliedIndex}
+	add := func(bucket, id string, v interface{}) error {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		src.pending = append(src.pending, &mutation{bucket: bucket, id: id, after: data})
+		return nil
+	}
+	for id, printer := range snapshot.Printers {
+		if err := add(bucketPrinters, id, printer); err != nil {
+			return nil, err
+		}
+	}
+	for id, filament := range snapshot.Filaments {
+		if err := add(bucketFilaments, id, filament); err != nil {
+			return nil, err
+		}
+	}
+	for id, job := range snapshot.PrintJobs {
+		if err := add(bucketPrintJobs, id, job); err != nil {
+			return nil, err
+		}
+	}
+	return src, nil
+}
+
+func (s *legacySource) appliedIndex() uint64 {
+	return s.index
+}
+
+func (s *legacySource) next() (string, string, []byte, error) {
+	if len(s.pending) == 0 {
+		return "", "", nil, io.EOF
+	}
+	c := s.pending[0]
+	s.pending = s.pending[1:]
+	return c.bucket, c.id, c.after, nil
+}
+
+func (s *legacySource) close() {}
+
+// fsmSnapshot implements the raft.FSMSnapshot interface
+type fsmSnapshot struct {
+	appliedIndex uint64
+	compression  string
+	view         stateTx
+	release      func()
+}
+
+// Persist saves the snapshot to the provided sink
+func (s *fsmSnapshot) Persist(sink raft.SnapshotSink) error {
+	err := func() error {
+		if err := writeSnapshot(sink, s.compression, s.appliedIndex, s.view); err != nil {
+			return err
+		}
+		return sink.Close()
+	}()
+
+	if err != nil {
+		sink.Cancel()
+		return err
+	}
+
+	return nil
+}
+
+// Release releases the read view held by the snapshot
+func (s *fsmSnapshot) Release() {
+	s.release()
+}
@@ -0,0 +1,264 @@
+package raft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Buckets the FSM keeps its state in. Each resource is stored as its JSON
+// encoding, keyed by ID within its bucket.
+const (
+	bucketPrinters  = "printers"
+	bucketFilaments = "filaments"
+	bucketPrintJobs = "print_jobs"
+
+	// bucketMeta holds FSM bookkeeping rather than resources
+	bucketMeta = "meta"
+)
+
+// Resource types, as named in filters, preconditions and the audit log
+const (
+	ResourcePrinters  = bucketPrinters
+	ResourceFilaments = bucketFilaments
+	ResourcePrintJobs = bucketPrintJobs
+)
+
+// resourceBuckets lists the buckets that hold resources
+var resourceBuckets = []string{bucketPrinters, bucketFilaments, bucketPrintJobs}
+
+// digestBuckets lists the buckets the state digest covers
+var digestBuckets = []string{bucketPrinters, bucketFilaments, bucketPrintJobs, bucketAPIKeys, bucketRoleBindings}
+
+// snapshotBuckets lists every bucket that is part of a snapshot, in
+// snapshot order
+var snapshotBuckets = []string{bucketPrinters, bucketFilaments, bucketPrintJobs, bucketAPIKeys, bucketRoleBindings, bucketHistory, bucketResourceHistory, bucketAudit, bucketMeta}
+
+// containsBucket reports whether bucket is in buckets
+func containsBucket(buckets []string, bucket string) bool {
+	for _, b := range buckets {
+		if b == bucket {
+			return true
+		}
+	}
+	return false
+}
+
+// metaAppliedIndex is the key of the last applied log index in bucketMeta
+const metaAppliedIndex = "applied_index"
+
+// Supported state backends
+const (
+	StateBackendMemory = "memory"
+	StateBackendBolt   = "bolt"
+)
+
+// stateBackend stores the FSM's state. Backends must give update
+// transactions all-or-nothing semantics and views a consistent picture of
+// the last committed update.
+type stateBackend interface {
+	// update runs fn in a read-write transaction, committing only if fn
+	// returns nil
+	update(fn func(tx stateTx) error) error
+	// view runs fn in a read-only transaction
+	view(fn func(tx stateTx) error) error
+	// readView opens a read-only transaction that stays valid until the
+	// returned release func is called, for streaming snapshots
+	readView() (stateTx, func(), error)
+	// close releases the backend
+	close() error
+}
+
+// stateTx reads and writes documents inside a backend transaction. Slices
+// returned by get and passed to forEach are only valid for the lifetime of
+// the transaction.
+type stateTx interface {
+	// get returns the document stored under id, or nil if there is none
+	get(bucket, id string) ([]byte, error)
+	put(bucket, id string, data []byte) error
+	delete(bucket, id string) error
+	// deleteAll removes every document in bucket
+	deleteAll(bucket string) error
+	// forEach calls fn for every document in bucket, ordered by ID
+	forEach(bucket string, fn func(id string, data []byte) error) error
+	// forEachFrom is forEach starting at the first ID not below from,
+	// seeking to it rather than walking the IDs before it
+	forEachFrom(bucket, from string, fn func(id string, data []byte) error) error
+}
+
+// mutation records how one resource was modified by a committed update.
+// before is nil for created resources and after is nil for deleted ones.
+type mutation struct {
+	bucket string
+	id     string
+	before []byte
+	after  []byte
+}
+
+// ErrAlreadyExists is returned when creating a resource whose ID is taken
+var ErrAlreadyExists = errors.New("already exists")
+
+// versioned is implemented by resources that carry a version and the index
+// they were created at
+type versioned interface {
+	SetVersion(version uint64)
+	SetCreatedIndex(index uint64)
+}
+
+// fsmTx wraps a backend transaction, encoding resources and recording the
+// changes made to them. Resources it stores are stamped with index, the log
+// index of the entry being applied, as their version. appendedAt is when the
+// leader appended that entry, the only clock commands may read.
+type fsmTx struct {
+	tx         stateTx
+	index      uint64
+	appendedAt time.Time
+	changes    []*mutation
+	byKey      map[string]*mutation
+
+	// deleted holds the resources deleted, as they were right before,
+	// which changes loses when a resource is changed first
+	deleted []*mutation
+}
+
+func newFSMTx(tx stateTx, index uint64, appendedAt time.Time) *fsmTx {
+	return &fsmTx{
+		tx:         tx,
+		index:      index,
+		appendedAt: appendedAt,
+		byKey:      make(map[string]*mutation),
+	}
+}
+
+// record notes that a resource now holds after
+func (t *fsmTx) record(bucket, id string, after []byte) error {
+	key := bucket + "\x00" + id
+	if c, ok := t.byKey[key]; ok {
+		c.after = after
+		return nil
+	}
+
+	before, err := t.tx.get(bucket, id)
+	if err != nil {
+		return err
+	}
+	c := &mutation{
+		bucket: bucket,
+		id:     id,
+		// The backend's slice dies with the transaction
+		before: append([]byte(nil), before...),
+		after:  after,
+	}
+	t.byKey[key] = c
+	t.changes = append(t.changes, c)
+	return nil
+}
+
+// put stores a resource, stamping its version
+func (t *fsmTx) put(bucket, id string, v interface{}) error {
+	if r, ok := v.(versioned); ok {
+		created, err := t.createdIndex(bucket, id)
+		if err != nil {
+			return err
+		}
+		r.SetVersion(t.index)
+		r.SetCreatedIndex(created)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s %s: %v", bucket, id, err)
+	}
+	if err := t.record(bucket, id, data); err != nil {
+		return err
+	}
+	return t.tx.put(bucket, id, data)
+}
+
+// createdIndex returns the index the resource stored under id was created
+// at, or the index being applied if there is none. Resources written before
+// creation indexes were kept count as created at 0.
+func (t *fsmTx) createdIndex(bucket, id string) (uint64, error) {
+	data, err := t.tx.get(bucket, id)
+	if err != nil || data == nil {
+		return t.index, err
+	}
+	var v struct {
+		CreatedIndex uint64 `json:"created_index"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return 0, fmt.Errorf("failed to decode %s %s: %v", bucket, id, err)
+	}
+	return v.CreatedIndex, nil
+}
+
+// create stores a new resource, failing with ErrAlreadyExists if there is
+// one with the same ID
+func (t *fsmTx) create(bucket, id string, v interface{}) error {
+	data, err := t.tx.get(bucket, id)
+	if err != nil {
+		return err
+	}
+	if data != nil {
+		return fmt.Errorf("%w: %s %s", ErrAlreadyExists, bucket, id)
+	}
+	return t.put(bucket, id, v)
+}
+
+// delete removes a resource
+func (t *fsmTx) delete(bucket, id string) error {
+	data, err := t.tx.get(bucket, id)
+	if err != nil {
+		return err
+	}
+	if data != nil {
+		t.deleted = append(t.deleted, &mutation{bucket: bucket, id: id, before: append([]byte(nil), data...)})
+	}
+	if err := t.record(bucket, id, nil); err != nil {
+		return err
+	}
+	return t.tx.delete(bucket, id)
+}
+
+// getResource reads and decodes a resource, returning nil if it doesn't exist
+func getResource[T any](tx stateTx, bucket, id string) (*T, error) {
+	data, err := tx.get(bucket, id)
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("failed to decode %s %s: %v", bucket, id, err)
+	}
+	return &v, nil
+}
+
+// listResources reads and decodes every resource in a bucket, ordered by ID
+func listResources[T any](tx stateTx, bucket string) ([]*T, error) {
+	list := make([]*T, 0)
+	err := tx.forEach(bucket, func(id string, data []byte) error {
+		var v T
+		if err := json.Unmarshal(data, &v); err != nil {
+			return fmt.Errorf("failed to decode %s %s: %v", bucket, id, err)
+		}
+		list = append(list, &v)
+		return nil
+	})
+	return list, err
+}
+
+// getAppliedIndex reads the last applied log index
+func getAppliedIndex(tx stateTx) (uint64, error) {
+	data, err := tx.get(bucketMeta, metaAppliedIndex)
+	if err != nil || data == nil {
+		return 0, err
+	}
+	return strconv.ParseUint(string(data), 10, 64)
+}
+
+// putAppliedIndex stores the last applied log index
+func putAppliedIndex(tx stateTx, index uint64) error {
+	return tx.put(bucketMeta, metaAppliedIndex, []byte(strconv.FormatUint(index, 10)))
+}
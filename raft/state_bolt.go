@@ -0,0 +1,130 @@
+package raft
+
+import (
+	"fmt"
+
+	"go.etcd.io/bbolt"
+)
+
+// boltBackend keeps the FSM state in a local BoltDB file, one bucket per
+// resource type, so it doesn't have to fit in memory
+type boltBackend struct {
+	db *bbolt.DB
+}
+
+// newBoltBackend opens or creates the BoltDB file at path
+func newBoltBackend(path string) (*boltBackend, error) {
+	db, err := bbolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create all the buckets up front so transactions can rely on them
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range snapshotBuckets {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &boltBackend{db: db}, nil
+}
+
+func (b *boltBackend) update(fn func(tx stateTx) error) error {
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		return fn(&boltTx{tx: tx})
+	})
+}
+
+func (b *boltBackend) view(fn func(tx stateTx) error) error {
+	return b.db.View(func(tx *bbolt.Tx) error {
+		return fn(&boltTx{tx: tx})
+	})
+}
+
+func (b *boltBackend) readView() (stateTx, func(), error) {
+	tx, err := b.db.Begin(false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &boltTx{tx: tx}, func() { tx.Rollback() }, nil
+}
+
+func (b *boltBackend) close() error {
+	return b.db.Close()
+}
+
+// boltTx is a transaction on a boltBackend
+type boltTx struct {
+	tx *bbolt.Tx
+}
+
+func (t *boltTx) bucket(name string) (*bbolt.Bucket, error) {
+	bucket := t.tx.Bucket([]byte(name))
+	if bucket == nil {
+		return nil, fmt.Errorf("bucket %s does not exist", name)
+	}
+	return bucket, nil
+}
+
+func (t *boltTx) get(bucket, id string) ([]byte, error) {
+	b, err := t.bucket(bucket)
+	if err != nil {
+		return nil, err
+	}
+	return b.Get([]byte(id)), nil
+}
+
+func (t *boltTx) put(bucket, id string, data []byte) error {
+	b, err := t.bucket(bucket)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(id), data)
+}
+
+func (t *boltTx) delete(bucket, id string) error {
+	b, err := t.bucket(bucket)
+	if err != nil {
+		return err
+	}
+	return b.Delete([]byte(id))
+}
+
+func (t *boltTx) deleteAll(bucket string) error {
+	if err := t.tx.DeleteBucket([]byte(bucket)); err != nil && err != bbolt.ErrBucketNotFound {
+		return err
+	}
+	_, err := t.tx.CreateBucket([]byte(bucket))
+	return err
+}
+
+func (t *boltTx) forEach(bucket string, fn func(id string, data []byte) error) error {
+	b, err := t.bucket(bucket)
+	if err != nil {
+		return err
+	}
+	return b.ForEach(func(k, v []byte) error {
+		return fn(string(k), v)
+	})
+}
+
+func (t *boltTx) forEachFrom(bucket, from string, fn func(id string, data []byte) error) error {
+	b, err := t.bucket(bucket)
+	if err != nil {
+		return err
+	}
+	c := b.Cursor()
+	for k, v := c.Seek([]byte(from)); k != nil; k, v = c.Next() {
+		if err := fn(string(k), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
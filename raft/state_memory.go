@@ -0,0 +1,125 @@
+package raft
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	iradix "github.com/hashicorp/go-immutable-radix"
+)
+
+// memoryBackend keeps the FSM state in an immutable radix tree. Updates
+// build a new tree and swap it in on commit, so views and snapshots read a
+// tree that never changes underneath them.
+type memoryBackend struct {
+	mu   sync.Mutex
+	tree *iradix.Tree
+}
+
+// newMemoryBackend creates an empty in-memory backend
+func newMemoryBackend() *memoryBackend {
+	return &memoryBackend{tree: iradix.New()}
+}
+
+func (b *memoryBackend) current() *iradix.Tree {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tree
+}
+
+func (b *memoryBackend) update(fn func(tx stateTx) error) error {
+	txn := b.current().Txn()
+	if err := fn(&memoryTx{txn: txn}); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.tree = txn.Commit()
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *memoryBackend) view(fn func(tx stateTx) error) error {
+	return fn(&memoryTx{root: b.current().Root()})
+}
+
+func (b *memoryBackend) readView() (stateTx, func(), error) {
+	return &memoryTx{root: b.current().Root()}, func() {}, nil
+}
+
+func (b *memoryBackend) close() error {
+	return nil
+}
+
+// memoryTx is a transaction on a memoryBackend. Read-write transactions
+// have a txn, read-only ones only a root.
+type memoryTx struct {
+	txn  *iradix.Txn
+	root *iradix.Node
+}
+
+// memoryKey builds the tree key of a document
+func memoryKey(bucket, id string) []byte {
+	return []byte(bucket + "\x00" + id)
+}
+
+func (t *memoryTx) node() *iradix.Node {
+	if t.txn != nil {
+		return t.txn.Root()
+	}
+	return t.root
+}
+
+func (t *memoryTx) get(bucket, id string) ([]byte, error) {
+	v, ok := t.node().Get(memoryKey(bucket, id))
+	if !ok {
+		return nil, nil
+	}
+	return v.([]byte), nil
+}
+
+func (t *memoryTx) put(bucket, id string, data []byte) error {
+	if t.txn == nil {
+		return fmt.Errorf("cannot write in a read-only transaction")
+	}
+	t.txn.Insert(memoryKey(bucket, id), data)
+	return nil
+}
+
+func (t *memoryTx) delete(bucket, id string) error {
+	if t.txn == nil {
+		return fmt.Errorf("cannot write in a read-only transaction")
+	}
+	t.txn.Delete(memoryKey(bucket, id))
+	return nil
+}
+
+func (t *memoryTx) deleteAll(bucket string) error {
+	if t.txn == nil {
+		return fmt.Errorf("cannot write in a read-only transaction")
+	}
+	t.txn.DeletePrefix(memoryKey(bucket, ""))
+	return nil
+}
+
+func (t *memoryTx) forEach(bucket string, fn func(id string, data []byte) error) error {
+	prefix := memoryKey(bucket, "")
+	var err error
+	t.node().WalkPrefix(prefix, func(k []byte, v interface{}) bool {
+		err = fn(string(k[len(prefix):]), v.([]byte))
+		return err != nil
+	})
+	return err
+}
+
+func (t *memoryTx) forEachFrom(bucket, from string, fn func(id string, data []byte) error) error {
+	prefix := memoryKey(bucket, "")
+	it := t.node().Iterator()
+	it.SeekLowerBound(memoryKey(bucket, from))
+	for k, v, ok := it.Next(); ok && bytes.HasPrefix(k, prefix); k, v, ok = it.Next() {
+		if err := fn(string(k[len(prefix):]), v.([]byte)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,386 @@
+package raft
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+// testBackends opens each state backend for a test
+var testBackends = []struct {
+	name string
+	open func(t *testing.T) stateBackend
+}{
+	{StateBackendMemory, func(t *testing.T) stateBackend {
+		return newMemoryBackend()
+	}},
+	{StateBackendBolt, func(t *testing.T) stateBackend {
+		b, err := newBoltBackend(filepath.Join(t.TempDir(), "state.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { b.close() })
+		return b
+	}},
+}
+
+// get reads a document in a view of the backend
+func get(t *testing.T, b stateBackend, bucket, id string) []byte {
+	t.Helper()
+
+	var doc []byte
+	err := b.view(func(tx stateTx) error {
+		data, err := tx.get(bucket, id)
+		doc = append([]byte(nil), data...)
+		if data == nil {
+			doc = nil
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// ids lists the IDs in a bucket in the order forEach visits them
+func ids(t *testing.T, b stateBackend, bucket string) []string {
+	t.Helper()
+
+	list := make([]string, 0)
+	err := b.view(func(tx stateTx) error {
+		return tx.forEach(bucket, func(id string, data []byte) error {
+			list = append(list, id)
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return list
+}
+
+func TestStateBackends(t *testing.T) {
+	// Applying stamps versions on the resources, so each case gets its own
+	printer := func() *models.Printer {
+		return &models.Printer{ID: "p1", Company: "Prusa", Model: "MK4"}
+	}
+	filament := func() *models.Filament {
+		return &models.Filament{ID: "f1", Type: "PLA", Color: "red", TotalWeightInGrams: 1000, RemainingWeightInGrams: 1000}
+	}
+
+	cases := []struct {
+		name string
+		// run gets a fresh backend, and open to open more of its kind
+		run func(t *testing.T, b stateBackend, open func(t *testing.T) stateBackend)
+	}{
+		{"put", func(t *testing.T, b stateBackend, open func(t *testing.T) stateBackend) {
+			err := b.update(func(tx stateTx) error {
+				if err := tx.put(bucketPrinters, "p1", []byte(`{"id":"p1"}`)); err != nil {
+					return err
+				}
+				// Writes are visible to the transaction making them
+				if data, err := tx.get(bucketPrinters, "p1"); err != nil || string(data) != `{"id":"p1"}` {
+					t.Errorf("read %q, %v inside the transaction", data, err)
+				}
+				return tx.put(bucketPrinters, "p1", []byte(`{"id":"p1","model":"MK4"}`))
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := get(t, b, bucketPrinters, "p1"); string(got) != `{"id":"p1","model":"MK4"}` {
+				t.Errorf("got %q", got)
+			}
+			if got := get(t, b, bucketPrinters, "p2"); got != nil {
+				t.Errorf("got %q for a missing document", got)
+			}
+			// Buckets are separate namespaces
+			if got := get(t, b, bucketFilaments, "p1"); got != nil {
+				t.Errorf("got %q from another bucket", got)
+			}
+		}},
+
+		{"create", func(t *testing.T, b stateBackend, open func(t *testing.T) stateBackend) {
+			for i, want := range []error{nil, ErrAlreadyExists} {
+				err := b.update(func(stx stateTx) error {
+					return newFSMTx(stx, uint64(i+1), time.Time{}).create(bucketPrinters, "p1", printer())
+				})
+				if !errors.Is(err, want) {
+					t.Fatalf("create %d returned %v, want %v", i+1, err, want)
+				}
+			}
+			stored, err := getResourceIn[models.Printer](b, bucketPrinters, "p1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.Version != 1 || stored.CreatedIndex != 1 {
+				t.Errorf("printer is at version %d, created at %d, want 1 and 1", stored.Version, stored.CreatedIndex)
+			}
+		}},
+
+		{"delete", func(t *testing.T, b stateBackend, open func(t *testing.T) stateBackend) {
+			err := b.update(func(tx stateTx) error {
+				for _, id := range []string{"a", "b", "c"} {
+					if err := tx.put(bucketPrinters, id, []byte(`{}`)); err != nil {
+						return err
+					}
+				}
+				return tx.put(bucketFilaments, "a", []byte(`{}`))
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = b.update(func(tx stateTx) error {
+				if err := tx.delete(bucketPrinters, "b"); err != nil {
+					return err
+				}
+				// Deleting what isn't there is not an error
+				return tx.delete(bucketPrinters, "missing")
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := ids(t, b, bucketPrinters), []string{"a", "c"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v after delete, want %v", got, want)
+			}
+
+			if err := b.update(func(tx stateTx) error { return tx.deleteAll(bucketPrinters) }); err != nil {
+				t.Fatal(err)
+			}
+			if got := ids(t, b, bucketPrinters); len(got) != 0 {
+				t.Errorf("got %v after deleteAll", got)
+			}
+			if got := ids(t, b, bucketFilaments); !reflect.DeepEqual(got, []string{"a"}) {
+				t.Errorf("deleteAll reached into another bucket: %v", got)
+			}
+		}},
+
+		{"rollback", func(t *testing.T, b stateBackend, open func(t *testing.T) stateBackend) {
+			if err := b.update(func(tx stateTx) error { return tx.put(bucketPrinters, "p1", []byte(`1`)) }); err != nil {
+				t.Fatal(err)
+			}
+			failed := errors.New("failed")
+			err := b.update(func(tx stateTx) error {
+				if err := tx.put(bucketPrinters, "p1", []byte(`2`)); err != nil {
+					return err
+				}
+				if err := tx.put(bucketPrinters, "p2", []byte(`2`)); err != nil {
+					return err
+				}
+				if err := tx.deleteAll(bucketFilaments); err != nil {
+					return err
+				}
+				return failed
+			})
+			if err != failed {
+				t.Fatalf("update returned %v, want %v", err, failed)
+			}
+			if got := get(t, b, bucketPrinters, "p1"); string(got) != `1` {
+				t.Errorf("got %q after rollback, want 1", got)
+			}
+			if got := get(t, b, bucketPrinters, "p2"); got != nil {
+				t.Errorf("got %q after rollback, want nothing", got)
+			}
+		}},
+
+		{"forEach", func(t *testing.T, b stateBackend, open func(t *testing.T) stateBackend) {
+			err := b.update(func(tx stateTx) error {
+				for _, id := range []string{"job10", "job02", "a", "job1", "Z"} {
+					if err := tx.put(bucketPrintJobs, id, []byte(id)); err != nil {
+						return err
+					}
+				}
+				return tx.put(bucketPrinters, "job00", []byte(`{}`))
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := ids(t, b, bucketPrintJobs), []string{"Z", "a", "job02", "job1", "job10"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+
+			// errStopIteration stops early and comes back to the caller
+			var visited []string
+			err = b.view(func(tx stateTx) error {
+				return tx.forEach(bucketPrintJobs, func(id string, data []byte) error {
+					if string(data) != id {
+						t.Errorf("document %s holds %q", id, data)
+					}
+					visited = append(visited, id)
+					if len(visited) == 2 {
+						return errStopIteration
+					}
+					return nil
+				})
+			})
+			if err != errStopIteration || len(visited) != 2 {
+				t.Errorf("stopped with %v after %v", err, visited)
+			}
+		}},
+
+		{"forEachFrom", func(t *testing.T, b stateBackend, open func(t *testing.T) stateBackend) {
+			err := b.update(func(tx stateTx) error {
+				for _, id := range []string{"a/1", "a/2", "b/1", "b/2", "c/1"} {
+					if err := tx.put(bucketHistory, id, []byte(id)); err != nil {
+						return err
+					}
+				}
+				// The next bucket must not be reached from the last one
+				return tx.put(bucketMeta, "a/0", []byte(`{}`))
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			from := func(start string) []string {
+				list := make([]string, 0)
+				err := b.view(func(tx stateTx) error {
+					return tx.forEachFrom(bucketHistory, start, func(id string, data []byte) error {
+						list = append(list, id)
+						return nil
+					})
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				return list
+			}
+			for start, want := range map[string][]string{
+				"":    {"a/1", "a/2", "b/1", "b/2", "c/1"},
+				"b/":  {"b/1", "b/2", "c/1"},
+				"a/2": {"a/2", "b/1", "b/2", "c/1"},
+				"b/3": {"c/1"},
+				"d":   {},
+			} {
+				if got := from(start); !reflect.DeepEqual(got, want) {
+					t.Errorf("from %q got %v, want %v", start, got, want)
+				}
+			}
+		}},
+
+		{"snapshot-restore", func(t *testing.T, b stateBackend, open func(t *testing.T) stateBackend) {
+			f := newTestFSMOn(t, b)
+			f.mustApply(&models.Command{Type: models.AddPrinter, Printer: printer()})
+			f.mustApply(&models.Command{Type: models.AddFilament, Filament: filament()})
+			f.mustApply(&models.Command{Type: models.AddPrintJob, PrintJob: &models.PrintJob{
+				ID: "j1", PrinterID: "p1", FilamentID: "f1", Filepath: "/prints/part.gcode", PrintWeightInGrams: 100,
+			}})
+			f.mustApply(&models.Command{Type: models.UpdatePrintJob, JobID: "j1", NewStatus: "Running"})
+
+			snap, err := f.Snapshot()
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Writes after the snapshot was taken must not leak into it
+			f.mustApply(&models.Command{Type: models.UpdatePrintJob, JobID: "j1", NewStatus: "Done"})
+			sink := &bufferSink{}
+			if err := snap.Persist(sink); err != nil {
+				t.Fatal(err)
+			}
+			snap.Release()
+
+			restored := newTestFSMOn(t, open(t))
+			restored.mustApply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: "stale", Company: "Bambu", Model: "X1"}})
+			if err := restored.Restore(io.NopCloser(&sink.Buffer)); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := restored.AppliedIndex(); got != 4 {
+				t.Errorf("restored applied index is %d, want 4", got)
+			}
+			if _, ok := restored.GetPrinter("stale"); ok {
+				t.Error("restore kept a printer that isn't in the snapshot")
+			}
+			if job, ok := restored.GetPrintJob("j1"); !ok || job.Status != "Running" {
+				t.Errorf("restored job is %+v, want it Running", job)
+			}
+			if got, want := restored.ReservedGrams("f1"), 100; got != want {
+				t.Errorf("restored reservations are %d g, want %d g", got, want)
+			}
+			want, ok := f.DigestAt(4)
+			if !ok {
+				t.Fatal("no digest recorded at index 4")
+			}
+			if _, got := restored.Digest(); got != want {
+				t.Errorf("restored digest is %s, want %s", got, want)
+			}
+		}},
+
+		{"skip", func(t *testing.T, b stateBackend, open func(t *testing.T) stateBackend) {
+			f := newTestFSMOn(t, b)
+			f.mustApply(&models.Command{Type: models.AddFilament, Filament: filament()})
+			_, digest := f.Digest()
+
+			// The first operation writes before the second one fails
+			cmd := &models.Command{Type: models.CommitTransaction, Transaction: &models.Transaction{
+				Operations: []*models.Command{
+					{Type: models.AddPrinter, Printer: printer()},
+					{Type: models.AdjustFilamentWeight, FilamentID: "f1", DeltaGrams: -5000, Reason: "spill"},
+				},
+			}}
+			if _, ok := f.apply(cmd).(error); !ok {
+				t.Fatal("transaction succeeded")
+			}
+
+			if _, ok := f.GetPrinter("p1"); ok {
+				t.Error("failed transaction left its printer behind")
+			}
+			if fl, _ := f.GetFilament("f1"); fl.RemainingWeightInGrams != 1000 || fl.Version != 1 {
+				t.Errorf("failed transaction changed the filament to %+v", fl)
+			}
+			if _, got := f.Digest(); got != digest {
+				t.Errorf("digest changed from %s to %s", digest, got)
+			}
+			feed, err := f.ChangesSince(1, nil, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(feed.Changes) != 0 {
+				t.Errorf("failed transaction left history: %+v", feed.Changes[0])
+			}
+
+			// Only the applied index and the audit record of the failure
+			// are written
+			if got := f.AppliedIndex(); got != 2 {
+				t.Errorf("applied index is %d, want 2", got)
+			}
+			records, _, err := f.GetAuditRecords(AuditFilter{}, 1, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != 1 || records[0].Result != AuditResultError || len(records[0].Changes) != 0 {
+				t.Errorf("got audit records %+v, want a single failure without changes", records)
+			}
+			stored, err := getResourceIn[models.Printer](b, bucketPrinters, "p1")
+			if err != nil || stored != nil {
+				t.Errorf("backend holds %+v, %v", stored, err)
+			}
+		}},
+	}
+
+	for _, backend := range testBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			for _, tc := range cases {
+				t.Run(tc.name, func(t *testing.T) {
+					tc.run(t, backend.open(t), backend.open)
+				})
+			}
+		})
+	}
+}
+
+// getResourceIn reads and decodes a resource in a view of the backend
+func getResourceIn[T any](b stateBackend, bucket, id string) (*T, error) {
+	var v *T
+	err := b.view(func(tx stateTx) error {
+		var err error
+		v, err = getResource[T](tx, bucket, id)
+		return err
+	})
+	return v, err
+}
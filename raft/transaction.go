@@ -0,0 +1,172 @@
+package raft
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+// Stages of a transaction a TransactionError can come from
+const (
+	TransactionStagePrecondition = "precondition"
+	TransactionStageOperation    = "operation"
+)
+
+// TransactionError reports which precondition or operation made a
+// transaction fail. Nothing of the transaction is applied.
+type TransactionError struct {
+	Stage string
+	// Index is the position of the precondition or operation in the
+	// transaction
+	Index int
+	Err   error
+}
+
+func (e *TransactionError) Error() string {
+	return fmt.Sprintf("%s %d: %v", e.Stage, e.Index, e.Err)
+}
+
+func (e *TransactionError) Unwrap() error {
+	return e.Err
+}
+
+// OperationResult is the outcome of one operation of a transaction
+type OperationResult struct {
+	Type         models.CommandType `json:"type"`
+	ResourceType string             `json:"resource_type,omitempty"`
+	ResourceID   string             `json:"resource_id,omitempty"`
+	// Resource is the resource as the operation left it
+	Resource json.RawMessage `json:"resource,omitempty"`
+}
+
+// TransactionResult is what Apply returns for a transaction that committed
+type TransactionResult struct {
+	Results []OperationResult `json:"results"`
+}
+
+// applyTransaction checks the preconditions of a transaction and applies
+// its operations in order. Any failure fails the whole transaction, and
+// Apply then discards everything it wrote.
+func (f *FSM) applyTransaction(tx *fsmTx, cmd *models.Command) (*TransactionResult, error) {
+	if cmd.Transaction == nil {
+		return nil, errorf(ErrValidation, "transaction is nil")
+	}
+
+	for i := range cmd.Transaction.Preconditions {
+		if err := checkPrecondition(tx.tx, &cmd.Transaction.Preconditions[i]); err != nil {
+			return nil, &TransactionError{Stage: TransactionStagePrecondition, Index: i, Err: err}
+		}
+	}
+
+	result := &TransactionResult{Results: make([]OperationResult, 0, len(cmd.Transaction.Operations))}
+	for i, op := range cmd.Transaction.Operations {
+		if op == nil {
+			return nil, &TransactionError{Stage: TransactionStageOperation, Index: i, Err: errorf(ErrValidation, "operation is nil")}
+		}
+		if op.Type == models.CommitTransaction {
+			return nil, &TransactionError{Stage: TransactionStageOperation, Index: i, Err: errorf(ErrValidation, "transactions can't be nested")}
+		}
+		if models.IsAuthCommand(op.Type) || op.Type == models.ReportDivergence {
+			return nil, &TransactionError{Stage: TransactionStageOperation, Index: i, Err: errorf(ErrValidation, "%s can't be part of a transaction", op.Type)}
+		}
+		// Operations are issued by whoever issued the transaction, whatever
+		// they say themselves
+		op.Actor, op.ClientIP, op.RequestID = cmd.Actor, cmd.ClientIP, cmd.RequestID
+		if _, err := f.applyCommand(tx, op); err != nil {
+			return nil, &TransactionError{Stage: TransactionStageOperation, Index: i, Err: err}
+		}
+
+		opResult := OperationResult{Type: op.Type}
+		opResult.ResourceType, opResult.ResourceID = commandResource(op)
+		if opResult.ResourceType != "" {
+			data, err := tx.tx.get(opResult.ResourceType, opResult.ResourceID)
+			if err != nil {
+				return nil, err
+			}
+			// The backend's slice dies with the transaction
+			opResult.Resource = append(json.RawMessage(nil), data...)
+		}
+		result.Results = append(result.Results, opResult)
+	}
+	return result, nil
+}
+
+// checkPrecondition checks a precondition against the state
+func checkPrecondition(tx stateTx, p *models.Precondition) error {
+	if !containsBucket(resourceBuckets, p.Resource) {
+		return errorf(ErrValidation, "unknown resource type: %s", p.Resource)
+	}
+	data, err := tx.get(p.Resource, p.ID)
+	if err != nil {
+		return err
+	}
+
+	switch p.Type {
+	case models.PreconditionExists:
+		if data == nil {
+			return fmt.Errorf("%s %s does not exist", p.Resource, p.ID)
+		}
+		return nil
+
+	case models.PreconditionNotExists:
+		if data != nil {
+			return fmt.Errorf("%s %s already exists", p.Resource, p.ID)
+		}
+		return nil
+
+	case models.PreconditionFieldEquals:
+		if data == nil {
+			return fmt.Errorf("%s %s does not exist", p.Resource, p.ID)
+		}
+		value, err := resourceField(data, p.Field)
+		if err != nil {
+			return err
+		}
+		if !jsonEqual(value, p.Value) {
+			return fmt.Errorf("%s %s has %s %s, expected %s", p.Resource, p.ID, p.Field, value, p.Value)
+		}
+		return nil
+
+	case models.PreconditionVersionEquals:
+		if data == nil {
+			return fmt.Errorf("%s %s does not exist", p.Resource, p.ID)
+		}
+		version, err := resourceVersion(data)
+		if err != nil {
+			return err
+		}
+		if version != p.Version {
+			return fmt.Errorf("%w: %s %s is at version %d, expected %d",
+				ErrVersionConflict, p.Resource, p.ID, version, p.Version)
+		}
+		return nil
+
+	default:
+		return errorf(ErrValidation, "unknown precondition type: %s", p.Type)
+	}
+}
+
+// resourceField returns the JSON of a top-level field of a stored resource,
+// or null if the resource doesn't have it
+func resourceField(data []byte, field string) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	value, ok := fields[field]
+	if !ok {
+		return json.RawMessage("null"), nil
+	}
+	return value, nil
+}
+
+// jsonEqual reports whether two JSON documents hold the same value
+func jsonEqual(a, b []byte) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,70 @@
+package raft
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+func TestTransactionOperationsTakeItsActor(t *testing.T) {
+	f := newTestFSM(t)
+	f.mustApply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: "p1", Company: "Prusa", Model: "MK4"}})
+	f.mustApply(&models.Command{Type: models.AddFilament, Filament: &models.Filament{
+		ID: "f1", Type: "PLA", Color: "red", TotalWeightInGrams: 1000, RemainingWeightInGrams: 1000,
+	}})
+	f.mustApply(&models.Command{Type: models.AddPrintJob, PrintJob: &models.PrintJob{
+		ID: "j1", PrinterID: "p1", FilamentID: "f1", Filepath: "/prints/part.gcode", PrintWeightInGrams: 100,
+	}})
+
+	f.mustApply(&models.Command{
+		Type:      models.CommitTransaction,
+		Actor:     "alice",
+		ClientIP:  "10.0.0.1",
+		RequestID: "req-1",
+		Transaction: &models.Transaction{Operations: []*models.Command{
+			{Type: models.UpdatePrintJob, JobID: "j1", NewStatus: "Canceled", Actor: "mallory", RequestID: "forged"},
+		}},
+	})
+
+	job, _ := f.GetPrintJob("j1")
+	last := job.Transitions[len(job.Transitions)-1]
+	if last.To != "Canceled" || last.Actor != "alice" {
+		t.Errorf("last transition is %+v, want a cancellation by alice", last)
+	}
+}
+
+func TestTransactionArchivesDeletedJobs(t *testing.T) {
+	f := newTestFSM(t)
+	archive, err := openJobArchive(filepath.Join(t.TempDir(), "archive.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.close()
+	f.archive = archive
+
+	f.mustApply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: "p1", Company: "Prusa", Model: "MK4"}})
+	f.mustApply(&models.Command{Type: models.AddFilament, Filament: &models.Filament{
+		ID: "f1", Type: "PLA", Color: "red", TotalWeightInGrams: 1000, RemainingWeightInGrams: 1000,
+	}})
+	f.mustApply(&models.Command{Type: models.AddPrintJob, PrintJob: &models.PrintJob{
+		ID: "j1", PrinterID: "p1", FilamentID: "f1", Filepath: "/prints/part.gcode", PrintWeightInGrams: 100,
+	}})
+	f.mustApply(&models.Command{Type: models.CommitTransaction, Transaction: &models.Transaction{
+		Operations: []*models.Command{
+			{Type: models.UpdatePrintJob, JobID: "j1", NewStatus: "Canceled"},
+			{Type: models.DeletePrintJob, JobID: "j1"},
+		},
+	}})
+
+	if _, ok := f.GetPrintJob("j1"); ok {
+		t.Fatal("transaction didn't delete the job")
+	}
+	archived, _, err := f.GetArchivedPrintJobs(ArchiveFilter{}, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archived) != 1 || archived[0].Job.ID != "j1" || archived[0].Job.Status != "Canceled" || archived[0].Index != f.index {
+		t.Errorf("archive holds %+v, want j1 as canceled at index %d", archived[0], f.index)
+	}
+}
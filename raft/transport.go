@@ -13,6 +13,10 @@ import (
 // Transport provides methods for forwarding requests to the Raft leader
 type Transport struct {
 	node *Node
+
+	// APIKey, if set, is sent as the X-API-Key header of the requests made
+	// to the HTTP API of other nodes
+	APIKey string
 }
 
 // NewTransport creates a new Transport
@@ -22,6 +26,34 @@ func NewTransport(node *Node) *Transport {
 	}
 }
 
+// httpAddrForRaftAddr returns the base URL of the HTTP API of the node with
+// the given Raft address
+func httpAddrForRaftAddr(raftAddr string) (string, error) {
+	// Extract HTTP address from raft address (this assumes a convention where Raft port and HTTP port have a fixed relationship)
+	httpPort := 8000
+	raftPort := 7000
+	nodePort := 0
+	if len(raftAddr) < 4 {
+		return "", fmt.Errorf("failed to parse port of %q", raftAddr)
+	}
+	_, err := fmt.Sscanf(raftAddr[len(raftAddr)-4:], "%d", &nodePort)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse port of %q: %v", raftAddr, err)
+	}
+
+	// Calculate HTTP port from Raft port
+	nodeHTTPPort := httpPort + (nodePort - raftPort)
+	return fmt.Sprintf("http://localhost:%d", nodeHTTPPort), nil
+}
+
+// authenticate adds the transport's API key, if any, to a request to
+// another node
+func (t *Transport) authenticate(req *http.Request) {
+	if t.APIKey != "" {
+		req.Header.Set("X-API-Key", t.APIKey)
+	}
+}
+
 // ForwardToLeader forwards a request to the Raft leader
 func (t *Transport) ForwardToLeader(method, path string, body []byte) ([]byte, error) {
 	// If this node is the leader, no need to forward
@@ -35,19 +67,11 @@ func (t *Transport) ForwardToLeader(method, path string, body []byte) ([]byte, e
 		return nil, fmt.Errorf("no leader available")
 	}
 
-	// Extract HTTP address from raft address (this assumes a convention where Raft port and HTTP port have a fixed relationship)
-	httpPort := 8000
-	raftPort := 7000
-	leaderPort := 0
-	_, err := fmt.Sscanf(leaderAddr[len(leaderAddr)-4:], "%d", &leaderPort)
+	leaderHTTPAddr, err := httpAddrForRaftAddr(leaderAddr)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse leader port: %v", err)
+		return nil, err
 	}
 
-	// Calculate HTTP port from Raft port
-	leaderHTTPPort := httpPort + (leaderPort - raftPort)
-	leaderHTTPAddr := fmt.Sprintf("http://localhost:%d", leaderHTTPPort)
-
 	// Create the request
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -62,6 +86,7 @@ func (t *Transport) ForwardToLeader(method, path string, body []byte) ([]byte, e
 		req.Body = http.NoBody
 		req.Header.Set("Content-Type", "application/json")
 	}
+	t.authenticate(req)
 
 	// Send the request
 	client := &http.Client{}
@@ -0,0 +1,58 @@
+package raft
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+func TestUpsertKeepsReservedFilament(t *testing.T) {
+	f := newTestFSM(t)
+	f.mustApply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: "p1", Company: "Prusa", Model: "MK4"}})
+	for _, id := range []string{"f1", "f2"} {
+		f.mustApply(&models.Command{Type: models.AddFilament, Filament: &models.Filament{
+			ID: id, Type: "PLA", Color: "red", TotalWeightInGrams: 1000, RemainingWeightInGrams: 100,
+		}})
+	}
+	job := func(id, filamentID string, weight int) *models.PrintJob {
+		return &models.PrintJob{ID: id, PrinterID: "p1", FilamentID: filamentID, Filepath: "/prints/part.gcode", PrintWeightInGrams: weight}
+	}
+	f.mustApply(&models.Command{Type: models.AddPrintJob, PrintJob: job("queued", "f1", 80)})
+	f.mustApply(&models.Command{Type: models.AddPrintJob, PrintJob: job("done", "f1", 10)})
+	f.mustApply(&models.Command{Type: models.UpdatePrintJob, JobID: "done", NewStatus: "Running"})
+	f.mustApply(&models.Command{Type: models.UpdatePrintJob, JobID: "done", NewStatus: "Done"})
+
+	// Replacing a filament keeps the weight it has left, whatever the body
+	// says, so it can't drop below what the queued job reserves
+	f.mustApply(&models.Command{Type: models.UpsertFilament, Filament: &models.Filament{
+		ID: "f1", Type: "PETG", Color: "blue", TotalWeightInGrams: 1000, RemainingWeightInGrams: 5,
+	}})
+	fl, _ := f.GetFilament("f1")
+	if fl.Type != "PETG" || fl.RemainingWeightInGrams != 90 {
+		t.Errorf("replaced filament is %s with %d g left, want PETG with 90 g", fl.Type, fl.RemainingWeightInGrams)
+	}
+
+	// A queued job can't move its reservation or change its size
+	for _, replaced := range []*models.PrintJob{job("queued", "f2", 80), job("queued", "f1", 20)} {
+		err, _ := f.apply(&models.Command{Type: models.UpsertPrintJob, PrintJob: replaced}).(error)
+		if !errors.Is(err, ErrJobNotFinished) {
+			t.Errorf("replacing the queued job with %s and %d g returned %v, want %v",
+				replaced.FilamentID, replaced.PrintWeightInGrams, err, ErrJobNotFinished)
+		}
+	}
+	if got := f.ReservedGrams("f1"); got != 80 {
+		t.Errorf("filament f1 has %d g reserved, want 80 g", got)
+	}
+
+	// but can change anything else
+	replaced := job("queued", "f1", 80)
+	replaced.Filepath = "/prints/other.gcode"
+	f.mustApply(&models.Command{Type: models.UpsertPrintJob, PrintJob: replaced})
+
+	// and a finished job may change both
+	f.mustApply(&models.Command{Type: models.UpsertPrintJob, PrintJob: job("done", "f2", 500)})
+	if done, _ := f.GetPrintJob("done"); done.Status != "Done" || done.FilamentID != "f2" {
+		t.Errorf("finished job is %s on %s, want Done on f2", done.Status, done.FilamentID)
+	}
+}
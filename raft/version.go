@@ -0,0 +1,59 @@
+package raft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+// ErrVersionConflict is returned when a command expects a resource to be at
+// a version it no longer is at, because someone else changed it in the
+// meantime
+var ErrVersionConflict = errors.New("version conflict")
+
+// resourceVersion returns the version of a stored resource. Resources
+// written before versions were kept count as version 0.
+func resourceVersion(data []byte) (uint64, error) {
+	var v struct {
+		Version uint64 `json:"version"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return 0, err
+	}
+	return v.Version, nil
+}
+
+// checkExpectedVersion fails with ErrVersionConflict unless the resource a
+// command targets is at the version the command expects. AnyVersion only
+// requires it to exist.
+func checkExpectedVersion(tx stateTx, cmd *models.Command) error {
+	if cmd.ExpectedVersion == nil {
+		return nil
+	}
+
+	bucket, id := commandResource(cmd)
+	if bucket == "" {
+		return errorf(ErrValidation, "expected_version needs a command on a single resource")
+	}
+	data, err := tx.get(bucket, id)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return fmt.Errorf("%w: %s %s does not exist", ErrVersionConflict, bucket, id)
+	}
+	if *cmd.ExpectedVersion == models.AnyVersion {
+		return nil
+	}
+	version, err := resourceVersion(data)
+	if err != nil {
+		return err
+	}
+	if version != *cmd.ExpectedVersion {
+		return fmt.Errorf("%w: %s %s is at version %d, expected %d",
+			ErrVersionConflict, bucket, id, version, *cmd.ExpectedVersion)
+	}
+	return nil
+}
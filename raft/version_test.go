@@ -0,0 +1,57 @@
+package raft
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+func TestExpectedVersion(t *testing.T) {
+	f := newTestFSM(t)
+	anyVersion := models.AnyVersion
+
+	// AnyVersion doesn't create what isn't there
+	err, _ := f.apply(&models.Command{
+		Type:            models.UpsertPrinter,
+		Printer:         &models.Printer{ID: "p1", Company: "Prusa", Model: "MK4"},
+		ExpectedVersion: &anyVersion,
+	}).(error)
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("upsert of a missing printer with any version returned %v, want a version conflict", err)
+	}
+	if _, ok := f.GetPrinter("p1"); ok {
+		t.Fatal("upsert with any version created the printer")
+	}
+
+	f.mustApply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: "p1", Company: "Prusa", Model: "MK4"}})
+	created := f.index
+
+	// but replaces what is, whatever its version
+	f.mustApply(&models.Command{
+		Type:            models.UpsertPrinter,
+		Printer:         &models.Printer{ID: "p1", Company: "Prusa", Model: "MK4S"},
+		ExpectedVersion: &anyVersion,
+	})
+
+	// while an exact version must still match
+	err, _ = f.apply(&models.Command{
+		Type:            models.UpdatePrinter,
+		PrinterID:       "p1",
+		Patch:           []byte(`{"model":"XL"}`),
+		ExpectedVersion: &created,
+	}).(error)
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("update at a stale version returned %v, want a version conflict", err)
+	}
+	current := f.index - 1
+	f.mustApply(&models.Command{
+		Type:            models.UpdatePrinter,
+		PrinterID:       "p1",
+		Patch:           []byte(`{"model":"XL"}`),
+		ExpectedVersion: &current,
+	})
+	if printer, _ := f.GetPrinter("p1"); printer.Model != "XL" {
+		t.Fatalf("printer model is %s, want XL", printer.Model)
+	}
+}
@@ -0,0 +1,85 @@
+package raft
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrResyncRequired is returned when a change feed is resumed from an index
+// whose changes are no longer in the history. The client has to read the
+// current state again and watch from the index it was read at.
+var ErrResyncRequired = errors.New("resync required: changes since the requested index are no longer retained")
+
+// ChangeFeed is a batch of changes read from the history
+type ChangeFeed struct {
+	Changes []*Change
+	// LastIndex is the index the batch is complete up to, to resume from
+	LastIndex uint64
+	// Updated is closed once further changes may have been applied
+	Updated <-chan struct{}
+}
+
+// ResourceTypes returns the resource types changes are reported for
+func ResourceTypes() []string {
+	return append([]string(nil), resourceBuckets...)
+}
+
+// notify wakes up everyone waiting for changes. The caller must hold the
+// write lock.
+func (f *FSM) notify() {
+	close(f.updated)
+	f.updated = make(chan struct{})
+}
+
+// ChangesSince returns the changes applied after index to resources of the
+// given types, or of every type if types is empty. At most limit changes are
+// returned, plus any others made by the same log entry as the last one; a
+// limit of 0 returns everything.
+func (f *FSM) ChangesSince(index uint64, types []string, limit int) (*ChangeFeed, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if index < f.historyFloor {
+		return nil, ErrResyncRequired
+	}
+
+	feed := &ChangeFeed{
+		Changes:   make([]*Change, 0),
+		LastIndex: f.appliedIndex,
+		Updated:   f.updated,
+	}
+	if index >= f.appliedIndex {
+		return feed, nil
+	}
+
+	// History keys sort by index, so resuming seeks straight to the first
+	// change after index
+	err := f.state.view(func(tx stateTx) error {
+		return tx.forEachFrom(bucketHistory, historyKey(index+1, 0), func(key string, data []byte) error {
+			var change Change
+			if err := json.Unmarshal(data, &change); err != nil {
+				return err
+			}
+			if len(types) > 0 && !containsBucket(types, change.Type) {
+				return nil
+			}
+
+			// Never split the changes of one entry across batches
+			if limit > 0 && len(feed.Changes) >= limit && change.Index != feed.LastIndex {
+				return errStopIteration
+			}
+			feed.Changes = append(feed.Changes, &change)
+			if limit > 0 && len(feed.Changes) >= limit {
+				feed.LastIndex = change.Index
+			}
+			return nil
+		})
+	})
+	if err != nil && err != errStopIteration {
+		return nil, err
+	}
+	if err != errStopIteration {
+		feed.LastIndex = f.appliedIndex
+	}
+	return feed, nil
+}
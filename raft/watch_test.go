@@ -0,0 +1,64 @@
+package raft
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/devadigapratham/raft3d/api/models"
+)
+
+func TestChangesSince(t *testing.T) {
+	f := newTestFSM(t)
+	f.mustApply(&models.Command{Type: models.AddFilament, Filament: &models.Filament{
+		ID: "f1", Type: "PLA", Color: "red", TotalWeightInGrams: 1000, RemainingWeightInGrams: 1000,
+	}})
+	for i := 0; i < 5; i++ {
+		f.mustApply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: fmt.Sprintf("p%d", i), Company: "Prusa", Model: "MK4"}})
+	}
+	// One entry changing two resources
+	f.mustApply(&models.Command{Type: models.CommitTransaction, Transaction: &models.Transaction{
+		Operations: []*models.Command{
+			{Type: models.DeletePrinter, PrinterID: "p0"},
+			{Type: models.AdjustFilamentWeight, FilamentID: "f1", DeltaGrams: -10, Reason: "weighed"},
+		},
+	}})
+
+	indexes := func(feed *ChangeFeed) []uint64 {
+		var list []uint64
+		for _, c := range feed.Changes {
+			list = append(list, c.Index)
+		}
+		return list
+	}
+
+	for _, tc := range []struct {
+		since     uint64
+		types     []string
+		limit     int
+		want      []uint64
+		lastIndex uint64
+	}{
+		{0, nil, 0, []uint64{1, 2, 3, 4, 5, 6, 7, 7}, 7},
+		{4, nil, 0, []uint64{5, 6, 7, 7}, 7},
+		{4, []string{ResourceFilaments}, 0, []uint64{7}, 7},
+		{2, nil, 2, []uint64{3, 4}, 4},
+		// The changes of the last entry aren't split across batches
+		{5, nil, 2, []uint64{6, 7, 7}, 7},
+		{7, nil, 0, nil, 7},
+	} {
+		feed, err := f.ChangesSince(tc.since, tc.types, tc.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := indexes(feed); fmt.Sprint(got) != fmt.Sprint(tc.want) || feed.LastIndex != tc.lastIndex {
+			t.Errorf("since %d %v limit %d: got %v up to %d, want %v up to %d",
+				tc.since, tc.types, tc.limit, got, feed.LastIndex, tc.want, tc.lastIndex)
+		}
+	}
+
+	f.SetHistoryRetention(HistoryRetention{Indexes: 2})
+	f.mustApply(&models.Command{Type: models.AddPrinter, Printer: &models.Printer{ID: "p9", Company: "Prusa", Model: "MK4"}})
+	if _, err := f.ChangesSince(2, nil, 0); err != ErrResyncRequired {
+		t.Errorf("resuming from a pruned index returned %v", err)
+	}
+}